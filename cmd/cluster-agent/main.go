@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/cloudoperators/greenhouse/pkg/tunnel"
+	"github.com/cloudoperators/greenhouse/pkg/version"
+)
+
+const agentTokenEnv = "GREENHOUSE_AGENT_TOKEN" // used to read the token authenticating the agent, it must not be passed as flag.
+
+func main() {
+	var greenhouseURL, clusterNamespace, clusterName, apiServerAddress, caFile string
+
+	opts := zap.Options{
+		Development: true,
+		TimeEncoder: zapcore.RFC3339TimeEncoder,
+	}
+	opts.BindFlags(flag.CommandLine)
+
+	flag.StringVar(&greenhouseURL, "greenhouse-url", os.Getenv("GREENHOUSE_TUNNEL_URL"), "URL of the Greenhouse tunnel endpoint")
+	flag.StringVar(&clusterNamespace, "cluster-namespace", os.Getenv("GREENHOUSE_ORG"), "namespace of the Cluster in Greenhouse, the name of the organization")
+	flag.StringVar(&clusterName, "cluster-name", os.Getenv("GREENHOUSE_CLUSTER_NAME"), "name of the Cluster in Greenhouse")
+	flag.StringVar(&apiServerAddress, "api-server-address", defaultAPIServerAddress(), "address of the Kubernetes API server, defaults to the in-cluster address")
+	flag.StringVar(&caFile, "greenhouse-ca", "", "CA file to verify the Greenhouse tunnel endpoint, defaults to the system trust store")
+	flag.Parse()
+
+	logger := zap.New(zap.UseFlagOptions(&opts))
+	log.SetLogger(logger)
+	logger.Info("Greenhouse cluster agent", "version", version.GitCommit, "build_date", version.BuildDate, "go", version.GoVersion)
+
+	var failWithError = func(err error, message string) {
+		logger.Error(err, message)
+		os.Exit(1)
+	}
+
+	token := os.Getenv(agentTokenEnv)
+	switch {
+	case greenhouseURL == "":
+		failWithError(errors.New("--greenhouse-url must not be empty"), "invalid configuration")
+	case clusterNamespace == "" || clusterName == "":
+		failWithError(errors.New("--cluster-namespace and --cluster-name must not be empty"), "invalid configuration")
+	case apiServerAddress == "":
+		failWithError(errors.New("--api-server-address must not be empty"), "invalid configuration")
+	case token == "":
+		failWithError(errors.New(agentTokenEnv+" must not be empty"), "invalid configuration")
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		caData, err := os.ReadFile(caFile)
+		if err != nil {
+			failWithError(err, "failed to read CA file")
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caData) {
+			failWithError(errors.New("no certificates found"), "failed to parse CA file")
+		}
+	}
+
+	agent := &tunnel.Agent{
+		URL:              greenhouseURL,
+		Cluster:          types.NamespacedName{Namespace: clusterNamespace, Name: clusterName},
+		Token:            token,
+		APIServerAddress: apiServerAddress,
+		TLSConfig:        tlsConfig,
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := agent.Run(log.IntoContext(ctx, logger)); err != nil {
+		failWithError(err, "agent failed")
+	}
+}
+
+// defaultAPIServerAddress returns the address of the API server from the environment of the pod.
+func defaultAPIServerAddress() string {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return ""
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -14,6 +14,7 @@ import (
 	clustercontrollers "github.com/cloudoperators/greenhouse/pkg/controllers/cluster"
 	organizationcontrollers "github.com/cloudoperators/greenhouse/pkg/controllers/organization"
 	plugincontrollers "github.com/cloudoperators/greenhouse/pkg/controllers/plugin"
+	propagationcontrollers "github.com/cloudoperators/greenhouse/pkg/controllers/propagation"
 	teammembershipcontrollers "github.com/cloudoperators/greenhouse/pkg/controllers/teammembership"
 	teamrbaccontrollers "github.com/cloudoperators/greenhouse/pkg/controllers/teamrbac"
 	dexstore "github.com/cloudoperators/greenhouse/pkg/dex"
@@ -29,12 +30,17 @@ var knownControllers = map[string]func(controllerName string, mgr ctrl.Manager)
 
 	// Team RBAC controllers.
 	"teamRoleBindingController": (&teamrbaccontrollers.TeamRoleBindingReconciler{}).SetupWithManager,
+	"teamRoleController":        (&teamrbaccontrollers.TeamRoleReconciler{}).SetupWithManager,
+
+	// Resource propagation controllers.
+	"resourcePropagation": (&propagationcontrollers.ResourcePropagationReconciler{}).SetupWithManager,
 
 	// Plugin controllers.
 	"plugin": (&plugincontrollers.PluginReconciler{
 		KubeRuntimeOpts: kubeClientOpts,
 	}).SetupWithManager,
-	"pluginPreset": (&plugincontrollers.PluginPresetReconciler{}).SetupWithManager,
+	"pluginPreset":     (&plugincontrollers.PluginPresetReconciler{}).SetupWithManager,
+	"pluginDefinition": (&plugincontrollers.PluginDefinitionReconciler{}).SetupWithManager,
 
 	// Cluster controllers
 	"bootStrap":         (&clustercontrollers.BootstrapReconciler{}).SetupWithManager,
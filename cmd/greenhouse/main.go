@@ -17,6 +17,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -31,6 +32,7 @@ import (
 	dexapi "github.com/cloudoperators/greenhouse/pkg/dex/api"
 	"github.com/cloudoperators/greenhouse/pkg/features"
 	"github.com/cloudoperators/greenhouse/pkg/helm"
+	"github.com/cloudoperators/greenhouse/pkg/tunnel"
 	"github.com/cloudoperators/greenhouse/pkg/version"
 )
 
@@ -51,6 +53,7 @@ const (
 	disableControllersEnv                     = "WEBHOOK_ONLY"             // used to deploy the operator in webhook only mode no controllers will run in this mode.
 	disableWebhookEnv                         = "CONTROLLERS_ONLY"         // used to disable webhooks when running locally or in debug mode.
 	podNamespaceEnv                           = "POD_NAMESPACE"            // used to read the pod namespace from the environment.
+	podIPEnv                                  = "POD_IP"                   // used to read the pod IP from the environment.
 	defaultPodNamespace                       = "greenhouse"               // default pod namespace.
 	featureFlagsEnv                           = "FEATURE_FLAGS"            // used to read the feature flags configMap name from the environment.
 	defaultFeatureFlagConfigMapName           = "greenhouse-feature-flags" // default feature flags configMap name.
@@ -78,6 +81,8 @@ func init() {
 func main() {
 	flag.BoolVar(&helm.IsHelmDebug, "helm-debug", false,
 		"Enable debug logging for underlying Helm client.")
+	flag.Int64Var(&helm.ChartCacheMaxBytes, "helm-chart-cache-max-bytes", helm.ChartCacheMaxBytes,
+		"Maximum size of the Helm chart cache shared across reconciliations. A value of 0 disables the cache.")
 	flag.StringSliceVar(&enabledControllers, "controllers", knownControllersNames(),
 		"A list of controllers to enable.")
 
@@ -98,6 +103,12 @@ func main() {
 	flag.StringVar(&common.DNSDomain, "dns-domain", "",
 		"The DNS domain to use for the Greenhouse central cluster")
 
+	var tunnelAgentAddr, tunnelProxyAddr string
+	flag.StringVar(&tunnelAgentAddr, "tunnel-agent-bind-address", ":8090",
+		"The address the tunnel endpoint for agents of clusters with access mode agent binds to. Set to empty to disable.")
+	flag.StringVar(&tunnelProxyAddr, "tunnel-proxy-bind-address", ":8091",
+		"The address the cluster-internal proxy endpoint for clusters with access mode agent binds to. Set to empty to disable.")
+
 	opts := zap.Options{
 		Development: true,
 		TimeEncoder: zapcore.RFC3339TimeEncoder,
@@ -169,6 +180,22 @@ func main() {
 		}
 	}
 
+	// Run the tunnel server for clusters with access mode agent alongside the controllers.
+	if mode != webhookOnlyMode && (tunnelAgentAddr != "" || tunnelProxyAddr != "") {
+		tunnel.DefaultDialer.Registry = tunnel.NewRegistry()
+		tunnelServer := &tunnel.Server{
+			Client:           mgr.GetClient(),
+			Registry:         tunnel.DefaultDialer.Registry,
+			AgentBindAddress: tunnelAgentAddr,
+			ProxyBindAddress: tunnelProxyAddr,
+			EndpointSlice:    types.NamespacedName{Namespace: clientutil.GetEnvOrDefault(podNamespaceEnv, defaultPodNamespace), Name: tunnel.ServiceName},
+			PodIP:            os.Getenv(podIPEnv),
+		}
+		// Only the leader must receive tunnel connections. A previous leader might have been restarted without removing its endpoint.
+		handleError(tunnelServer.RemoveEndpoint(context.TODO()), "unable to remove tunnel endpoint")
+		handleError(mgr.Add(tunnelServer), "unable to add tunnel server")
+	}
+
 	// Register webhooks.
 	if mode != controllerOnlyMode {
 		for webhookName, hookFunc := range knownWebhooks {
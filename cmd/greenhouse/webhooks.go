@@ -10,13 +10,14 @@ import (
 )
 
 var knownWebhooks = map[string]func(mgr ctrl.Manager) error{
-	"cluster":          admission.SetupClusterWebhookWithManager,
-	"secrets":          admission.SetupSecretWebhookWithManager,
-	"organization":     admission.SetupOrganizationWebhookWithManager,
-	"pluginDefinition": admission.SetupPluginDefinitionWebhookWithManager,
-	"plugin":           admission.SetupPluginWebhookWithManager,
-	"pluginPreset":     admission.SetupPluginPresetWebhookWithManager,
-	"teamrole":         admission.SetupTeamRoleWebhookWithManager,
-	"teamrolebinding":  admission.SetupTeamRoleBindingWebhookWithManager,
-	"team":             admission.SetupTeamWebhookWithManager,
+	"cluster":             admission.SetupClusterWebhookWithManager,
+	"secrets":             admission.SetupSecretWebhookWithManager,
+	"organization":        admission.SetupOrganizationWebhookWithManager,
+	"pluginDefinition":    admission.SetupPluginDefinitionWebhookWithManager,
+	"plugin":              admission.SetupPluginWebhookWithManager,
+	"pluginPreset":        admission.SetupPluginPresetWebhookWithManager,
+	"teamrole":            admission.SetupTeamRoleWebhookWithManager,
+	"teamrolebinding":     admission.SetupTeamRoleBindingWebhookWithManager,
+	"team":                admission.SetupTeamWebhookWithManager,
+	"resourcePropagation": admission.SetupResourcePropagationWebhookWithManager,
 }
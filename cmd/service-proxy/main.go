@@ -27,7 +27,9 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	greenhousesapv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
 	greenhousehealthz "github.com/cloudoperators/greenhouse/pkg/healthz"
+	"github.com/cloudoperators/greenhouse/pkg/tunnel"
 	"github.com/cloudoperators/greenhouse/pkg/version"
 )
 
@@ -53,6 +55,8 @@ func main() {
 	flag.StringVar(&listenAddr, "listen-addr", ":8080", "proxy listen address")
 	flag.StringVar(&metricsAddr, "metrics-addr", ":6543", "bind address for metrics")
 	flag.StringVar(&healthzAddr, "healz-addr", ":8081", "bind address for health checks")
+	flag.StringVar(&tunnel.DefaultDialer.ProxyAddress, "tunnel-proxy-address", clientutil.GetEnvOrDefault("TUNNEL_PROXY_ADDRESS", tunnel.DefaultProxyAddress), "address of the Greenhouse tunnel proxy to access clusters with access mode agent")
+	flag.StringVar(&tunnel.DefaultDialer.TokenFile, "tunnel-proxy-token-file", tunnel.DefaultTokenFile, "file containing the ServiceAccount token to authenticate against the Greenhouse tunnel proxy")
 	flag.Parse()
 
 	k8sConfig, err := ctrlconfig.GetConfigWithContext(kubecontext)
@@ -103,6 +103,10 @@ func ValidateCreateCluster(ctx context.Context, _ client.Client, obj runtime.Obj
 		logger.Error(err, "found deletion annotation on cluster creation, admission will be denied")
 		return admission.Warnings{"you cannot create a cluster with deletion annotation"}, err
 	}
+	if err := validateClusterMaintenance(cluster); err != nil {
+		logger.Error(err, "create request denied", "cluster", cluster.GetName())
+		return nil, err
+	}
 
 	return nil, nil
 }
@@ -120,9 +124,34 @@ func ValidateUpdateCluster(ctx context.Context, _ client.Client, _, currObj runt
 		logger.Error(err, "update request denied", "cluster", cluster.GetName())
 		return admission.Warnings{"update is not allowed"}, err
 	}
+	if err := validateClusterMaintenance(cluster); err != nil {
+		logger.Error(err, "update request denied", "cluster", cluster.GetName())
+		return admission.Warnings{"update is not allowed"}, err
+	}
 	return nil, nil
 }
 
+// validateClusterMaintenance ensures the maintenance windows have a valid cron schedule and a positive duration.
+func validateClusterMaintenance(cluster *greenhousev1alpha1.Cluster) error {
+	if cluster.Spec.Maintenance == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	windowsPath := field.NewPath("spec", "maintenance", "windows")
+	for i, window := range cluster.Spec.Maintenance.Windows {
+		if _, err := clientutil.ParseMaintenanceSchedule(window.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowsPath.Index(i).Child("schedule"), window.Schedule, err.Error()))
+		}
+		if window.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(windowsPath.Index(i).Child("duration"), window.Duration.String(), "must be positive"))
+		}
+	}
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(cluster.GroupVersionKind().GroupKind(), cluster.GetName(), allErrs)
+	}
+	return nil
+}
+
 // ValidateDeleteCluster only allows deletion requests for clusters with a deletion schedule timestamp past now.
 func ValidateDeleteCluster(ctx context.Context, _ client.Client, obj runtime.Object) (admission.Warnings, error) {
 	now := time.Now()
@@ -163,9 +192,23 @@ func ValidateDeleteCluster(ctx context.Context, _ client.Client, obj runtime.Obj
 	}
 	if canDelete {
 		logger.Info("deletion request allowed", "cluster", cluster.GetName())
-		return nil, nil
+		return deletionReportWarnings(cluster), nil
 	}
 	err = apierrors.NewForbidden(groupResource, cluster.GetName(), errors.New("deletion scheduled at - "+schedule.String()))
 	logger.Error(err, "deletion request denied", "cluster", cluster.GetName())
 	return admission.Warnings{"deletion is not allowed"}, err
 }
+
+// deletionReportWarnings returns a warning summarizing the resources affected by the deletion of the cluster.
+func deletionReportWarnings(cluster *greenhousev1alpha1.Cluster) admission.Warnings {
+	report := cluster.Status.DeletionReport
+	if report == nil {
+		return nil
+	}
+	action := "uninstalled from"
+	if report.DeletionPolicy == greenhousev1alpha1.ClusterDeletionPolicyForget {
+		action = "forgotten without uninstalling them from"
+	}
+	return admission.Warnings{fmt.Sprintf("%d Plugins and the RBAC of %d TeamRoleBindings are %s the cluster, %d PluginPresets stop deploying to it",
+		len(report.Plugins), len(report.TeamRoleBindings), action, len(report.PluginPresets))}
+}
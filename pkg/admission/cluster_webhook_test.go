@@ -244,6 +244,57 @@ var _ = Describe("Cluster Webhook", func() {
 			},
 			true,
 		),
+		Entry("it should allow update with valid maintenance windows",
+			&greenhousev1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: greenhousev1alpha1.ClusterSpec{
+					AccessMode: greenhousev1alpha1.ClusterAccessModeDirect,
+					Maintenance: &greenhousev1alpha1.ClusterMaintenance{
+						Windows: []greenhousev1alpha1.MaintenanceWindow{
+							{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+						},
+					},
+				},
+			},
+			false,
+		),
+		Entry("it should deny update with invalid maintenance schedule",
+			&greenhousev1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: greenhousev1alpha1.ClusterSpec{
+					AccessMode: greenhousev1alpha1.ClusterAccessModeDirect,
+					Maintenance: &greenhousev1alpha1.ClusterMaintenance{
+						Windows: []greenhousev1alpha1.MaintenanceWindow{
+							{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+						},
+					},
+				},
+			},
+			true,
+		),
+		Entry("it should deny update with maintenance window without duration",
+			&greenhousev1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: greenhousev1alpha1.ClusterSpec{
+					AccessMode: greenhousev1alpha1.ClusterAccessModeDirect,
+					Maintenance: &greenhousev1alpha1.ClusterMaintenance{
+						Windows: []greenhousev1alpha1.MaintenanceWindow{
+							{Schedule: "0 22 * * 6"},
+						},
+					},
+				},
+			},
+			true,
+		),
 	)
 
 	DescribeTable("Validate Delete Cluster",
@@ -232,7 +232,7 @@ func validatePluginOptionValues(
 
 func validatePluginForCluster(ctx context.Context, c client.Client, plugin *greenhousev1alpha1.Plugin, pluginDefinition *greenhousev1alpha1.PluginDefinition) error {
 	// Exclude whitelisted and front-end only Plugins as well as the greenhouse namespace from the below check.
-	if slices.Contains(pluginsAllowedInCentralCluster, plugin.Spec.PluginDefinition) || pluginDefinition.Spec.HelmChart == nil || plugin.GetNamespace() == "greenhouse" {
+	if slices.Contains(pluginsAllowedInCentralCluster, plugin.Spec.PluginDefinition) || pluginDefinition.Spec.ChartReference() == nil || plugin.GetNamespace() == "greenhouse" {
 		return nil
 	}
 
@@ -5,6 +5,10 @@ package admission
 
 import (
 	"context"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -50,6 +54,12 @@ func ValidateCreatePluginDefinition(_ context.Context, _ client.Client, o runtim
 	if err := validatePluginDefinitionMustSpecifyVersion(pluginDefinition); err != nil {
 		return nil, err
 	}
+	if err := validatePluginDefinitionHelmChartReference(pluginDefinition); err != nil {
+		return nil, err
+	}
+	if err := validatePluginDefinitionManifestReference(pluginDefinition); err != nil {
+		return nil, err
+	}
 	return nil, validatePluginDefinitionOptionValueAndType(pluginDefinition)
 }
 
@@ -64,6 +74,12 @@ func ValidateUpdatePluginDefinition(_ context.Context, _ client.Client, _, o run
 	if err := validatePluginDefinitionMustSpecifyVersion(pluginDefinition); err != nil {
 		return nil, err
 	}
+	if err := validatePluginDefinitionHelmChartReference(pluginDefinition); err != nil {
+		return nil, err
+	}
+	if err := validatePluginDefinitionManifestReference(pluginDefinition); err != nil {
+		return nil, err
+	}
 	return nil, validatePluginDefinitionOptionValueAndType(pluginDefinition)
 }
 
@@ -94,15 +110,102 @@ func validatePluginDefinitionMustSpecifyVersion(pluginDefinition *greenhousev1al
 }
 
 func validatePluginDefinitionMustSpecifyHelmChartOrUIApplication(pluginDefinition *greenhousev1alpha1.PluginDefinition) error {
-	if pluginDefinition.Spec.HelmChart == nil && pluginDefinition.Spec.UIApplication == nil {
+	if pluginDefinition.Spec.HelmChart == nil && pluginDefinition.Spec.Manifests == nil && pluginDefinition.Spec.UIApplication == nil {
 		return apierrors.NewInvalid(pluginDefinition.GroupVersionKind().GroupKind(), pluginDefinition.GetName(), field.ErrorList{
 			field.Required(field.NewPath("spec").Child("helmChart", "uiApplication"),
-				"A PluginDefinition without both spec.helmChart and spec.uiApplication is invalid."),
+				"A PluginDefinition without spec.helmChart, spec.manifests and spec.uiApplication is invalid."),
 		})
 	}
+	if pluginDefinition.Spec.HelmChart != nil && pluginDefinition.Spec.Manifests != nil {
+		return apierrors.NewInvalid(pluginDefinition.GroupVersionKind().GroupKind(), pluginDefinition.GetName(), field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "manifests"), "A PluginDefinition must not specify both spec.helmChart and spec.manifests."),
+		})
+	}
+	return nil
+}
+
+// validatePluginDefinitionHelmChartReference validates that the HelmChart references either a chart repository or a Git repository.
+func validatePluginDefinitionHelmChartReference(pluginDefinition *greenhousev1alpha1.PluginDefinition) error {
+	helmChart := pluginDefinition.Spec.HelmChart
+	if helmChart == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	helmChartPath := field.NewPath("spec", "helmChart")
+	if helmChart.Git == nil {
+		if helmChart.Name == "" {
+			allErrs = append(allErrs, field.Required(helmChartPath.Child("name"), "A HelmChart not sourced from Git must specify a name."))
+		}
+		if helmChart.Repository == "" {
+			allErrs = append(allErrs, field.Required(helmChartPath.Child("repository"), "A HelmChart not sourced from Git must specify a repository."))
+		}
+		if helmChart.Version == "" {
+			allErrs = append(allErrs, field.Required(helmChartPath.Child("version"), "A HelmChart not sourced from Git must specify a version."))
+		}
+	} else {
+		allErrs = append(allErrs, validateGitChartSource(helmChartPath.Child("git"), helmChart.Git)...)
+	}
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(pluginDefinition.GroupVersionKind().GroupKind(), pluginDefinition.GetName(), allErrs)
+	}
+	return nil
+}
+
+// validatePluginDefinitionManifestReference validates the Git source of the Manifests and that replacements reference known PluginOptions.
+func validatePluginDefinitionManifestReference(pluginDefinition *greenhousev1alpha1.PluginDefinition) error {
+	manifests := pluginDefinition.Spec.Manifests
+	if manifests == nil {
+		return nil
+	}
+	manifestsPath := field.NewPath("spec", "manifests")
+	allErrs := validateGitChartSource(manifestsPath.Child("git"), &manifests.Git)
+	for idx, replacement := range manifests.Replacements {
+		replacementPath := manifestsPath.Child("replacements").Index(idx)
+		if !slices.ContainsFunc(pluginDefinition.Spec.Options, func(o greenhousev1alpha1.PluginOption) bool { return o.Name == replacement.Option }) {
+			allErrs = append(allErrs, field.NotFound(replacementPath.Child("option"), replacement.Option))
+		}
+		if len(replacement.Targets) == 0 {
+			allErrs = append(allErrs, field.Required(replacementPath.Child("targets"), "A replacement must specify at least one target."))
+		}
+		for targetIdx, target := range replacement.Targets {
+			if len(target.FieldPaths) == 0 {
+				allErrs = append(allErrs, field.Required(replacementPath.Child("targets").Index(targetIdx).Child("fieldPaths"), "A replacement target must specify at least one field path."))
+			}
+		}
+	}
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(pluginDefinition.GroupVersionKind().GroupKind(), pluginDefinition.GetName(), allErrs)
+	}
 	return nil
 }
 
+// gitCommitSHARegex matches full SHA-1 and SHA-256 commit hashes. Abbreviated hashes cannot be checked out without resolving them against the repository.
+var gitCommitSHARegex = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// validateGitChartSource validates that the Git source specifies a URL, at most one reference and a path within the repository.
+func validateGitChartSource(gitPath *field.Path, source *greenhousev1alpha1.GitChartSource) field.ErrorList {
+	var allErrs field.ErrorList
+	if source.URL == "" {
+		allErrs = append(allErrs, field.Required(gitPath.Child("url"), "A Git source must specify a URL."))
+	}
+	refCount := 0
+	for _, ref := range []string{source.Ref.Branch, source.Ref.Tag, source.Ref.Commit} {
+		if ref != "" {
+			refCount++
+		}
+	}
+	if refCount > 1 {
+		allErrs = append(allErrs, field.Invalid(gitPath.Child("ref"), source.Ref, "Only one of branch, tag or commit may be specified."))
+	}
+	if source.Ref.Commit != "" && !gitCommitSHARegex.MatchString(source.Ref.Commit) {
+		allErrs = append(allErrs, field.Invalid(gitPath.Child("ref", "commit"), source.Ref.Commit, "The commit must be a full SHA-1 or SHA-256 hash in lowercase hexadecimal."))
+	}
+	if source.Path != "" && (filepath.IsAbs(source.Path) || slices.Contains(strings.Split(filepath.ToSlash(source.Path), "/"), "..")) {
+		allErrs = append(allErrs, field.Invalid(gitPath.Child("path"), source.Path, "The path must be relative to the root of the repository."))
+	}
+	return allErrs
+}
+
 // validatePluginDefinitionOptionValueAndType validates that the type and value of each PluginOption matches.
 func validatePluginDefinitionOptionValueAndType(pluginDefinition *greenhousev1alpha1.PluginDefinition) error {
 	for _, option := range pluginDefinition.Spec.Options {
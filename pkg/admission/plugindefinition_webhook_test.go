@@ -192,3 +192,81 @@ var _ = Describe("Validate PluginDefinition Deletion", func() {
 		Expect(err).To(HaveOccurred(), "there should be an error deleting the PluginDefinition when Plugins still exist")
 	})
 })
+
+var _ = DescribeTable("Validate PluginDefinition HelmChart reference", func(helmChart *greenhousev1alpha1.HelmChartReference, expErr bool) {
+	pluginDefinition := &greenhousev1alpha1.PluginDefinition{
+		Spec: greenhousev1alpha1.PluginDefinitionSpec{
+			HelmChart: helmChart,
+		},
+	}
+	actErr := validatePluginDefinitionHelmChartReference(pluginDefinition)
+	switch expErr {
+	case false:
+		Expect(actErr).ToNot(HaveOccurred(), "unexpected error occurred")
+	default:
+		var err *apierrors.StatusError
+		Expect(errors.As(actErr, &err)).To(BeTrue(), "expected an *apierrors.StatusError, got %T", actErr)
+
+		Expect(err.ErrStatus.Reason).To(Equal(metav1.StatusReasonInvalid), "expected an error with reason %s, got %s", metav1.StatusReasonInvalid, err.ErrStatus)
+	}
+},
+	Entry("Chart repository", &greenhousev1alpha1.HelmChartReference{Name: "chart", Repository: "oci://registry", Version: "1.0.0"}, false),
+	Entry("Chart repository without version", &greenhousev1alpha1.HelmChartReference{Name: "chart", Repository: "oci://registry"}, true),
+	Entry("Git repository", &greenhousev1alpha1.HelmChartReference{Git: &greenhousev1alpha1.GitChartSource{URL: "https://example.com/charts.git", Path: "charts/chart"}}, false),
+	Entry("Git repository with tag", &greenhousev1alpha1.HelmChartReference{Git: &greenhousev1alpha1.GitChartSource{URL: "https://example.com/charts.git", Ref: greenhousev1alpha1.GitReference{Tag: "v1.0.0"}}}, false),
+	Entry("Git repository with branch and tag", &greenhousev1alpha1.HelmChartReference{Git: &greenhousev1alpha1.GitChartSource{URL: "https://example.com/charts.git", Ref: greenhousev1alpha1.GitReference{Branch: "main", Tag: "v1.0.0"}}}, true),
+	Entry("Git repository with path outside of the repository", &greenhousev1alpha1.HelmChartReference{Git: &greenhousev1alpha1.GitChartSource{URL: "https://example.com/charts.git", Path: "charts/../../chart"}}, true),
+	Entry("Git repository with absolute path", &greenhousev1alpha1.HelmChartReference{Git: &greenhousev1alpha1.GitChartSource{URL: "https://example.com/charts.git", Path: "/chart"}}, true),
+	Entry("Git repository with commit", &greenhousev1alpha1.HelmChartReference{Git: &greenhousev1alpha1.GitChartSource{URL: "https://example.com/charts.git", Ref: greenhousev1alpha1.GitReference{Commit: "0123456789abcdef0123456789abcdef01234567"}}}, false),
+	Entry("Git repository with abbreviated commit", &greenhousev1alpha1.HelmChartReference{Git: &greenhousev1alpha1.GitChartSource{URL: "https://example.com/charts.git", Ref: greenhousev1alpha1.GitReference{Commit: "0123456"}}}, true),
+	Entry("Git repository with malformed commit", &greenhousev1alpha1.HelmChartReference{Git: &greenhousev1alpha1.GitChartSource{URL: "https://example.com/charts.git", Ref: greenhousev1alpha1.GitReference{Commit: "../0123456789abcdef0123456789abcdef0123456"}}}, true),
+)
+
+var _ = DescribeTable("Validate PluginDefinition Manifests reference", func(manifests *greenhousev1alpha1.ManifestReference, expErr bool) {
+	pluginDefinition := &greenhousev1alpha1.PluginDefinition{
+		Spec: greenhousev1alpha1.PluginDefinitionSpec{
+			Manifests: manifests,
+			Options: []greenhousev1alpha1.PluginOption{
+				{
+					Name: "replicas",
+					Type: greenhousev1alpha1.PluginOptionTypeInt,
+				},
+			},
+		},
+	}
+	actErr := validatePluginDefinitionManifestReference(pluginDefinition)
+	switch expErr {
+	case false:
+		Expect(actErr).ToNot(HaveOccurred(), "unexpected error occurred")
+	default:
+		var err *apierrors.StatusError
+		Expect(errors.As(actErr, &err)).To(BeTrue(), "expected an *apierrors.StatusError, got %T", actErr)
+
+		Expect(err.ErrStatus.Reason).To(Equal(metav1.StatusReasonInvalid), "expected an error with reason %s, got %s", metav1.StatusReasonInvalid, err.ErrStatus)
+	}
+},
+	Entry("Manifests", &greenhousev1alpha1.ManifestReference{Git: greenhousev1alpha1.GitChartSource{URL: "https://example.com/manifests.git", Path: "overlays/prod"}}, false),
+	Entry("Manifests without URL", &greenhousev1alpha1.ManifestReference{Git: greenhousev1alpha1.GitChartSource{Path: "overlays/prod"}}, true),
+	Entry("Manifests with path outside of the repository", &greenhousev1alpha1.ManifestReference{Git: greenhousev1alpha1.GitChartSource{URL: "https://example.com/manifests.git", Path: "../prod"}}, true),
+	Entry("Manifests with replacement", &greenhousev1alpha1.ManifestReference{
+		Git: greenhousev1alpha1.GitChartSource{URL: "https://example.com/manifests.git"},
+		Replacements: []greenhousev1alpha1.ManifestReplacement{{
+			Option:  "replicas",
+			Targets: []greenhousev1alpha1.ManifestReplacementTarget{{Select: greenhousev1alpha1.ManifestObjectSelector{Kind: "Deployment"}, FieldPaths: []string{"spec.replicas"}}},
+		}},
+	}, false),
+	Entry("Manifests with replacement of an unknown option", &greenhousev1alpha1.ManifestReference{
+		Git: greenhousev1alpha1.GitChartSource{URL: "https://example.com/manifests.git"},
+		Replacements: []greenhousev1alpha1.ManifestReplacement{{
+			Option:  "unknown",
+			Targets: []greenhousev1alpha1.ManifestReplacementTarget{{Select: greenhousev1alpha1.ManifestObjectSelector{Kind: "Deployment"}, FieldPaths: []string{"spec.replicas"}}},
+		}},
+	}, true),
+	Entry("Manifests with replacement without field paths", &greenhousev1alpha1.ManifestReference{
+		Git: greenhousev1alpha1.GitChartSource{URL: "https://example.com/manifests.git"},
+		Replacements: []greenhousev1alpha1.ManifestReplacement{{
+			Option:  "replicas",
+			Targets: []greenhousev1alpha1.ManifestReplacementTarget{{Select: greenhousev1alpha1.ManifestObjectSelector{Kind: "Deployment"}}},
+		}},
+	}, true),
+)
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package admission
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+// Webhook for the ResourcePropagation custom resource.
+
+func SetupResourcePropagationWebhookWithManager(mgr ctrl.Manager) error {
+	return setupWebhook(mgr,
+		&greenhousev1alpha1.ResourcePropagation{},
+		webhookFuncs{
+			defaultFunc:        DefaultResourcePropagation,
+			validateCreateFunc: ValidateCreateResourcePropagation,
+			validateUpdateFunc: ValidateUpdateResourcePropagation,
+			validateDeleteFunc: ValidateDeleteResourcePropagation,
+		},
+	)
+}
+
+//+kubebuilder:webhook:path=/mutate-greenhouse-sap-v1alpha1-resourcepropagation,mutating=true,failurePolicy=fail,sideEffects=None,groups=greenhouse.sap,resources=resourcepropagations,verbs=create;update,versions=v1alpha1,name=mresourcepropagation.kb.io,admissionReviewVersions=v1
+
+func DefaultResourcePropagation(_ context.Context, _ client.Client, _ runtime.Object) error {
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-greenhouse-sap-v1alpha1-resourcepropagation,mutating=false,failurePolicy=fail,sideEffects=None,groups=greenhouse.sap,resources=resourcepropagations,verbs=create;update,versions=v1alpha1,name=vresourcepropagation.kb.io,admissionReviewVersions=v1
+
+func ValidateCreateResourcePropagation(_ context.Context, _ client.Client, o runtime.Object) (admission.Warnings, error) {
+	rp, ok := o.(*greenhousev1alpha1.ResourcePropagation)
+	if !ok {
+		return nil, nil
+	}
+	return nil, validateResourcePropagation(rp)
+}
+
+func ValidateUpdateResourcePropagation(_ context.Context, _ client.Client, _, cur runtime.Object) (admission.Warnings, error) {
+	rp, ok := cur.(*greenhousev1alpha1.ResourcePropagation)
+	if !ok {
+		return nil, nil
+	}
+	return nil, validateResourcePropagation(rp)
+}
+
+func ValidateDeleteResourcePropagation(_ context.Context, _ client.Client, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateResourcePropagation ensures objects are selected, the selectors are valid and the target namespaces are valid namespace names.
+func validateResourcePropagation(rp *greenhousev1alpha1.ResourcePropagation) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if rp.Spec.Secrets.IsEmpty() && rp.Spec.ConfigMaps.IsEmpty() {
+		allErrs = append(allErrs, field.Required(specPath, "at least one of spec.secrets or spec.configMaps must select objects"))
+	}
+	allErrs = append(allErrs, validatePropagatedObjectSelector(rp.Spec.Secrets, specPath.Child("secrets"))...)
+	allErrs = append(allErrs, validatePropagatedObjectSelector(rp.Spec.ConfigMaps, specPath.Child("configMaps"))...)
+	if _, err := metav1.LabelSelectorAsSelector(&rp.Spec.ClusterSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("clusterSelector"), rp.Spec.ClusterSelector, err.Error()))
+	}
+	if len(rp.Spec.Namespaces) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("namespaces"), "at least one namespace is required"))
+	}
+	for i, namespace := range rp.Spec.Namespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("namespaces").Index(i), namespace, msg))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(rp.GroupVersionKind().GroupKind(), rp.GetName(), allErrs)
+}
+
+func validatePropagatedObjectSelector(selector greenhousev1alpha1.PropagatedObjectSelector, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if selector.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector.LabelSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("labelSelector"), selector.LabelSelector, err.Error()))
+		}
+	}
+	for i, name := range selector.Names {
+		if name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("names").Index(i), "name must not be empty"))
+		}
+	}
+	return allErrs
+}
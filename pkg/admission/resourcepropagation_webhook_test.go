@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package admission
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+var _ = DescribeTable("Validate ResourcePropagation", func(spec greenhousev1alpha1.ResourcePropagationSpec, expErr bool) {
+	rp := &greenhousev1alpha1.ResourcePropagation{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-propagation", Namespace: "test-org"},
+		Spec:       spec,
+	}
+	actErr := validateResourcePropagation(rp)
+	switch expErr {
+	case false:
+		Expect(actErr).ToNot(HaveOccurred(), "unexpected error occurred")
+	default:
+		var err *apierrors.StatusError
+		Expect(errors.As(actErr, &err)).To(BeTrue(), "expected an *apierrors.StatusError, got %T", actErr)
+
+		Expect(err.ErrStatus.Reason).To(Equal(metav1.StatusReasonInvalid), "expected an error with reason %s, got %s", metav1.StatusReasonInvalid, err.ErrStatus)
+	}
+},
+	Entry("Secrets by name", greenhousev1alpha1.ResourcePropagationSpec{
+		Secrets:    greenhousev1alpha1.PropagatedObjectSelector{Names: []string{"pull-secret"}},
+		Namespaces: []string{"default"},
+	}, false),
+	Entry("ConfigMaps by label", greenhousev1alpha1.ResourcePropagationSpec{
+		ConfigMaps: greenhousev1alpha1.PropagatedObjectSelector{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"propagate": "true"}}},
+		Namespaces: []string{"default", "kube-system"},
+	}, false),
+	Entry("No objects selected", greenhousev1alpha1.ResourcePropagationSpec{
+		Namespaces: []string{"default"},
+	}, true),
+	Entry("Invalid label selector", greenhousev1alpha1.ResourcePropagationSpec{
+		Secrets: greenhousev1alpha1.PropagatedObjectSelector{LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "propagate", Operator: "Unknown"},
+		}}},
+		Namespaces: []string{"default"},
+	}, true),
+	Entry("Empty name", greenhousev1alpha1.ResourcePropagationSpec{
+		Secrets:    greenhousev1alpha1.PropagatedObjectSelector{Names: []string{""}},
+		Namespaces: []string{"default"},
+	}, true),
+	Entry("No namespaces", greenhousev1alpha1.ResourcePropagationSpec{
+		Secrets: greenhousev1alpha1.PropagatedObjectSelector{Names: []string{"pull-secret"}},
+	}, true),
+	Entry("Invalid namespace", greenhousev1alpha1.ResourcePropagationSpec{
+		Secrets:    greenhousev1alpha1.PropagatedObjectSelector{Names: []string{"pull-secret"}},
+		Namespaces: []string{"Not_A_Namespace"},
+	}, true),
+)
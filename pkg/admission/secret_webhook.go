@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
 	"github.com/cloudoperators/greenhouse/pkg/clientutil"
 )
 
@@ -47,6 +48,9 @@ func ValidateCreateSecret(ctx context.Context, _ client.Client, o runtime.Object
 	if !ok {
 		return nil, nil
 	}
+	if err := validateConnectionSettings(secret); err != nil {
+		return nil, err
+	}
 	if secret.Type == greenhouseapis.SecretTypeOIDCConfig {
 		err := validateGreenhouseOIDCType(secret)
 		return nil, err
@@ -62,6 +66,9 @@ func ValidateUpdateSecret(ctx context.Context, _ client.Client, _, o runtime.Obj
 	if !ok {
 		return nil, nil
 	}
+	if err := validateConnectionSettings(secret); err != nil {
+		return nil, err
+	}
 	if secret.Type == greenhouseapis.SecretTypeOIDCConfig {
 		err := validateGreenhouseOIDCType(secret)
 		return nil, err
@@ -97,6 +104,43 @@ func validateSecretGreenHouseType(ctx context.Context, secret *corev1.Secret) er
 				"This type of secrets without Data.kubeconfig is invalid."),
 		})
 	}
+	// Check the access mode and that an agent can authenticate if the cluster is accessed through it
+	if accessMode, ok := secret.GetAnnotations()[greenhouseapis.SecretClusterAccessModeAnnotation]; ok {
+		accessModePath := field.NewPath("metadata", "annotations").Key(greenhouseapis.SecretClusterAccessModeAnnotation)
+		switch greenhousev1alpha1.ClusterAccessMode(accessMode) {
+		case greenhousev1alpha1.ClusterAccessModeDirect:
+		case greenhousev1alpha1.ClusterAccessModeAgent:
+			if !clientutil.IsSecretContainsKey(secret, greenhouseapis.AgentTokenKey) {
+				return apierrors.NewInvalid(secret.GroupVersionKind().GroupKind(), secret.GetName(), field.ErrorList{
+					field.Required(field.NewPath("data").Child(greenhouseapis.AgentTokenKey), "Secrets of clusters with access mode agent require an agent token."),
+				})
+			}
+		default:
+			return apierrors.NewInvalid(secret.GroupVersionKind().GroupKind(), secret.GetName(), field.ErrorList{
+				field.NotSupported(accessModePath, accessMode, []string{string(greenhousev1alpha1.ClusterAccessModeDirect), string(greenhousev1alpha1.ClusterAccessModeAgent)}),
+			})
+		}
+	}
+	return nil
+}
+
+// validateConnectionSettings validates the proxy and TLS settings of cluster secrets.
+func validateConnectionSettings(secret *corev1.Secret) error {
+	if secret.Type != greenhouseapis.SecretTypeKubeConfig && secret.Type != greenhouseapis.SecretTypeOIDCConfig {
+		return nil
+	}
+	if _, err := clientutil.ConnectionSettingsFromSecret(secret); err != nil {
+		return apierrors.NewInvalid(secret.GroupVersionKind().GroupKind(), secret.GetName(), field.ErrorList{
+			field.Invalid(field.NewPath("metadata", "annotations"), secret.GetAnnotations(), err.Error()),
+		})
+	}
+	// The agent connects to the API server itself, a proxy would be dialed through the tunnel.
+	annotations := secret.GetAnnotations()
+	if annotations[greenhouseapis.SecretProxyURLAnnotation] != "" && annotations[greenhouseapis.SecretClusterAccessModeAnnotation] == string(greenhousev1alpha1.ClusterAccessModeAgent) {
+		return apierrors.NewInvalid(secret.GroupVersionKind().GroupKind(), secret.GetName(), field.ErrorList{
+			field.Forbidden(field.NewPath("metadata", "annotations").Key(greenhouseapis.SecretProxyURLAnnotation), "A proxy is not supported for clusters with access mode agent."),
+		})
+	}
 	return nil
 }
 
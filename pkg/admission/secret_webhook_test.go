@@ -108,4 +108,35 @@ var _ = Describe("Validate Secret Creation based on type", func() {
 		Entry("Valid APIServerURL with valid base64 certificate", map[string]string{greenhouseapis.SecretAPIServerURLAnnotation: "https://example.com"}, []byte(base64.StdEncoding.EncodeToString([]byte("valid-cert"))), false),
 	)
 
+	DescribeTable("Validate cluster access mode of kubeconfig secrets",
+		func(accessMode string, withAgentToken bool, expErr bool) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-cluster",
+					Namespace:   corev1.NamespaceDefault,
+					Annotations: map[string]string{greenhouseapis.SecretClusterAccessModeAnnotation: accessMode},
+				},
+				Type: greenhouseapis.SecretTypeKubeConfig,
+				Data: map[string][]byte{
+					greenhouseapis.KubeConfigKey: test.KubeConfig,
+				},
+			}
+			if withAgentToken {
+				secret.Data[greenhouseapis.AgentTokenKey] = []byte("token")
+			}
+
+			err := validateSecretGreenHouseType(test.Ctx, secret)
+
+			if expErr {
+				Expect(err).To(HaveOccurred(), "expected an error, but got nil")
+			} else {
+				Expect(err).ToNot(HaveOccurred(), "expected no error, but got %v", err)
+			}
+		},
+		Entry("access mode direct", "direct", false, false),
+		Entry("access mode agent with agent token", "agent", true, false),
+		Entry("access mode agent without agent token", "agent", false, true),
+		Entry("unknown access mode", "tunnel", true, true),
+	)
+
 })
@@ -5,6 +5,7 @@ package admission
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -16,6 +17,7 @@ import (
 
 	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
 	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/rbac"
 )
 
 // Webhook for the Team custom resource.
@@ -48,7 +50,10 @@ func ValidateCreateTeam(ctx context.Context, c client.Client, o runtime.Object)
 	if err := validateGreenhouseLabels(team, ctx, c); err != nil {
 		return nil, err
 	}
-	return nil, validateJoinURL(team)
+	if err := validateJoinURL(team); err != nil {
+		return nil, err
+	}
+	return validateParentTeam(ctx, c, team)
 }
 
 func ValidateUpdateTeam(ctx context.Context, c client.Client, _, o runtime.Object) (admission.Warnings, error) {
@@ -59,7 +64,10 @@ func ValidateUpdateTeam(ctx context.Context, c client.Client, _, o runtime.Objec
 	if err := validateGreenhouseLabels(team, ctx, c); err != nil {
 		return nil, err
 	}
-	return nil, validateJoinURL(team)
+	if err := validateJoinURL(team); err != nil {
+		return nil, err
+	}
+	return validateParentTeam(ctx, c, team)
 }
 
 func ValidateDeleteTeam(_ context.Context, _ client.Client, _ runtime.Object) (admission.Warnings, error) {
@@ -108,3 +116,24 @@ func validateJoinURL(team *greenhousev1alpha1.Team) error {
 	}
 	return nil
 }
+
+// validateParentTeam rejects a Team referencing itself as a parent or ancestor, and warns about a parent Team that does not exist.
+func validateParentTeam(ctx context.Context, c client.Client, team *greenhousev1alpha1.Team) (admission.Warnings, error) {
+	if team.Spec.ParentTeamRef == "" {
+		return nil, nil
+	}
+	teams := new(greenhousev1alpha1.TeamList)
+	if err := c.List(ctx, teams, client.InNamespace(team.GetNamespace())); err != nil {
+		return nil, err
+	}
+	if rbac.HasTeamCycle(team, teams.Items) {
+		return nil, apierrors.NewInvalid(team.GroupVersionKind().GroupKind(), team.GetName(), field.ErrorList{
+			field.Invalid(field.NewPath("spec").Child("parentTeamRef"), team.Spec.ParentTeamRef,
+				"the parent Team references must not form a cycle"),
+		})
+	}
+	if !slices.ContainsFunc(teams.Items, func(t greenhousev1alpha1.Team) bool { return t.GetName() == team.Spec.ParentTeamRef }) {
+		return admission.Warnings{"parent Team " + team.Spec.ParentTeamRef + " does not exist"}, nil
+	}
+	return nil, nil
+}
@@ -154,5 +154,27 @@ var _ = Describe("Validate Team Creation", func() {
 			Expect(err).To(HaveOccurred(), "There should be an error when updating a team with invalid JoinURL")
 			Expect(err.Error()).To(ContainSubstring("JoinURL must be a valid 'http:' or 'https:' URL, like 'https://example.com'."))
 		})
+		By("correctly allowing create of a team with a parent team", func() {
+			teamChild := teamStub
+			teamChild.SetName("child-team")
+			teamChild.Spec.ParentTeamRef = teamValidJoinURL.GetName()
+			err := test.K8sClient.Create(test.Ctx, &teamChild)
+			Expect(err).ToNot(HaveOccurred(), "There should be no error when creating a team with a parent team")
+		})
+		By("correctly denying update of a team with a parent team forming a cycle", func() {
+			teamValidJoinURL.Spec.JoinURL = "https://example.com/resource"
+			teamValidJoinURL.Spec.ParentTeamRef = "child-team"
+			err := test.K8sClient.Update(test.Ctx, &teamValidJoinURL)
+			Expect(err).To(HaveOccurred(), "There should be an error when updating a team with a parent team forming a cycle")
+			Expect(err.Error()).To(ContainSubstring("the parent Team references must not form a cycle"))
+		})
+		By("correctly denying create of a team referencing itself as parent team", func() {
+			teamSelfParent := teamStub
+			teamSelfParent.SetName("self-parent")
+			teamSelfParent.Spec.ParentTeamRef = "self-parent"
+			err := test.K8sClient.Create(test.Ctx, &teamSelfParent)
+			Expect(err).To(HaveOccurred(), "There should be an error when creating a team referencing itself as parent team")
+			Expect(err.Error()).To(ContainSubstring("the parent Team references must not form a cycle"))
+		})
 	})
 })
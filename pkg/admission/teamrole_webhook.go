@@ -6,17 +6,21 @@ package admission
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
 	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/rbac"
 )
 
 const errAggregationRuleAndRulesExclusive = ".spec.rules and .spec.aggregationRule are mutually exclusive"
@@ -43,7 +47,7 @@ func DefaultRole(_ context.Context, _ client.Client, _ runtime.Object) error {
 
 //+kubebuilder:webhook:path=/validate-greenhouse-sap-v1alpha1-teamrole,mutating=false,failurePolicy=fail,sideEffects=None,groups=greenhouse.sap,resources=teamroles,verbs=create;update;delete,versions=v1alpha1,name=vrole.kb.io,admissionReviewVersions=v1
 
-func ValidateCreateRole(_ context.Context, c client.Client, o runtime.Object) (admission.Warnings, error) {
+func ValidateCreateRole(ctx context.Context, c client.Client, o runtime.Object) (admission.Warnings, error) {
 	role, ok := o.(*greenhousev1alpha1.TeamRole)
 	if !ok {
 		return nil, nil
@@ -53,10 +57,10 @@ func ValidateCreateRole(_ context.Context, c client.Client, o runtime.Object) (a
 		return nil, err
 	}
 
-	return nil, nil
+	return validateTeamRolePolicy(ctx, c, role)
 }
 
-func ValidateUpdateRole(_ context.Context, c client.Client, _, o runtime.Object) (admission.Warnings, error) {
+func ValidateUpdateRole(ctx context.Context, c client.Client, _, o runtime.Object) (admission.Warnings, error) {
 	role, ok := o.(*greenhousev1alpha1.TeamRole)
 	if !ok {
 		return nil, nil
@@ -65,7 +69,7 @@ func ValidateUpdateRole(_ context.Context, c client.Client, _, o runtime.Object)
 	if err := isRulesAndAggregationRuleExclusive(role); err != nil {
 		return nil, err
 	}
-	return nil, nil
+	return validateTeamRolePolicy(ctx, c, role)
 }
 
 func ValidateDeleteRole(ctx context.Context, c client.Client, o runtime.Object) (admission.Warnings, error) {
@@ -110,3 +114,38 @@ func isRulesAndAggregationRuleExclusive(role *greenhousev1alpha1.TeamRole) error
 	}
 	return nil
 }
+
+// validateTeamRolePolicy checks the effective rules of the TeamRole against the TeamRolePolicy of the Organization.
+// Sensitive rules are denied if the policy is enforced, otherwise they are returned as warnings.
+func validateTeamRolePolicy(ctx context.Context, c client.Client, role *greenhousev1alpha1.TeamRole) (admission.Warnings, error) {
+	org := new(greenhousev1alpha1.Organization)
+	if err := c.Get(ctx, types.NamespacedName{Name: role.GetNamespace()}, org); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	teamRoles := new(greenhousev1alpha1.TeamRoleList)
+	if err := c.List(ctx, teamRoles, client.InNamespace(role.GetNamespace())); err != nil {
+		return nil, err
+	}
+	// The TeamRole is checked in the state being admitted.
+	others := slices.DeleteFunc(teamRoles.Items, func(tr greenhousev1alpha1.TeamRole) bool {
+		return tr.GetName() == role.GetName()
+	})
+	findings, err := rbac.LintTeamRole(role, append(others, *role), org.Spec.TeamRolePolicy)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if len(findings) == 0 {
+		return nil, nil
+	}
+	if org.Spec.TeamRolePolicy != nil && org.Spec.TeamRolePolicy.Enforcement == greenhousev1alpha1.TeamRolePolicyEnforcementDeny {
+		return nil, apierrors.NewForbidden(schema.GroupResource{
+			Group:    role.GroupVersionKind().Group,
+			Resource: role.GroupVersionKind().Kind,
+		}, role.GetName(), errors.New("the TeamRole violates the TeamRolePolicy of the organization: "+strings.Join(findings, "; ")))
+	}
+	warnings := make(admission.Warnings, len(findings))
+	for i, finding := range findings {
+		warnings[i] = "TeamRole " + role.GetName() + " " + finding
+	}
+	return warnings, nil
+}
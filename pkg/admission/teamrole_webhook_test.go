@@ -104,6 +104,56 @@ var _ = Describe("Validate Role Admission", func() {
 		err := test.K8sClient.Delete(test.Ctx, teamRole)
 		Expect(err).To(HaveOccurred(), "there should be an error deleting the role with references")
 	})
+
+	Context("TeamRolePolicy", func() {
+		var org *greenhousev1alpha1.Organization
+
+		sensitiveRules := []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"create"},
+				APIGroups: []string{""},
+				Resources: []string{"pods/exec"},
+			},
+		}
+
+		BeforeEach(func() {
+			org = setup.CreateOrganization(test.Ctx, setup.Namespace(), func(org *greenhousev1alpha1.Organization) {
+				org.Spec.TeamRolePolicy = &greenhousev1alpha1.TeamRolePolicy{
+					Enforcement:     greenhousev1alpha1.TeamRolePolicyEnforcementDeny,
+					ExemptTeamRoles: []string{"exempt-role"},
+				}
+			})
+		})
+
+		AfterEach(func() {
+			test.EventuallyDeleted(test.Ctx, test.K8sClient, org)
+		})
+
+		It("should deny creating a TeamRole granting a sensitive resource", func() {
+			teamRole = test.NewTeamRole(test.Ctx, "test-role", setup.Namespace(), test.WithRules(sensitiveRules))
+
+			err := test.K8sClient.Create(test.Ctx, teamRole)
+			Expect(err).To(HaveOccurred(), "there should be an error creating the TeamRole violating the policy")
+			Expect(err.Error()).To(ContainSubstring("grants create on the sensitive resource pods/exec"), "the finding should be part of the error")
+		})
+
+		It("should deny aggregating a TeamRole granting a sensitive resource", func() {
+			exemptRole := test.NewTeamRole(test.Ctx, "exempt-role", setup.Namespace(), test.WithRules(sensitiveRules), test.WithLabels(map[string]string{"foo": "bar"}))
+			Expect(test.K8sClient.Create(test.Ctx, exemptRole)).To(Succeed(), "there should be no error creating the exempt TeamRole")
+			DeferCleanup(test.EventuallyDeleted, test.Ctx, test.K8sClient, exemptRole)
+
+			teamRole = test.NewTeamRole(test.Ctx, "test-role", setup.Namespace(), test.WithAggregationRule(aggregationRule), test.WithRules(nil))
+			err := test.K8sClient.Create(test.Ctx, teamRole)
+			Expect(err).To(HaveOccurred(), "there should be an error creating the TeamRole aggregating sensitive rules")
+			Expect(err.Error()).To(ContainSubstring("sensitive resource pods/exec"), "the finding should be part of the error")
+		})
+
+		It("should allow a TeamRole without sensitive rules", func() {
+			teamRole = setup.CreateTeamRole(test.Ctx, "test-role", test.WithRules([]rbacv1.PolicyRule{
+				{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			}))
+		})
+	})
 })
 
 // setupRoleBindingWebhookForTest adds an indexField for '.spec.roleRef', additionally to setting up the webhook for the RoleBinding resource. It is used in the webhook tests.
@@ -5,8 +5,13 @@ package admission
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
+	"strings"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -14,6 +19,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
 	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
 )
 
@@ -33,7 +39,39 @@ func SetupTeamRoleBindingWebhookWithManager(mgr ctrl.Manager) error {
 
 //+kubebuilder:webhook:path=/mutate-greenhouse-sap-v1alpha1-teamrolebinding,mutating=true,failurePolicy=fail,sideEffects=None,groups=greenhouse.sap,resources=teamrolebindings,verbs=create;update,versions=v1alpha1,name=mrolebinding.kb.io,admissionReviewVersions=v1
 
-func DefaultRoleBinding(_ context.Context, _ client.Client, _ runtime.Object) error {
+func DefaultRoleBinding(ctx context.Context, _ client.Client, o runtime.Object) error {
+	rb, ok := o.(*greenhousev1alpha1.TeamRoleBinding)
+	if !ok {
+		return nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil
+	}
+
+	switch req.Operation {
+	case admissionv1.Create:
+		// the requester is recorded to prevent them from approving their own request
+		metav1.SetMetaDataAnnotation(&rb.ObjectMeta, greenhouseapis.TeamRoleBindingRequestedByAnnotation, req.UserInfo.Username)
+		delete(rb.Annotations, greenhouseapis.TeamRoleBindingApprovedByAnnotation)
+		delete(rb.Annotations, greenhouseapis.TeamRoleBindingApprovedSpecAnnotation)
+	case admissionv1.Update:
+		oldRB := new(greenhousev1alpha1.TeamRoleBinding)
+		if err := json.Unmarshal(req.OldObject.Raw, oldRB); err != nil {
+			return err
+		}
+		// an update by the approver (re)approves the current spec, otherwise the approved spec is retained and any change of the spec revokes the approval
+		approvedBy := rb.GetAnnotations()[greenhouseapis.TeamRoleBindingApprovedByAnnotation]
+		approvedSpec, wasApproved := oldRB.GetAnnotations()[greenhouseapis.TeamRoleBindingApprovedSpecAnnotation]
+		switch {
+		case approvedBy != "" && approvedBy == req.UserInfo.Username:
+			metav1.SetMetaDataAnnotation(&rb.ObjectMeta, greenhouseapis.TeamRoleBindingApprovedSpecAnnotation, rb.SpecHash())
+		case approvedBy != "" && wasApproved:
+			metav1.SetMetaDataAnnotation(&rb.ObjectMeta, greenhouseapis.TeamRoleBindingApprovedSpecAnnotation, approvedSpec)
+		default:
+			delete(rb.Annotations, greenhouseapis.TeamRoleBindingApprovedSpecAnnotation)
+		}
+	}
 	return nil
 }
 
@@ -67,6 +105,15 @@ func ValidateCreateRoleBinding(ctx context.Context, c client.Client, o runtime.O
 	if err != nil {
 		return nil, err
 	}
+	if err := validateValidityPeriod(rb); err != nil {
+		return nil, err
+	}
+	if err := validateNamespacesOrSelector(rb); err != nil {
+		return nil, err
+	}
+	if approvedBy, ok := rb.GetAnnotations()[greenhouseapis.TeamRoleBindingApprovedByAnnotation]; ok {
+		return nil, apierrors.NewInvalid(rb.GroupVersionKind().GroupKind(), rb.Name, field.ErrorList{field.Forbidden(field.NewPath("metadata", "annotations").Key(greenhouseapis.TeamRoleBindingApprovedByAnnotation), "cannot approve a TeamRoleBinding on creation, found approval by "+approvedBy)})
+	}
 	return nil, nil
 }
 
@@ -79,6 +126,15 @@ func ValidateUpdateRoleBinding(ctx context.Context, c client.Client, old, cur ru
 	if !ok {
 		return nil, nil
 	}
+	if err := validateValidityPeriod(curRB); err != nil {
+		return nil, err
+	}
+	if err := validateNamespacesOrSelector(curRB); err != nil {
+		return nil, err
+	}
+	if err := validateApproval(ctx, c, oldRB, curRB); err != nil {
+		return nil, err
+	}
 	switch {
 	case validateClusterNameOrSelector(curRB) != nil:
 		return nil, apierrors.NewForbidden(
@@ -131,7 +187,65 @@ func validateClusterNameOrSelector(rb *greenhousev1alpha1.TeamRoleBinding) error
 	return nil
 }
 
+// validateNamespacesOrSelector checks that the TeamRoleBinding does not specify both namespaces and a namespaceSelector.
+func validateNamespacesOrSelector(rb *greenhousev1alpha1.TeamRoleBinding) error {
+	if len(rb.Spec.Namespaces) == 0 || rb.Spec.NamespaceSelector == nil {
+		return nil
+	}
+	return apierrors.NewInvalid(rb.GroupVersionKind().GroupKind(), rb.Name, field.ErrorList{field.Invalid(field.NewPath("spec", "namespaceSelector"), rb.Spec.NamespaceSelector.String(), "cannot specify both spec.namespaces and spec.namespaceSelector")})
+}
+
+// validateValidityPeriod checks that the TeamRoleBinding does not expire before it becomes valid.
+func validateValidityPeriod(rb *greenhousev1alpha1.TeamRoleBinding) error {
+	if rb.Spec.NotBefore == nil || rb.Spec.ExpiresAt == nil || rb.Spec.NotBefore.Before(rb.Spec.ExpiresAt) {
+		return nil
+	}
+	return apierrors.NewInvalid(rb.GroupVersionKind().GroupKind(), rb.Name, field.ErrorList{field.Invalid(field.NewPath("spec", "expiresAt"), rb.Spec.ExpiresAt.String(), "must be after spec.notBefore")})
+}
+
+// validateApproval checks that the requester of a TeamRoleBinding is unchanged and that an approval is given by another member of the Team in their own name.
+// An approval is given whenever the approver or the approved spec changes, so a re-approval of a changed spec by the same approver is validated as well.
+func validateApproval(ctx context.Context, c client.Client, oldRB, curRB *greenhousev1alpha1.TeamRoleBinding) error {
+	annotationsPath := field.NewPath("metadata", "annotations")
+	requestedBy := oldRB.GetAnnotations()[greenhouseapis.TeamRoleBindingRequestedByAnnotation]
+	if curRB.GetAnnotations()[greenhouseapis.TeamRoleBindingRequestedByAnnotation] != requestedBy {
+		return apierrors.NewInvalid(curRB.GroupVersionKind().GroupKind(), curRB.Name, field.ErrorList{field.Forbidden(annotationsPath.Key(greenhouseapis.TeamRoleBindingRequestedByAnnotation), "cannot change the requester of a TeamRoleBinding")})
+	}
+
+	approvedBy := curRB.GetAnnotations()[greenhouseapis.TeamRoleBindingApprovedByAnnotation]
+	approvedSpec := curRB.GetAnnotations()[greenhouseapis.TeamRoleBindingApprovedSpecAnnotation]
+	if approvedBy == "" || (approvedBy == oldRB.GetAnnotations()[greenhouseapis.TeamRoleBindingApprovedByAnnotation] &&
+		approvedSpec == oldRB.GetAnnotations()[greenhouseapis.TeamRoleBindingApprovedSpecAnnotation]) {
+		return nil
+	}
+	approverPath := annotationsPath.Key(greenhouseapis.TeamRoleBindingApprovedByAnnotation)
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if req.UserInfo.Username != approvedBy {
+		return apierrors.NewInvalid(curRB.GroupVersionKind().GroupKind(), curRB.Name, field.ErrorList{field.Forbidden(approverPath, "an approval must be given in the name of the approving user "+req.UserInfo.Username)})
+	}
+	if approvedSpec != curRB.SpecHash() {
+		return apierrors.NewInvalid(curRB.GroupVersionKind().GroupKind(), curRB.Name, field.ErrorList{field.Forbidden(annotationsPath.Key(greenhouseapis.TeamRoleBindingApprovedSpecAnnotation), "must match the hash of the approved spec")})
+	}
+	if approvedBy == requestedBy {
+		return apierrors.NewInvalid(curRB.GroupVersionKind().GroupKind(), curRB.Name, field.ErrorList{field.Forbidden(approverPath, "the requester cannot approve their own TeamRoleBinding")})
+	}
+
+	var team greenhousev1alpha1.Team
+	if err := c.Get(ctx, client.ObjectKey{Namespace: curRB.Namespace, Name: curRB.Spec.TeamRef}, &team); err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if !slices.ContainsFunc(team.Status.Members, func(member greenhousev1alpha1.User) bool {
+		return member.ID == approvedBy || strings.EqualFold(member.Email, approvedBy)
+	}) {
+		return apierrors.NewInvalid(curRB.GroupVersionKind().GroupKind(), curRB.Name, field.ErrorList{field.Forbidden(approverPath, approvedBy+" is not a member of team "+team.Name)})
+	}
+	return nil
+}
+
 // isClusterScoped returns true if the TeamRoleBinding will create ClusterRoleBindings.
 func isClusterScoped(trb *greenhousev1alpha1.TeamRoleBinding) bool {
-	return len(trb.Spec.Namespaces) == 0
+	return len(trb.Spec.Namespaces) == 0 && trb.Spec.NamespaceSelector == nil
 }
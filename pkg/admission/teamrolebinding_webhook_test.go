@@ -4,12 +4,22 @@
 package admission
 
 import (
+	"context"
+	"encoding/json"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
 	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
 	"github.com/cloudoperators/greenhouse/pkg/test"
 )
 
@@ -115,6 +125,27 @@ var _ = Describe("Validate Create RoleBinding", Ordered, func() {
 			Expect(err).To(HaveOccurred(), "expected an error")
 			Expect(err).To(MatchError(ContainSubstring("cannot specify both spec.clusterName and spec.clusterSelector")))
 		})
+		It("should return an error if both namespaces and namespaceSelector are specified", func() {
+			rb := &greenhousev1alpha1.TeamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: setup.Namespace(),
+					Name:      "testBinding",
+				},
+				Spec: greenhousev1alpha1.TeamRoleBindingSpec{
+					TeamRoleRef: teamRole.Name,
+					TeamRef:     team.Name,
+					ClusterName: cluster.Name,
+					Namespaces:  []string{"test-namespace"},
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"test": "test"},
+					},
+				},
+			}
+			warns, err := ValidateCreateRoleBinding(test.Ctx, test.K8sClient, rb)
+			Expect(warns).To(BeNil(), "expected no warnings")
+			Expect(err).To(HaveOccurred(), "expected an error")
+			Expect(err).To(MatchError(ContainSubstring("cannot specify both spec.namespaces and spec.namespaceSelector")))
+		})
 	})
 
 	Context("Validate Update Rolebinding", func() {
@@ -302,4 +333,103 @@ var _ = Describe("Validate Create RoleBinding", Ordered, func() {
 			Expect(err).To(MatchError(ContainSubstring("cannot change TeamRef of an existing TeamRoleBinding")))
 		})
 	})
+
+	Context("Validate time-bound and approved TeamRoleBindings", func() {
+		approvalCtx := func(username string) context.Context {
+			return admission.NewContextWithRequest(test.Ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: username},
+			}})
+		}
+		requestedRB := func() *greenhousev1alpha1.TeamRoleBinding {
+			return &greenhousev1alpha1.TeamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   setup.Namespace(),
+					Name:        "testBinding",
+					Annotations: map[string]string{greenhouseapis.TeamRoleBindingRequestedByAnnotation: "requester"},
+				},
+				Spec: greenhousev1alpha1.TeamRoleBindingSpec{
+					TeamRoleRef: teamRole.Name,
+					TeamRef:     team.Name,
+					ClusterName: cluster.Name,
+				},
+			}
+		}
+
+		It("should deny a TeamRoleBinding expiring before it is valid", func() {
+			rb := requestedRB()
+			rb.Spec.NotBefore = &metav1.Time{Time: time.Now().Add(time.Hour)}
+			rb.Spec.ExpiresAt = &metav1.Time{Time: time.Now()}
+			_, err := ValidateCreateRoleBinding(test.Ctx, test.K8sClient, rb)
+			Expect(err).To(MatchError(ContainSubstring("must be after spec.notBefore")), "expected an error for the invalid validity period")
+		})
+
+		It("should record the requester on creation", func() {
+			rb := requestedRB()
+			rb.Annotations = map[string]string{greenhouseapis.TeamRoleBindingApprovedByAnnotation: "requester"}
+			ctx := admission.NewContextWithRequest(test.Ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "requester"},
+			}})
+			Expect(DefaultRoleBinding(ctx, test.K8sClient, rb)).To(Succeed(), "there should be no error defaulting the TeamRoleBinding")
+			Expect(rb.Annotations).To(HaveKeyWithValue(greenhouseapis.TeamRoleBindingRequestedByAnnotation, "requester"), "the requester should be recorded")
+			Expect(rb.Annotations).NotTo(HaveKey(greenhouseapis.TeamRoleBindingApprovedByAnnotation), "an approval on creation should be removed")
+		})
+
+		It("should deny the approval by the requester", func() {
+			curRB := requestedRB()
+			curRB.Annotations[greenhouseapis.TeamRoleBindingApprovedByAnnotation] = "requester"
+			curRB.Annotations[greenhouseapis.TeamRoleBindingApprovedSpecAnnotation] = curRB.SpecHash()
+			_, err := ValidateUpdateRoleBinding(approvalCtx("requester"), test.K8sClient, requestedRB(), curRB)
+			Expect(err).To(MatchError(ContainSubstring("the requester cannot approve their own TeamRoleBinding")), "expected an error for the self-approval")
+		})
+
+		It("should deny the approval in the name of another user", func() {
+			curRB := requestedRB()
+			curRB.Annotations[greenhouseapis.TeamRoleBindingApprovedByAnnotation] = "approver"
+			_, err := ValidateUpdateRoleBinding(approvalCtx("someone-else"), test.K8sClient, requestedRB(), curRB)
+			Expect(err).To(MatchError(ContainSubstring("must be given in the name of the approving user")), "expected an error for the approval in the name of another user")
+		})
+
+		It("should only allow the approval by a member of the team", func() {
+			curRB := requestedRB()
+			curRB.Annotations[greenhouseapis.TeamRoleBindingApprovedByAnnotation] = "approver"
+			curRB.Annotations[greenhouseapis.TeamRoleBindingApprovedSpecAnnotation] = curRB.SpecHash()
+			_, err := ValidateUpdateRoleBinding(approvalCtx("approver"), test.K8sClient, requestedRB(), curRB)
+			Expect(err).To(MatchError(ContainSubstring("approver is not a member of team")), "expected an error for the approval by a non-member")
+
+			_, err = clientutil.PatchStatus(test.Ctx, test.K8sClient, team, func() error {
+				team.Status.Members = []greenhousev1alpha1.User{{ID: "approver", Email: "approver@example.com"}}
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred(), "there should be no error adding the member to the team")
+			_, err = ValidateUpdateRoleBinding(approvalCtx("approver"), test.K8sClient, requestedRB(), curRB)
+			Expect(err).NotTo(HaveOccurred(), "the approval by a member of the team should be allowed")
+		})
+
+		It("should only retain the approval for the approved spec", func() {
+			approvedRB := requestedRB()
+			approvedRB.Annotations[greenhouseapis.TeamRoleBindingApprovedByAnnotation] = "approver"
+			approvedRB.Annotations[greenhouseapis.TeamRoleBindingApprovedSpecAnnotation] = approvedRB.SpecHash()
+			oldRaw, err := json.Marshal(approvedRB)
+			Expect(err).NotTo(HaveOccurred(), "there should be no error marshalling the TeamRoleBinding")
+			updateCtx := func(username string) context.Context {
+				return admission.NewContextWithRequest(test.Ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					UserInfo:  authenticationv1.UserInfo{Username: username},
+					OldObject: runtime.RawExtension{Raw: oldRaw},
+				}})
+			}
+			requiringRole := &greenhousev1alpha1.TeamRole{Spec: greenhousev1alpha1.TeamRoleSpec{RequiresApproval: true}}
+
+			By("changing the spec as the requester")
+			changedRB := approvedRB.DeepCopy()
+			changedRB.Spec.Usernames = []string{"someone"}
+			Expect(DefaultRoleBinding(updateCtx("requester"), test.K8sClient, changedRB)).To(Succeed(), "there should be no error defaulting the TeamRoleBinding")
+			Expect(changedRB.IsApproved(requiringRole)).To(BeFalse(), "a change of the spec should revoke the approval")
+
+			By("re-approving the changed spec as the same approver")
+			Expect(DefaultRoleBinding(updateCtx("approver"), test.K8sClient, changedRB)).To(Succeed(), "there should be no error defaulting the TeamRoleBinding")
+			Expect(changedRB.IsApproved(requiringRole)).To(BeTrue(), "the re-approval of the changed spec should be recorded")
+		})
+	})
 })
@@ -4,6 +4,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -14,10 +15,39 @@ type ClusterSpec struct {
 
 	// KubeConfig contains specific values for `KubeConfig` for the cluster.
 	KubeConfig ClusterKubeConfig `json:"kubeConfig,omitempty"`
+
+	// Maintenance pauses the changes Greenhouse applies to the cluster, e.g. during upgrades of the cluster.
+	Maintenance *ClusterMaintenance `json:"maintenance,omitempty"`
+
+	// DeletionPolicy configures what happens to the Plugins and RBAC deployed to the cluster when it is deleted.
+	// Uninstall removes them from the cluster, Forget only removes them from Greenhouse, e.g. if the cluster is already gone.
+	// +kubebuilder:default:=Uninstall
+	DeletionPolicy ClusterDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// ClusterDeletionPolicy configures the cleanup of the resources deployed to a cluster when it is deleted.
+// +kubebuilder:validation:Enum=Uninstall;Forget
+type ClusterDeletionPolicy string
+
+// ClusterMaintenance configures when the cluster is in maintenance.
+// While the cluster is in maintenance, Plugins, PluginPresets and TeamRoleBindings do not apply changes to the cluster.
+type ClusterMaintenance struct {
+	// Enabled puts the cluster into maintenance immediately until it is disabled again.
+	Enabled bool `json:"enabled,omitempty"`
+	// Windows are recurring maintenance windows.
+	Windows []MaintenanceWindow `json:"windows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring maintenance window.
+type MaintenanceWindow struct {
+	// Schedule is the cron expression for the start of the window, e.g. "0 22 * * 6". It is evaluated in UTC.
+	Schedule string `json:"schedule"`
+	// Duration of the window, e.g. "2h".
+	Duration metav1.Duration `json:"duration"`
 }
 
 // ClusterAccessMode configures the access mode to the customer cluster.
-// +kubebuilder:validation:Enum=direct
+// +kubebuilder:validation:Enum=direct;agent
 type ClusterAccessMode string
 
 // ClusterKubeConfig configures kube config values.
@@ -33,6 +63,45 @@ const (
 	// ClusterAccessModeDirect configures direct access to the cluster.
 	ClusterAccessModeDirect ClusterAccessMode = "direct"
 
+	// ClusterAccessModeAgent configures access to the cluster through the tunnel opened by the agent running in the cluster.
+	ClusterAccessModeAgent ClusterAccessMode = "agent"
+
+	// ClusterDeletionPolicyUninstall removes the Plugins and RBAC deployed to the cluster from it before the cluster is deleted.
+	ClusterDeletionPolicyUninstall ClusterDeletionPolicy = "Uninstall"
+
+	// ClusterDeletionPolicyForget removes the Plugins and RBAC deployed to the cluster only from Greenhouse, without accessing the cluster.
+	ClusterDeletionPolicyForget ClusterDeletionPolicy = "Forget"
+
+	// AgentConnected reflects the connection status of the agent of a cluster with access mode agent.
+	AgentConnected ConditionType = "AgentConnected"
+
+	// APIServerHealthy reflects the result of the readyz and livez checks of the API server of a cluster.
+	APIServerHealthy ConditionType = "APIServerHealthy"
+
+	// APIServerUnreachableReason is set if the API server of a cluster does not respond.
+	APIServerUnreachableReason ConditionReason = "Unreachable"
+
+	// APIServerChecksFailingReason is set if the API server of a cluster reports failing health checks.
+	APIServerChecksFailingReason ConditionReason = "ChecksFailing"
+
+	// ClusterInMaintenance is set on Plugins, PluginPresets and TeamRoleBindings while changes to a cluster are paused due to its maintenance.
+	ClusterInMaintenance ConditionType = "ClusterInMaintenance"
+
+	// CredentialsRotated reflects the result of the last rotation of the credentials requested for a cluster.
+	CredentialsRotated ConditionType = "CredentialsRotated"
+
+	// CredentialsRotationFailedReason is set if the rotation of the credentials of a cluster failed.
+	CredentialsRotationFailedReason ConditionReason = "RotationFailed"
+
+	// CredentialsRotationNotSupportedReason is set if the credentials of a cluster cannot be revoked, e.g. for clusters accessed via OIDC.
+	CredentialsRotationNotSupportedReason ConditionReason = "NotSupported"
+
+	// PluginsKubeVersionCompatible reflects whether the Kubernetes version of a cluster satisfies the kubeVersion constraints of the Plugins deployed to it.
+	PluginsKubeVersionCompatible ConditionType = "PluginsKubeVersionCompatible"
+
+	// KubeVersionIncompatibleReason is set if the Kubernetes version of a cluster does not satisfy the kubeVersion constraint of a PluginDefinition.
+	KubeVersionIncompatibleReason ConditionReason = "KubeVersionIncompatible"
+
 	// AllNodesReady reflects the readiness status of all nodes of a cluster.
 	AllNodesReady ConditionType = "AllNodesReady"
 
@@ -56,6 +125,48 @@ type ClusterStatus struct {
 	StatusConditions `json:"statusConditions,omitempty"`
 	// Nodes provides a map of cluster node names to node statuses
 	Nodes map[string]NodeStatus `json:"nodes,omitempty"`
+	// Inventory summarizes the nodes and APIs of the cluster.
+	Inventory *ClusterInventory `json:"inventory,omitempty"`
+	// DeletionReport lists the resources affected by the deletion of the cluster. It is refreshed while the deletion is scheduled.
+	DeletionReport *ClusterDeletionReport `json:"deletionReport,omitempty"`
+}
+
+// ClusterDeletionReport lists the resources affected by the deletion of the cluster.
+type ClusterDeletionReport struct {
+	// DeletionPolicy is the policy that is applied to the resources deployed to the cluster.
+	DeletionPolicy ClusterDeletionPolicy `json:"deletionPolicy,omitempty"`
+	// Plugins lists the Plugins deployed to the cluster, which are deleted with it.
+	Plugins []string `json:"plugins,omitempty"`
+	// PluginPresets lists the PluginPresets selecting the cluster, which stop deploying to it.
+	PluginPresets []string `json:"pluginPresets,omitempty"`
+	// TeamRoleBindings lists the TeamRoleBindings applied to the cluster, which stop deploying RBAC to it.
+	TeamRoleBindings []string `json:"teamRoleBindings,omitempty"`
+	// BlockingTeamRoleBindings lists the TeamRoleBindings which did not yet remove their RBAC from the cluster while it is deleted.
+	BlockingTeamRoleBindings []string `json:"blockingTeamRoleBindings,omitempty"`
+}
+
+// ClusterInventory summarizes the nodes and APIs of the cluster.
+type ClusterInventory struct {
+	// APIServerEndpoint is the endpoint of the API server used by Greenhouse.
+	APIServerEndpoint string `json:"apiServerEndpoint,omitempty"`
+	// Provider is detected from the providerID of the nodes, e.g. aws, azure, gce or openstack.
+	Provider string `json:"provider,omitempty"`
+	// NodeCount is the total number of nodes.
+	NodeCount int `json:"nodeCount"`
+	// NodesByRole counts the nodes by their node-role.kubernetes.io/<role> labels. Nodes without a role are counted as none.
+	NodesByRole map[string]int `json:"nodesByRole,omitempty"`
+	// AllocatableCPU is the sum of the allocatable CPU of all nodes.
+	AllocatableCPU resource.Quantity `json:"allocatableCPU,omitempty"`
+	// AllocatableMemory is the sum of the allocatable memory of all nodes.
+	AllocatableMemory resource.Quantity `json:"allocatableMemory,omitempty"`
+	// KubeletVersions counts the nodes by kubelet version.
+	KubeletVersions map[string]int `json:"kubeletVersions,omitempty"`
+	// ContainerRuntimeVersions counts the nodes by container runtime version.
+	ContainerRuntimeVersions map[string]int `json:"containerRuntimeVersions,omitempty"`
+	// OSImages counts the nodes by operating system image.
+	OSImages map[string]int `json:"osImages,omitempty"`
+	// CRDGroups lists the API groups of the CustomResourceDefinitions installed in the cluster.
+	CRDGroups []string `json:"crdGroups,omitempty"`
 }
 
 // ClusterConditionType is a valid condition of a cluster.
@@ -91,6 +202,12 @@ func (c *Cluster) SetCondition(condition Condition) {
 	c.Status.StatusConditions.SetConditions(condition)
 }
 
+// SkipsRemoteCleanup returns true if the cluster is being deleted with the deletion policy Forget.
+// The resources deployed to such a cluster are only removed from Greenhouse.
+func (c *Cluster) SkipsRemoteCleanup() bool {
+	return c.DeletionTimestamp != nil && c.Spec.DeletionPolicy == ClusterDeletionPolicyForget
+}
+
 // GetSecretName returns the Kubernetes secret containing sensitive data for this cluster.
 // The secret is for internal usage only and its content must not be exposed to the user.
 func (c *Cluster) GetSecretName() string {
@@ -43,9 +43,20 @@ type ClusterKubeconfigAuthInfoItem struct {
 }
 
 type ClusterKubeconfigAuthInfo struct {
-	AuthProvider          clientcmdapi.AuthProviderConfig `json:"auth-provider,omitempty"`
-	ClientCertificateData []byte                          `json:"client-certificate-data,omitempty"`
-	ClientKeyData         []byte                          `json:"client-key-data,omitempty"`
+	AuthProvider          *clientcmdapi.AuthProviderConfig `json:"auth-provider,omitempty"`
+	Exec                  *ClusterKubeconfigExecConfig     `json:"exec,omitempty"`
+	ClientCertificateData []byte                           `json:"client-certificate-data,omitempty"`
+	ClientKeyData         []byte                           `json:"client-key-data,omitempty"`
+}
+
+// ClusterKubeconfigExecConfig configures a credential plugin obtaining the token.
+// It is a simplified version of clientcmdapi.ExecConfig: https://pkg.go.dev/k8s.io/client-go/tools/clientcmd/api#ExecConfig
+type ClusterKubeconfigExecConfig struct {
+	APIVersion      string   `json:"apiVersion"`
+	Command         string   `json:"command"`
+	Args            []string `json:"args,omitempty"`
+	InstallHint     string   `json:"installHint,omitempty"`
+	InteractiveMode string   `json:"interactiveMode,omitempty"`
 }
 
 type ClusterKubeconfigContextItem struct {
@@ -12,4 +12,10 @@ const (
 	SuccessfulDeletedEvent = "SuccessfulDeleted"
 	// FailedDeleteFailedReason is used if the delete failed
 	FailedDeleteEvent = "FailedDelete"
+	// CredentialsRotationEvent is used for the steps of the rotation of the credentials of a cluster
+	CredentialsRotationEvent = "CredentialsRotation"
+	// KubeVersionIncompatibleEvent is used if the Kubernetes version of a cluster is not compatible with the Plugins deployed to it
+	KubeVersionIncompatibleEvent = "KubeVersionIncompatible"
+	// RBACDriftDetectedEvent is used if rbacv1 resources deployed by a TeamRoleBinding were modified or deleted on a cluster
+	RBACDriftDetectedEvent = "RBACDriftDetected"
 )
@@ -53,8 +53,38 @@ type OrganizationSpec struct {
 
 	// MappedOrgAdminIDPGroup is the IDP group ID identifying org admins
 	MappedOrgAdminIDPGroup string `json:"mappedOrgAdminIdPGroup,omitempty"`
+
+	// TeamRolePolicy configures the check of the TeamRoles for rules allowing privilege escalation.
+	TeamRolePolicy *TeamRolePolicy `json:"teamRolePolicy,omitempty"`
+}
+
+// TeamRolePolicy configures which rules of TeamRoles are considered sensitive and how violations are handled.
+type TeamRolePolicy struct {
+	// Enforcement decides whether TeamRoles with sensitive rules are admitted with a warning or denied.
+	// +kubebuilder:validation:Enum=Warn;Deny
+	// +kubebuilder:default="Warn"
+	Enforcement TeamRolePolicyEnforcement `json:"enforcement,omitempty"`
+	// SensitiveResources are the resources in the format resource[.group][/subresource], which may not be granted with any verb.
+	// Defaults to pods/exec, pods/attach, secrets, serviceaccounts/token and nodes/proxy.
+	SensitiveResources []string `json:"sensitiveResources,omitempty"`
+	// SensitiveVerbs are the verbs, which may not be granted on any resource. The verb * refers to rules granting all verbs.
+	// Defaults to *, escalate, bind and impersonate.
+	SensitiveVerbs []string `json:"sensitiveVerbs,omitempty"`
+	// ExemptTeamRoles are the names of the TeamRoles not checked, e.g. the seeded cluster-admin TeamRole.
+	// +kubebuilder:default={cluster-admin}
+	ExemptTeamRoles []string `json:"exemptTeamRoles,omitempty"`
 }
 
+// TeamRolePolicyEnforcement decides how TeamRoles violating the TeamRolePolicy are handled.
+type TeamRolePolicyEnforcement string
+
+const (
+	// TeamRolePolicyEnforcementWarn admits TeamRoles violating the policy with a warning.
+	TeamRolePolicyEnforcementWarn TeamRolePolicyEnforcement = "Warn"
+	// TeamRolePolicyEnforcementDeny denies TeamRoles violating the policy.
+	TeamRolePolicyEnforcementDeny TeamRolePolicyEnforcement = "Deny"
+)
+
 type Authentication struct {
 	// OIDConfig configures the OIDC provider.
 	OIDCConfig *OIDCConfig `json:"oidc,omitempty"`
@@ -75,8 +105,28 @@ type OIDCConfig struct {
 	// OAuth2ClientRedirectURIs are a registered set of redirect URIs. When redirecting from the idproxy to
 	// the client application, the URI requested to redirect to must be contained in this list.
 	OAuth2ClientRedirectURIs []string `json:"oauth2ClientRedirectURIs,omitempty"`
+	// KubeconfigFormat selects how kubectl obtains the token in the ClusterKubeconfigs of the organization.
+	// auth-provider uses the deprecated oidc auth provider, exec the kubelogin credential plugin and both provides the two during a migration.
+	// +kubebuilder:validation:Enum=auth-provider;exec;both
+	// +kubebuilder:default="auth-provider"
+	KubeconfigFormat KubeconfigFormat `json:"kubeconfigFormat,omitempty"`
+	// KubeconfigScopes are requested by the kubelogin credential plugin in addition to openid.
+	// Defaults to email, groups and offline_access.
+	KubeconfigScopes []string `json:"kubeconfigScopes,omitempty"`
 }
 
+// KubeconfigFormat selects how kubectl obtains the token in the ClusterKubeconfigs of an organization.
+type KubeconfigFormat string
+
+const (
+	// KubeconfigFormatAuthProvider uses the oidc auth provider, which is no longer supported by current kubectl versions.
+	KubeconfigFormatAuthProvider KubeconfigFormat = "auth-provider"
+	// KubeconfigFormatExec uses the kubelogin credential plugin.
+	KubeconfigFormatExec KubeconfigFormat = "exec"
+	// KubeconfigFormatBoth provides the auth provider and the credential plugin in separate contexts.
+	KubeconfigFormatBoth KubeconfigFormat = "both"
+)
+
 type SCIMConfig struct {
 	// URL to the SCIM server.
 	BaseURL string `json:"baseURL"`
@@ -70,6 +70,10 @@ const (
 	// HelmChartTestSucceededCondition reflects the status of the HelmChart tests.
 	HelmChartTestSucceededCondition ConditionType = "HelmChartTestSucceeded"
 
+	// KubeVersionCompatibleCondition reflects whether the Kubernetes version of the cluster satisfies the kubeVersion constraint of the PluginDefinition.
+	// It is set by the PluginPreset on its Plugins once their cluster became incompatible.
+	KubeVersionCompatibleCondition ConditionType = "KubeVersionCompatible"
+
 	// PluginDefinitionNotFoundReason is set when the pluginDefinition is not found.
 	PluginDefinitionNotFoundReason ConditionReason = "PluginDefinitionNotFound"
 
@@ -77,6 +81,13 @@ const (
 	HelmUninstallFailedReason ConditionReason = "HelmUninstallFailed"
 )
 
+// PluginHelmChartStatus is the helm chart deployed by the Plugin.
+type PluginHelmChartStatus struct {
+	HelmChartReference `json:",inline"`
+	// ResolvedCommit is the commit the Git reference of a chart sourced from Git was resolved to.
+	ResolvedCommit string `json:"resolvedCommit,omitempty"`
+}
+
 // PluginStatus defines the observed state of Plugin
 type PluginStatus struct {
 	// HelmReleaseStatus reflects the status of the latest HelmChart release.
@@ -87,7 +98,7 @@ type PluginStatus struct {
 	Version string `json:"version,omitempty"`
 
 	// HelmChart contains a reference the helm chart used for the deployed pluginDefinition version.
-	HelmChart *HelmChartReference `json:"helmChart,omitempty"`
+	HelmChart *PluginHelmChartStatus `json:"helmChart,omitempty"`
 
 	// UIApplication contains a reference to the frontend that is used for the deployed pluginDefinition version.
 	UIApplication *UIApplicationReference `json:"uiApplication,omitempty"`
@@ -130,6 +141,22 @@ type HelmReleaseStatus struct {
 	PluginOptionChecksum string `json:"pluginOptionChecksum,omitempty"`
 	// Diff contains the difference between the deployed helm chart and the helm chart in the last reconciliation
 	Diff string `json:"diff,omitempty"`
+	// AppVersion is the appVersion of the deployed helm chart.
+	AppVersion string `json:"appVersion,omitempty"`
+	// Notes are the rendered NOTES.txt of the deployed helm chart. Long notes are truncated.
+	Notes string `json:"notes,omitempty"`
+	// Objects lists the number of objects per kind in the release manifest. The list is limited to a maximum number of kinds.
+	Objects []ReleaseObjectCount `json:"objects,omitempty"`
+}
+
+// ReleaseObjectCount is the number of objects of a kind in a Helm release.
+type ReleaseObjectCount struct {
+	// APIVersion of the objects.
+	APIVersion string `json:"apiVersion"`
+	// Kind of the objects.
+	Kind string `json:"kind"`
+	// Count is the number of objects of this kind.
+	Count int32 `json:"count"`
 }
 
 //+kubebuilder:object:root=true
@@ -141,6 +168,7 @@ type HelmReleaseStatus struct {
 //+kubebuilder:printcolumn:name="Disabled",type=boolean,JSONPath=`.spec.disabled`
 //+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.statusConditions.conditions[?(@.type == "Ready")].status`
 //+kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
+//+kubebuilder:printcolumn:name="App Version",type=string,JSONPath=`.status.helmReleaseStatus.appVersion`,priority=1
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Plugin is the Schema for the plugins API
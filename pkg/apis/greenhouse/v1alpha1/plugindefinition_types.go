@@ -22,6 +22,10 @@ type PluginDefinitionSpec struct {
 	// HelmChart specifies where the Helm Chart for this pluginDefinition can be found.
 	HelmChart *HelmChartReference `json:"helmChart,omitempty"`
 
+	// Manifests specifies plain Kubernetes manifests or a Kustomize overlay for this pluginDefinition.
+	// Only one of HelmChart and Manifests may be set.
+	Manifests *ManifestReference `json:"manifests,omitempty"`
+
 	// UIApplication specifies a reference to a UI application
 	UIApplication *UIApplicationReference `json:"uiApplication,omitempty"`
 
@@ -46,6 +50,55 @@ type PluginDefinitionSpec struct {
 	DocMarkDownUrl string `json:"docMarkDownUrl,omitempty"` //nolint:stylecheck
 }
 
+// ChartReference returns the reference of the Helm chart deployed for the pluginDefinition.
+// Manifests are deployed as a Helm chart rendered from their Git source. Nil is returned for UI-only pluginDefinitions.
+func (s *PluginDefinitionSpec) ChartReference() *HelmChartReference {
+	switch {
+	case s.HelmChart != nil:
+		return s.HelmChart
+	case s.Manifests != nil:
+		return &HelmChartReference{Version: s.Version, Git: s.Manifests.Git.DeepCopy()}
+	default:
+		return nil
+	}
+}
+
+// ManifestReference references plain Kubernetes manifests or a Kustomize overlay in a Git repository.
+// If the path contains a kustomization it is built with Kustomize, otherwise all manifests in the path are deployed.
+type ManifestReference struct {
+	// Git is the repository containing the manifests.
+	Git GitChartSource `json:"git"`
+	// Replacements write PluginOption values into fields of the rendered manifests.
+	Replacements []ManifestReplacement `json:"replacements,omitempty"`
+}
+
+// ManifestReplacement writes the value of a PluginOption into fields of the rendered manifests using Kustomize replacements.
+type ManifestReplacement struct {
+	// Option is the name of the PluginOption whose value is written.
+	Option string `json:"option"`
+	// Targets select the objects and fields the value is written to.
+	Targets []ManifestReplacementTarget `json:"targets"`
+}
+
+// ManifestReplacementTarget selects the objects and fields a PluginOption value is written to.
+type ManifestReplacementTarget struct {
+	// Select specifies the objects to write the value to.
+	Select ManifestObjectSelector `json:"select"`
+	// FieldPaths are the paths of the fields within the selected objects, e.g. spec.template.spec.containers.[name=app].image.
+	FieldPaths []string `json:"fieldPaths"`
+	// Create specifies whether missing fields are created.
+	Create bool `json:"create,omitempty"`
+}
+
+// ManifestObjectSelector selects objects by their group, version, kind, name and namespace. Empty fields match all objects.
+type ManifestObjectSelector struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
 // PluginOptionType specifies the type of PluginOption.
 // +kubebuilder:validation:Enum=string;secret;bool;int;list;map
 type PluginOptionType string
@@ -202,7 +255,12 @@ func (p *PluginOption) DefaultValue() (any, error) {
 }
 
 // PluginDefinitionStatus defines the observed state of PluginDefinition
-type PluginDefinitionStatus struct{}
+type PluginDefinitionStatus struct {
+	// KubeVersion is the Kubernetes version constraint of the Helm chart, e.g. ">= 1.27.0-0".
+	KubeVersion string `json:"kubeVersion,omitempty"`
+	// IncompatibleClusters lists the clusters as <organization>/<cluster>, whose Kubernetes version does not satisfy the KubeVersion constraint.
+	IncompatibleClusters []string `json:"incompatibleClusters,omitempty"`
+}
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
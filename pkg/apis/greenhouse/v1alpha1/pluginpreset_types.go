@@ -56,6 +56,9 @@ type PluginPresetStatus struct {
 	ReadyPlugins int `json:"readyPlugins,omitempty"`
 	// FailedPlugins is the number of failed Plugins managed by the PluginPreset.
 	FailedPlugins int `json:"failedPlugins,omitempty"`
+	// IncompatibleClusters lists the selected clusters, whose Kubernetes version does not satisfy the kubeVersion constraint of the PluginDefinition.
+	// No Plugins are created for these clusters.
+	IncompatibleClusters []string `json:"incompatibleClusters,omitempty"`
 }
 
 // ManagedPluginStatus defines the Ready condition of a managed Plugin identified by its name.
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourcePropagationSpec defines the desired state of a ResourcePropagation
+type ResourcePropagationSpec struct {
+	// Secrets selects the Secrets in the namespace of the ResourcePropagation to propagate.
+	// Only Secrets labeled with greenhouse.sap/allow-propagation=true are propagated, kubeconfigs and service account tokens never are.
+	Secrets PropagatedObjectSelector `json:"secrets,omitempty"`
+	// ConfigMaps selects the ConfigMaps in the namespace of the ResourcePropagation to propagate.
+	ConfigMaps PropagatedObjectSelector `json:"configMaps,omitempty"`
+	// ClusterSelector is a label selector to select the Clusters the objects are propagated to.
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+	// Namespaces is the list of namespaces in the remote clusters the objects are propagated to.
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+	// CreateNamespaces when enabled the controller will create the namespaces if they do not exist.
+	// +kubebuilder:default:=false
+	CreateNamespaces bool `json:"createNamespaces,omitempty"`
+}
+
+// PropagatedObjectSelector selects objects by name or by labels. Objects matching either are selected.
+type PropagatedObjectSelector struct {
+	// Names of the objects to select.
+	Names []string `json:"names,omitempty"`
+	// LabelSelector selects objects by their labels.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// IsEmpty returns true if the selector does not select any objects.
+func (s PropagatedObjectSelector) IsEmpty() bool {
+	return len(s.Names) == 0 && s.LabelSelector == nil
+}
+
+// ResourcePropagationStatus defines the observed state of the ResourcePropagation
+type ResourcePropagationStatus struct {
+	// StatusConditions contain the different conditions that constitute the status of the ResourcePropagation.
+	StatusConditions `json:"statusConditions,omitempty"`
+	// PropagatedObjects lists the Secrets and ConfigMaps propagated to the clusters.
+	PropagatedObjects []PropagatedObjectReference `json:"propagatedObjects,omitempty"`
+	// PropagationStatus is the list of clusters the objects are propagated to.
+	// +listType="map"
+	// +listMapKey=clusterName
+	PropagationStatus []PropagationStatus `json:"clusters,omitempty"`
+}
+
+// PropagatedObjectReference references a propagated Secret or ConfigMap.
+type PropagatedObjectReference struct {
+	// Kind of the object, either Secret or ConfigMap.
+	Kind string `json:"kind"`
+	// Name of the object.
+	Name string `json:"name"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.statusConditions.conditions[?(@.type == "Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ResourcePropagation is the Schema for the resourcepropagations API.
+// It propagates Secrets and ConfigMaps of an organization to the selected remote clusters.
+type ResourcePropagation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ResourcePropagationSpec   `json:"spec,omitempty"`
+	Status ResourcePropagationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ResourcePropagationList contains a list of ResourcePropagation
+type ResourcePropagationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourcePropagation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ResourcePropagation{}, &ResourcePropagationList{})
+}
+
+func (rp *ResourcePropagation) GetConditions() StatusConditions {
+	return rp.Status.StatusConditions
+}
+
+func (rp *ResourcePropagation) SetCondition(condition Condition) {
+	rp.Status.StatusConditions.SetConditions(condition)
+}
+
+// SetPropagationStatus updates the ResourcePropagation's PropagationStatus for the Cluster
+func (rp *ResourcePropagation) SetPropagationStatus(cluster string, propagated metav1.ConditionStatus, reason ConditionReason, message string) {
+	condition := NewCondition(ResourcesPropagated, propagated, reason, message)
+	for i, ps := range rp.Status.PropagationStatus {
+		if ps.ClusterName != cluster {
+			continue
+		}
+		if ps.Condition.Status == propagated {
+			// Set the LastTransitionTime to its previous value if the status did not change.
+			condition.LastTransitionTime = ps.Condition.LastTransitionTime
+		}
+		rp.Status.PropagationStatus[i].Condition = condition
+		return
+	}
+	condition.LastTransitionTime = metav1.Now()
+	rp.Status.PropagationStatus = append(rp.Status.PropagationStatus, PropagationStatus{
+		ClusterName: cluster,
+		Condition:   condition,
+	})
+}
+
+// RemovePropagationStatus removes a condition for the Cluster from ResourcePropagation's PropagationStatus
+func (rp *ResourcePropagation) RemovePropagationStatus(cluster string) {
+	rp.Status.PropagationStatus = slices.DeleteFunc(rp.Status.PropagationStatus, func(ps PropagationStatus) bool {
+		return ps.ClusterName == cluster
+	})
+}
+
+const (
+	// ResourcesPropagated is the condition type for the ResourcePropagation when the objects are propagated to the clusters
+	ResourcesPropagated ConditionType = "ResourcesPropagated"
+
+	// PropagationSucceeded is the condition reason for the ResourcePropagation when the objects are successfully propagated
+	PropagationSucceeded ConditionReason = "PropagationSucceeded"
+
+	// PropagationFailed is the condition reason for the ResourcePropagation when not all objects have been successfully propagated
+	PropagationFailed ConditionReason = "PropagationFailed"
+
+	// SourceObjectsFailed is the condition reason for the ResourcePropagation when the selected objects could not be listed
+	SourceObjectsFailed ConditionReason = "SourceObjectsFailed"
+)
@@ -15,6 +15,31 @@ type TeamSpec struct {
 	MappedIDPGroup string `json:"mappedIdPGroup,omitempty"`
 	// URL to join the IdP group.
 	JoinURL string `json:"joinUrl,omitempty"`
+	// ParentTeamRef references the parent Team in the same organization by name.
+	// Members of the Team are considered members of the parent Team for TeamRoleBindings including child teams and for the group claims.
+	ParentTeamRef string `json:"parentTeamRef,omitempty"`
+	// Members lists users managed manually instead of or in addition to the members of the MappedIDPGroup retrieved from SCIM.
+	// Both are merged into the TeamMembership of the Team.
+	// +listType=map
+	// +listMapKey=id
+	// +optional
+	Members []TeamMember `json:"members,omitempty"`
+}
+
+// TeamMember is a user managed manually as member of the Team.
+// The details are optional, the details retrieved from SCIM are preferred for members of the MappedIDPGroup.
+type TeamMember struct {
+	// ID is the unique identifier of the user.
+	ID string `json:"id"`
+	// FirstName of the user.
+	// +optional
+	FirstName string `json:"firstName,omitempty"`
+	// LastName of the user.
+	// +optional
+	LastName string `json:"lastName,omitempty"`
+	// Email of the user.
+	// +optional
+	Email string `json:"email,omitempty"`
 }
 
 // TeamStatus defines the observed state of Team
@@ -27,6 +52,7 @@ type TeamStatus struct {
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Description",type=string,JSONPath=`.spec.description`
 //+kubebuilder:printcolumn:name="IDP Group",type=string,JSONPath=`.spec.mappedIdPGroup`
+//+kubebuilder:printcolumn:name="Parent Team",type=string,JSONPath=`.spec.parentTeamRef`
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Team is the Schema for the teams API
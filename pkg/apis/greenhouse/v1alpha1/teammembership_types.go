@@ -26,8 +26,22 @@ type User struct {
 	LastName string `json:"lastName"`
 	// Email of the user.
 	Email string `json:"email"`
+	// Sources lists where the membership of the user originates from. It is set by Greenhouse in the TeamMembership.
+	// +optional
+	Sources []TeamMemberSource `json:"sources,omitempty"`
 }
 
+// TeamMemberSource is the origin of a member of a Team.
+// +kubebuilder:validation:Enum=SCIM;Manual
+type TeamMemberSource string
+
+const (
+	// TeamMemberSourceSCIM is set for members of the MappedIDPGroup of the Team retrieved from SCIM.
+	TeamMemberSourceSCIM TeamMemberSource = "SCIM"
+	// TeamMemberSourceManual is set for members listed in the Team.
+	TeamMemberSourceManual TeamMemberSource = "Manual"
+)
+
 // TeamMembershipSpec defines the desired state of TeamMembership
 type TeamMembershipSpec struct {
 	// Members list users that are part of a team.
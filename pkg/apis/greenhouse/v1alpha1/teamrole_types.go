@@ -21,13 +21,29 @@ type TeamRoleSpec struct {
 	// Labels are applied to the ClusterRole created on the remote cluster.
 	// This allows using TeamRoles as part of AggregationRules by other TeamRoles
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// RequiresApproval holds back the rbacv1 resources of TeamRoleBindings referencing the TeamRole until another member of their Team
+	// approved them by setting the annotation greenhouse.sap/approved-by to their username. Any change of the spec of a TeamRoleBinding revokes its approval.
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
 }
 
 // TeamRoleStatus defines the observed state of a TeamRole
-type TeamRoleStatus struct{}
+type TeamRoleStatus struct {
+	// StatusConditions contain the different conditions that constitute the status of the TeamRole.
+	StatusConditions `json:"statusConditions,omitempty"`
+	// TeamRoleBindings lists the TeamRoleBindings referencing the TeamRole.
+	TeamRoleBindings []string `json:"teamRoleBindings,omitempty"`
+	// Clusters lists the clusters the ClusterRole rendered from the TeamRole exists on.
+	Clusters []string `json:"clusters,omitempty"`
+	// AggregatedTeamRoles lists the TeamRoles whose Labels are selected by the AggregationRule of the TeamRole.
+	AggregatedTeamRoles []string `json:"aggregatedTeamRoles,omitempty"`
+	// AggregatedBy lists the TeamRoles whose AggregationRule selects the Labels of the TeamRole.
+	AggregatedBy []string `json:"aggregatedBy,omitempty"`
+}
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Rules Propagated",type="string",JSONPath=`.status.statusConditions.conditions[?(@.type == "RulesPropagated")].status`
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // TeamRole is the Schema for the TeamRoles API
@@ -52,7 +68,23 @@ func init() {
 	SchemeBuilder.Register(&TeamRole{}, &TeamRoleList{})
 }
 
+func (tr *TeamRole) GetConditions() StatusConditions {
+	return tr.Status.StatusConditions
+}
+
+func (tr *TeamRole) SetCondition(condition Condition) {
+	tr.Status.StatusConditions.SetConditions(condition)
+}
+
 // GetRBACName returns the name of the rbacv1.ClusterRole that will be created on the remote cluster
 func (tr *TeamRole) GetRBACName() string {
 	return greenhouseapis.RBACPrefix + tr.GetName()
 }
+
+const (
+	// RulesPropagated is the condition type for the TeamRole when the ClusterRoles on all clusters it is bound to contain its latest rules
+	RulesPropagated ConditionType = "RulesPropagated"
+
+	// RulesOutdated is the condition reason for the TeamRole when the ClusterRole on a cluster is missing or does not contain its latest rules
+	RulesOutdated ConditionReason = "RulesOutdated"
+)
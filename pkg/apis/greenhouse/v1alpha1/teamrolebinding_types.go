@@ -4,6 +4,9 @@
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"slices"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,6 +20,11 @@ type TeamRoleBindingSpec struct {
 	TeamRoleRef string `json:"teamRoleRef,omitempty"`
 	// TeamRef references a Greenhouse Team by name
 	TeamRef string `json:"teamRef,omitempty"`
+	// IncludeChildTeams adds the mapped IdP groups of all descendant Teams of the referenced Team to the subjects.
+	IncludeChildTeams bool `json:"includeChildTeams,omitempty"`
+	// IncludeTeamMembers adds the IDs of the members in the TeamMembership of the referenced Team, and of the child Teams if included, as usernames to the subjects.
+	// This allows to grant access to members of Teams whose IdP group is not part of the group claims.
+	IncludeTeamMembers bool `json:"includeTeamMembers,omitempty"`
 	// Usernames defines list of users to add to the (Cluster-)RoleBindings
 	Usernames []string `json:"usernames,omitempty"`
 	// ClusterName is the name of the cluster the rbacv1 resources are created on.
@@ -26,9 +34,16 @@ type TeamRoleBindingSpec struct {
 	// Namespaces is a list of namespaces in the Greenhouse Clusters to apply the RoleBinding to.
 	// If empty, a ClusterRoleBinding will be created on the remote cluster, otherwise a RoleBinding per namespace.
 	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector is a label selector for the namespaces in the Greenhouse Clusters to apply the RoleBinding to.
+	// It is an alternative to Namespaces. RoleBindings are created and removed as matching namespaces appear and disappear.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 	// CreateNamespaces when enabled the controller will create namespaces for RoleBindings if they do not exist.
 	// +kubebuilder:default:=false
 	CreateNamespaces bool `json:"createNamespaces,omitempty"`
+	// NotBefore is the time from which on the rbacv1 resources are created on the clusters. If empty, they are created immediately.
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// ExpiresAt is the time at which the rbacv1 resources are removed from the clusters. If empty, they do not expire.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // TeamRoleBindingStatus defines the observed state of the TeamRoleBinding
@@ -38,7 +53,7 @@ type TeamRoleBindingStatus struct {
 	// PropagationStatus is the list of clusters the TeamRoleBinding is applied to
 	// +listType="map"
 	// +listMapKey=clusterName
-	PropagationStatus []PropagationStatus `json:"clusters,omitempty"`
+	PropagationStatus []TeamRoleBindingPropagationStatus `json:"clusters,omitempty"`
 }
 
 // PropagationStatus defines the observed state of the TeamRoleBinding's associated rbacv1 resources  on a Cluster
@@ -49,10 +64,27 @@ type PropagationStatus struct {
 	Condition `json:"condition,omitempty"`
 }
 
+// TeamRoleBindingPropagationStatus defines the observed state of the TeamRoleBinding's rbacv1 resources on a Cluster, including the RoleBindings per namespace.
+type TeamRoleBindingPropagationStatus struct {
+	PropagationStatus `json:",inline"`
+	// Namespaces is the status of the RoleBindings per namespace on the cluster.
+	Namespaces []NamespacePropagationStatus `json:"namespaces,omitempty"`
+}
+
+// NamespacePropagationStatus defines the observed state of the TeamRoleBinding's RoleBinding in a namespace of a Cluster
+type NamespacePropagationStatus struct {
+	// Namespace is the name of the namespace the RoleBinding is created in.
+	Namespace string `json:"namespace"`
+	// Condition is the Status of the RoleBinding in the namespace
+	Condition `json:"condition,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Team Role",type=string,JSONPath=`.spec.teamRoleRef`
 //+kubebuilder:printcolumn:name="Team",type=string,JSONPath=`.spec.teamRef`
+//+kubebuilder:printcolumn:name="Active",type="string",JSONPath=`.status.statusConditions.conditions[?(@.type == "Active")].status`
+//+kubebuilder:printcolumn:name="Expires At",type="date",JSONPath=`.spec.expiresAt`
 //+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.statusConditions.conditions[?(@.type == "Ready")].status`
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
@@ -101,20 +133,58 @@ func (trb *TeamRoleBinding) SetPropagationStatus(cluster string, rbacReady metav
 		return
 	}
 	condition.LastTransitionTime = metav1.Now()
-	trb.Status.PropagationStatus = append(trb.Status.PropagationStatus, PropagationStatus{
+	trb.Status.PropagationStatus = append(trb.Status.PropagationStatus, TeamRoleBindingPropagationStatus{PropagationStatus: PropagationStatus{
 		ClusterName: cluster,
 		Condition:   condition,
-	})
+	}})
+}
+
+// SetNamespacePropagationStatus replaces the status of the RoleBindings per namespace in the TeamRoleBinding's PropagationStatus for the Cluster.
+// The LastTransitionTime of a namespace is retained if its status did not change.
+func (trb *TeamRoleBinding) SetNamespacePropagationStatus(cluster string, namespaces []NamespacePropagationStatus) {
+	for i, ps := range trb.Status.PropagationStatus {
+		if ps.ClusterName != cluster {
+			continue
+		}
+		for j, ns := range namespaces {
+			idx := slices.IndexFunc(ps.Namespaces, func(previous NamespacePropagationStatus) bool { return previous.Namespace == ns.Namespace })
+			if idx >= 0 && ps.Namespaces[idx].Status == ns.Status {
+				namespaces[j].LastTransitionTime = ps.Namespaces[idx].LastTransitionTime
+			}
+		}
+		trb.Status.PropagationStatus[i].Namespaces = namespaces
+		return
+	}
 }
 
 // RemovePropagationStatus removes a condition for the Cluster from TeamRoleBinding's PropagationStatus
 func (trb *TeamRoleBinding) RemovePropagationStatus(cluster string) {
-	updatedStatus := slices.DeleteFunc(trb.Status.PropagationStatus, func(ps PropagationStatus) bool {
+	updatedStatus := slices.DeleteFunc(trb.Status.PropagationStatus, func(ps TeamRoleBindingPropagationStatus) bool {
 		return ps.ClusterName == cluster
 	})
 	trb.Status.PropagationStatus = updatedStatus
 }
 
+// IsApproved returns true if the TeamRole does not require an approval or the current spec of the TeamRoleBinding has been approved.
+func (trb *TeamRoleBinding) IsApproved(teamRole *TeamRole) bool {
+	if !teamRole.Spec.RequiresApproval {
+		return true
+	}
+	annotations := trb.GetAnnotations()
+	return annotations[greenhouseapis.TeamRoleBindingApprovedByAnnotation] != "" &&
+		annotations[greenhouseapis.TeamRoleBindingApprovedSpecAnnotation] == trb.SpecHash()
+}
+
+// SpecHash returns the hex encoded sha256 digest of the spec, identifying the spec an approval was given for.
+func (trb *TeamRoleBinding) SpecHash() string {
+	data, err := json.Marshal(trb.Spec)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // GetRBACName returns the name of the rbacv1.RoleBinding or rbacv1.ClusterRoleBinding that will be created on the remote cluster
 func (trb *TeamRoleBinding) GetRBACName() string {
 	return greenhouseapis.RBACPrefix + trb.GetName()
@@ -124,9 +194,24 @@ const (
 	// RBACReady is the condition type for the TeamRoleBinding when the rbacv1 resources are ready
 	RBACReady ConditionType = "RBACReady"
 
+	// Active is the condition type for the TeamRoleBinding reflecting whether its rbacv1 resources are deployed at the current time
+	Active ConditionType = "Active"
+
+	// NotYetValid is the condition reason for the TeamRoleBinding when the NotBefore time is not reached yet
+	NotYetValid ConditionReason = "NotYetValid"
+
+	// Expired is the condition reason for the TeamRoleBinding when the ExpiresAt time has passed
+	Expired ConditionReason = "Expired"
+
+	// ApprovalPending is the condition reason for the TeamRoleBinding when it requires an approval which has not been given yet
+	ApprovalPending ConditionReason = "ApprovalPending"
+
 	// RBACReconciled is the condition reason for the TeamRoleBinding when the rbacv1 resources are successfully reconciled
 	RBACReconciled ConditionReason = "RBACReconciled"
 
+	// RBACDriftCorrected is the condition reason for the TeamRoleBinding when rbacv1 resources modified or deleted on the cluster were restored
+	RBACDriftCorrected ConditionReason = "RBACDriftCorrected"
+
 	// RBACReconcileFailed is the condition reason for the TeamRoleBinding when not all of the rbacv1 resources have been successfully reconciled
 	RBACReconcileFailed ConditionReason = "RBACReconcileFailed"
 
@@ -139,6 +224,9 @@ const (
 	// ClusterConnectionFailed is the condition reason for the TeamRoleBinding when the connection to the cluster failed
 	ClusterConnectionFailed ConditionReason = "ClusterConnectionFailed"
 
+	// ClusterInMaintenanceReason is the condition reason for the TeamRoleBinding when the rbacv1 resources are not reconciled as the cluster is in maintenance
+	ClusterInMaintenanceReason ConditionReason = "ClusterInMaintenance"
+
 	// ClusterRoleFailed is the condition reason for the TeamRoleBinding when the ClusterRole could not be created
 	ClusterRoleFailed ConditionReason = "ClusterRoleFailed"
 
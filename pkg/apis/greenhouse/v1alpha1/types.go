@@ -14,21 +14,79 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// HelmChartReference references a Helm Chart in a chart repository.
+// HelmChartReference references a Helm Chart in a chart repository or a Git repository.
 type HelmChartReference struct {
 	// Name of the HelmChart chart.
-	Name string `json:"name"`
+	// Required unless the chart is sourced from Git.
+	Name string `json:"name,omitempty"`
 	// Repository of the HelmChart chart.
-	Repository string `json:"repository"`
+	// Required unless the chart is sourced from Git.
+	Repository string `json:"repository,omitempty"`
 	// Version of the HelmChart chart.
-	Version string `json:"version"`
+	// Required unless the chart is sourced from Git.
+	Version string `json:"version,omitempty"`
+	// Git specifies a Git repository containing the chart.
+	// If set, the chart is loaded from the repository instead of a Helm chart repository.
+	Git *GitChartSource `json:"git,omitempty"`
 }
 
 // String returns the printable HelmChartReference.
 func (h *HelmChartReference) String() string {
+	if h.Git != nil {
+		return h.Git.String()
+	}
 	return fmt.Sprintf("%s/%s:%s", h.Repository, h.Name, h.Version)
 }
 
+// GitChartSource references a Helm chart or manifests in a Git repository.
+type GitChartSource struct {
+	// URL of the Git repository. Both HTTP(S) and SSH URLs are supported.
+	URL string `json:"url"`
+	// Ref is the Git reference to check out. Defaults to the HEAD of the repository.
+	Ref GitReference `json:"ref,omitempty"`
+	// Path to the chart or manifests within the repository. Defaults to the root of the repository.
+	Path string `json:"path,omitempty"`
+	// SecretRef references a Secret containing the credentials to access the repository.
+	// Supported keys are username and password for HTTP(S) and identity and known_hosts for SSH.
+	SecretRef *GitCredentialsReference `json:"secretRef,omitempty"`
+}
+
+// GitReference specifies the branch, tag or commit of a Git repository. At most one of them may be set.
+type GitReference struct {
+	// Branch to check out.
+	Branch string `json:"branch,omitempty"`
+	// Tag to check out.
+	Tag string `json:"tag,omitempty"`
+	// Commit SHA to check out.
+	Commit string `json:"commit,omitempty"`
+}
+
+// String returns the printable GitReference.
+func (r GitReference) String() string {
+	switch {
+	case r.Commit != "":
+		return r.Commit
+	case r.Tag != "":
+		return "tags/" + r.Tag
+	case r.Branch != "":
+		return "heads/" + r.Branch
+	default:
+		return "HEAD"
+	}
+}
+
+// GitCredentialsReference references a Secret containing Git credentials.
+// The Secret is always read from the namespace of the Plugin, as PluginDefinitions are shared by all organizations.
+type GitCredentialsReference struct {
+	// Name of the Secret in the namespace of the Plugin.
+	Name string `json:"name"`
+}
+
+// String returns the printable GitChartSource.
+func (g *GitChartSource) String() string {
+	return fmt.Sprintf("%s//%s@%s", g.URL, g.Path, g.Ref.String())
+}
+
 // ValueFromSource is a valid source for a value.
 type ValueFromSource struct {
 	// Secret references the secret containing the value.
@@ -10,7 +10,9 @@ package v1alpha1
 import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -43,7 +45,7 @@ func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -65,6 +67,91 @@ func (in *Cluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeletionReport) DeepCopyInto(out *ClusterDeletionReport) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PluginPresets != nil {
+		in, out := &in.PluginPresets, &out.PluginPresets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TeamRoleBindings != nil {
+		in, out := &in.TeamRoleBindings, &out.TeamRoleBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlockingTeamRoleBindings != nil {
+		in, out := &in.BlockingTeamRoleBindings, &out.BlockingTeamRoleBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeletionReport.
+func (in *ClusterDeletionReport) DeepCopy() *ClusterDeletionReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeletionReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInventory) DeepCopyInto(out *ClusterInventory) {
+	*out = *in
+	if in.NodesByRole != nil {
+		in, out := &in.NodesByRole, &out.NodesByRole
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.AllocatableCPU = in.AllocatableCPU.DeepCopy()
+	out.AllocatableMemory = in.AllocatableMemory.DeepCopy()
+	if in.KubeletVersions != nil {
+		in, out := &in.KubeletVersions, &out.KubeletVersions
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ContainerRuntimeVersions != nil {
+		in, out := &in.ContainerRuntimeVersions, &out.ContainerRuntimeVersions
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OSImages != nil {
+		in, out := &in.OSImages, &out.OSImages
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CRDGroups != nil {
+		in, out := &in.CRDGroups, &out.CRDGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInventory.
+func (in *ClusterInventory) DeepCopy() *ClusterInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterKubeConfig) DeepCopyInto(out *ClusterKubeConfig) {
 	*out = *in
@@ -110,7 +197,16 @@ func (in *ClusterKubeconfig) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterKubeconfigAuthInfo) DeepCopyInto(out *ClusterKubeconfigAuthInfo) {
 	*out = *in
-	in.AuthProvider.DeepCopyInto(&out.AuthProvider)
+	if in.AuthProvider != nil {
+		in, out := &in.AuthProvider, &out.AuthProvider
+		*out = new(api.AuthProviderConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ClusterKubeconfigExecConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ClientCertificateData != nil {
 		in, out := &in.ClientCertificateData, &out.ClientCertificateData
 		*out = make([]byte, len(*in))
@@ -251,6 +347,26 @@ func (in *ClusterKubeconfigData) DeepCopy() *ClusterKubeconfigData {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKubeconfigExecConfig) DeepCopyInto(out *ClusterKubeconfigExecConfig) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKubeconfigExecConfig.
+func (in *ClusterKubeconfigExecConfig) DeepCopy() *ClusterKubeconfigExecConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKubeconfigExecConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterKubeconfigList) DeepCopyInto(out *ClusterKubeconfigList) {
 	*out = *in
@@ -362,6 +478,26 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMaintenance) DeepCopyInto(out *ClusterMaintenance) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMaintenance.
+func (in *ClusterMaintenance) DeepCopy() *ClusterMaintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMaintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterOptionOverride) DeepCopyInto(out *ClusterOptionOverride) {
 	*out = *in
@@ -409,6 +545,11 @@ func (in *ClusterSelector) DeepCopy() *ClusterSelector {
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
 	out.KubeConfig = in.KubeConfig
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(ClusterMaintenance)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -433,6 +574,16 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(ClusterInventory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionReport != nil {
+		in, out := &in.DeletionReport, &out.DeletionReport
+		*out = new(ClusterDeletionReport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -461,9 +612,65 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitChartSource) DeepCopyInto(out *GitChartSource) {
+	*out = *in
+	out.Ref = in.Ref
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(GitCredentialsReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitChartSource.
+func (in *GitChartSource) DeepCopy() *GitChartSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitChartSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitCredentialsReference) DeepCopyInto(out *GitCredentialsReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitCredentialsReference.
+func (in *GitCredentialsReference) DeepCopy() *GitCredentialsReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GitCredentialsReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitReference) DeepCopyInto(out *GitReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitReference.
+func (in *GitReference) DeepCopy() *GitReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GitReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HelmChartReference) DeepCopyInto(out *HelmChartReference) {
 	*out = *in
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitChartSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmChartReference.
@@ -481,6 +688,11 @@ func (in *HelmReleaseStatus) DeepCopyInto(out *HelmReleaseStatus) {
 	*out = *in
 	in.FirstDeployed.DeepCopyInto(&out.FirstDeployed)
 	in.LastDeployed.DeepCopyInto(&out.LastDeployed)
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]ReleaseObjectCount, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmReleaseStatus.
@@ -493,6 +705,22 @@ func (in *HelmReleaseStatus) DeepCopy() *HelmReleaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedPluginStatus) DeepCopyInto(out *ManagedPluginStatus) {
 	*out = *in
@@ -509,6 +737,103 @@ func (in *ManagedPluginStatus) DeepCopy() *ManagedPluginStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestObjectSelector) DeepCopyInto(out *ManifestObjectSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestObjectSelector.
+func (in *ManifestObjectSelector) DeepCopy() *ManifestObjectSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestObjectSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestReference) DeepCopyInto(out *ManifestReference) {
+	*out = *in
+	in.Git.DeepCopyInto(&out.Git)
+	if in.Replacements != nil {
+		in, out := &in.Replacements, &out.Replacements
+		*out = make([]ManifestReplacement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestReference.
+func (in *ManifestReference) DeepCopy() *ManifestReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestReplacement) DeepCopyInto(out *ManifestReplacement) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ManifestReplacementTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestReplacement.
+func (in *ManifestReplacement) DeepCopy() *ManifestReplacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestReplacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestReplacementTarget) DeepCopyInto(out *ManifestReplacementTarget) {
+	*out = *in
+	out.Select = in.Select
+	if in.FieldPaths != nil {
+		in, out := &in.FieldPaths, &out.FieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestReplacementTarget.
+func (in *ManifestReplacementTarget) DeepCopy() *ManifestReplacementTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestReplacementTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePropagationStatus) DeepCopyInto(out *NamespacePropagationStatus) {
+	*out = *in
+	in.Condition.DeepCopyInto(&out.Condition)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePropagationStatus.
+func (in *NamespacePropagationStatus) DeepCopy() *NamespacePropagationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePropagationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 	*out = *in
@@ -535,6 +860,11 @@ func (in *OIDCConfig) DeepCopyInto(out *OIDCConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.KubeconfigScopes != nil {
+		in, out := &in.KubeconfigScopes, &out.KubeconfigScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfig.
@@ -614,6 +944,11 @@ func (in *OrganizationSpec) DeepCopyInto(out *OrganizationSpec) {
 		*out = new(Authentication)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TeamRolePolicy != nil {
+		in, out := &in.TeamRolePolicy, &out.TeamRolePolicy
+		*out = new(TeamRolePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationSpec.
@@ -675,7 +1010,7 @@ func (in *PluginDefinition) DeepCopyInto(out *PluginDefinition) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginDefinition.
@@ -734,7 +1069,12 @@ func (in *PluginDefinitionSpec) DeepCopyInto(out *PluginDefinitionSpec) {
 	if in.HelmChart != nil {
 		in, out := &in.HelmChart, &out.HelmChart
 		*out = new(HelmChartReference)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = new(ManifestReference)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.UIApplication != nil {
 		in, out := &in.UIApplication, &out.UIApplication
@@ -768,6 +1108,11 @@ func (in *PluginDefinitionSpec) DeepCopy() *PluginDefinitionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginDefinitionStatus) DeepCopyInto(out *PluginDefinitionStatus) {
 	*out = *in
+	if in.IncompatibleClusters != nil {
+		in, out := &in.IncompatibleClusters, &out.IncompatibleClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginDefinitionStatus.
@@ -780,6 +1125,22 @@ func (in *PluginDefinitionStatus) DeepCopy() *PluginDefinitionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginHelmChartStatus) DeepCopyInto(out *PluginHelmChartStatus) {
+	*out = *in
+	in.HelmChartReference.DeepCopyInto(&out.HelmChartReference)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginHelmChartStatus.
+func (in *PluginHelmChartStatus) DeepCopy() *PluginHelmChartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginHelmChartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PluginList) DeepCopyInto(out *PluginList) {
 	*out = *in
@@ -951,6 +1312,11 @@ func (in *PluginPresetStatus) DeepCopyInto(out *PluginPresetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IncompatibleClusters != nil {
+		in, out := &in.IncompatibleClusters, &out.IncompatibleClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginPresetStatus.
@@ -995,8 +1361,8 @@ func (in *PluginStatus) DeepCopyInto(out *PluginStatus) {
 	}
 	if in.HelmChart != nil {
 		in, out := &in.HelmChart, &out.HelmChart
-		*out = new(HelmChartReference)
-		**out = **in
+		*out = new(PluginHelmChartStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.UIApplication != nil {
 		in, out := &in.UIApplication, &out.UIApplication
@@ -1028,6 +1394,46 @@ func (in *PluginStatus) DeepCopy() *PluginStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropagatedObjectReference) DeepCopyInto(out *PropagatedObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagatedObjectReference.
+func (in *PropagatedObjectReference) DeepCopy() *PropagatedObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PropagatedObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropagatedObjectSelector) DeepCopyInto(out *PropagatedObjectSelector) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagatedObjectSelector.
+func (in *PropagatedObjectSelector) DeepCopy() *PropagatedObjectSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(PropagatedObjectSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PropagationStatus) DeepCopyInto(out *PropagationStatus) {
 	*out = *in
@@ -1044,6 +1450,131 @@ func (in *PropagationStatus) DeepCopy() *PropagationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseObjectCount) DeepCopyInto(out *ReleaseObjectCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseObjectCount.
+func (in *ReleaseObjectCount) DeepCopy() *ReleaseObjectCount {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseObjectCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePropagation) DeepCopyInto(out *ResourcePropagation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePropagation.
+func (in *ResourcePropagation) DeepCopy() *ResourcePropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourcePropagation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePropagationList) DeepCopyInto(out *ResourcePropagationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ResourcePropagation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePropagationList.
+func (in *ResourcePropagationList) DeepCopy() *ResourcePropagationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePropagationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourcePropagationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePropagationSpec) DeepCopyInto(out *ResourcePropagationSpec) {
+	*out = *in
+	in.Secrets.DeepCopyInto(&out.Secrets)
+	in.ConfigMaps.DeepCopyInto(&out.ConfigMaps)
+	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePropagationSpec.
+func (in *ResourcePropagationSpec) DeepCopy() *ResourcePropagationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePropagationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePropagationStatus) DeepCopyInto(out *ResourcePropagationStatus) {
+	*out = *in
+	in.StatusConditions.DeepCopyInto(&out.StatusConditions)
+	if in.PropagatedObjects != nil {
+		in, out := &in.PropagatedObjects, &out.PropagatedObjects
+		*out = make([]PropagatedObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PropagationStatus != nil {
+		in, out := &in.PropagationStatus, &out.PropagationStatus
+		*out = make([]PropagationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePropagationStatus.
+func (in *ResourcePropagationStatus) DeepCopy() *ResourcePropagationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePropagationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SCIMConfig) DeepCopyInto(out *SCIMConfig) {
 	*out = *in
@@ -1124,7 +1655,7 @@ func (in *Team) DeepCopyInto(out *Team) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -1178,6 +1709,21 @@ func (in *TeamList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamMember) DeepCopyInto(out *TeamMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamMember.
+func (in *TeamMember) DeepCopy() *TeamMember {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamMembership) DeepCopyInto(out *TeamMembership) {
 	*out = *in
@@ -1243,7 +1789,9 @@ func (in *TeamMembershipSpec) DeepCopyInto(out *TeamMembershipSpec) {
 	if in.Members != nil {
 		in, out := &in.Members, &out.Members
 		*out = make([]User, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1287,7 +1835,7 @@ func (in *TeamRole) DeepCopyInto(out *TeamRole) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamRole.
@@ -1367,6 +1915,29 @@ func (in *TeamRoleBindingList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamRoleBindingPropagationStatus) DeepCopyInto(out *TeamRoleBindingPropagationStatus) {
+	*out = *in
+	in.PropagationStatus.DeepCopyInto(&out.PropagationStatus)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespacePropagationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamRoleBindingPropagationStatus.
+func (in *TeamRoleBindingPropagationStatus) DeepCopy() *TeamRoleBindingPropagationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamRoleBindingPropagationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamRoleBindingSpec) DeepCopyInto(out *TeamRoleBindingSpec) {
 	*out = *in
@@ -1381,6 +1952,19 @@ func (in *TeamRoleBindingSpec) DeepCopyInto(out *TeamRoleBindingSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamRoleBindingSpec.
@@ -1399,7 +1983,7 @@ func (in *TeamRoleBindingStatus) DeepCopyInto(out *TeamRoleBindingStatus) {
 	in.StatusConditions.DeepCopyInto(&out.StatusConditions)
 	if in.PropagationStatus != nil {
 		in, out := &in.PropagationStatus, &out.PropagationStatus
-		*out = make([]PropagationStatus, len(*in))
+		*out = make([]TeamRoleBindingPropagationStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1448,6 +2032,36 @@ func (in *TeamRoleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamRolePolicy) DeepCopyInto(out *TeamRolePolicy) {
+	*out = *in
+	if in.SensitiveResources != nil {
+		in, out := &in.SensitiveResources, &out.SensitiveResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SensitiveVerbs != nil {
+		in, out := &in.SensitiveVerbs, &out.SensitiveVerbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExemptTeamRoles != nil {
+		in, out := &in.ExemptTeamRoles, &out.ExemptTeamRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamRolePolicy.
+func (in *TeamRolePolicy) DeepCopy() *TeamRolePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamRolePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamRoleSpec) DeepCopyInto(out *TeamRoleSpec) {
 	*out = *in
@@ -1485,6 +2099,27 @@ func (in *TeamRoleSpec) DeepCopy() *TeamRoleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamRoleStatus) DeepCopyInto(out *TeamRoleStatus) {
 	*out = *in
+	in.StatusConditions.DeepCopyInto(&out.StatusConditions)
+	if in.TeamRoleBindings != nil {
+		in, out := &in.TeamRoleBindings, &out.TeamRoleBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AggregatedTeamRoles != nil {
+		in, out := &in.AggregatedTeamRoles, &out.AggregatedTeamRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AggregatedBy != nil {
+		in, out := &in.AggregatedBy, &out.AggregatedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamRoleStatus.
@@ -1500,6 +2135,11 @@ func (in *TeamRoleStatus) DeepCopy() *TeamRoleStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]TeamMember, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSpec.
@@ -1519,7 +2159,9 @@ func (in *TeamStatus) DeepCopyInto(out *TeamStatus) {
 	if in.Members != nil {
 		in, out := &in.Members, &out.Members
 		*out = make([]User, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1551,6 +2193,11 @@ func (in *UIApplicationReference) DeepCopy() *UIApplicationReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]TeamMemberSource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new User.
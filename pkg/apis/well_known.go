@@ -24,6 +24,12 @@ const (
 	// This kubeconfig should be used by Greenhouse controllers and their kubernetes clients to access the remote cluster.
 	GreenHouseKubeConfigKey = "greenhousekubeconfig"
 
+	// AgentTokenKey is the key for the token authenticating the cluster agent in the secret of a cluster with access mode agent.
+	AgentTokenKey = "greenhouseagenttoken"
+
+	// ExtraCABundleKey is the key for PEM encoded certificate authorities trusted in addition to the one of the kubeconfig in the secret of type greenhouse.sap/kubeconfig.
+	ExtraCABundleKey = "greenhouseextracabundle"
+
 	// LabelKeyPluginPreset is used to identify the PluginPreset managing the plugin.
 	LabelKeyPluginPreset = "greenhouse.sap/pluginpreset"
 
@@ -36,6 +42,12 @@ const (
 	// LabelKeyCluster is used to identify corresponding Cluster for the resource.
 	LabelKeyCluster = "greenhouse.sap/cluster"
 
+	// LabelKeyClusterK8sMinor is set on clusters to the <major>.<minor> Kubernetes version of the cluster.
+	LabelKeyClusterK8sMinor = "greenhouse.sap/k8s-minor"
+
+	// LabelKeyClusterProvider is set on clusters to the provider detected from the providerID of the nodes.
+	LabelKeyClusterProvider = "greenhouse.sap/provider"
+
 	// LabelKeyExposeService is applied to services that are part of a PluginDefinitions Helm chart to expose them via the central Greenhouse infrastructure.
 	LabelKeyExposeService = "greenhouse.sap/expose"
 
@@ -51,6 +63,12 @@ const (
 	// LabelKeyRole is the key of the label that is used to identify the Role.
 	LabelKeyRole = "greenhouse.sap/role"
 
+	// LabelKeyResourcePropagation is the key of the label that is used to identify objects propagated by a ResourcePropagation.
+	LabelKeyResourcePropagation = "greenhouse.sap/resourcepropagation"
+
+	// LabelKeyAllowPropagation must be set to "true" on a Secret to allow propagating it with a ResourcePropagation.
+	LabelKeyAllowPropagation = "greenhouse.sap/allow-propagation"
+
 	// RBACPrefix is the prefix for the Role and RoleBinding names.
 	RBACPrefix = "greenhouse:"
 
@@ -74,6 +92,22 @@ const (
 	ClusterConnectivityAnnotation     = "greenhouse.sap/cluster-connectivity"
 	ClusterConnectivityKubeconfig     = "kubeconfig"
 	ClusterConnectivityOIDC           = "oidc"
+	// SecretClusterAccessModeAnnotation on the secret of a cluster selects the access mode of the cluster. Defaults to direct.
+	SecretClusterAccessModeAnnotation = "greenhouse.sap/cluster-access-mode"
+	// SecretProxyURLAnnotation on the secret of a cluster configures an HTTP(S) or SOCKS5 proxy used to connect to the API server.
+	SecretProxyURLAnnotation = "greenhouse.sap/proxy-url"
+	// SecretTLSServerNameAnnotation on the secret of a cluster overrides the server name used to verify the certificate of the API server.
+	SecretTLSServerNameAnnotation = "greenhouse.sap/tls-server-name"
+	// RotateCredentialsAnnotation on a cluster requests the immediate rotation of the credentials used by Greenhouse. It is removed once the rotation succeeded.
+	RotateCredentialsAnnotation = "greenhouse.sap/rotate-credentials"
+	// TeamRoleBindingRequestedByAnnotation on a TeamRoleBinding contains the username of the user who created it.
+	TeamRoleBindingRequestedByAnnotation = "greenhouse.sap/requested-by"
+	// TeamRoleBindingApprovedByAnnotation on a TeamRoleBinding requiring an approval contains the username of the team member who approved it.
+	TeamRoleBindingApprovedByAnnotation = "greenhouse.sap/approved-by"
+	// TeamRoleBindingApprovedSpecAnnotation on an approved TeamRoleBinding contains the hash of the spec the approval was given for. It is set by Greenhouse.
+	TeamRoleBindingApprovedSpecAnnotation = "greenhouse.sap/approved-spec"
+	// RBACAppliedHashAnnotation on the rbacv1 resources deployed by a TeamRoleBinding contains the hash of the state applied by Greenhouse. It is used to detect drift.
+	RBACAppliedHashAnnotation = "greenhouse.sap/applied-hash"
 )
 
 const (
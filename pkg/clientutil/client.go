@@ -49,19 +49,23 @@ func NewK8sClientFromRestClientGetter(restClientGetter genericclioptions.RESTCli
 
 // NewK8sClientFromCluster returns a client.Client based on the given clusters kubeconfig secret.
 func NewK8sClientFromCluster(ctx context.Context, c client.Client, cluster *greenhousev1alpha1.Cluster) (client.Client, error) {
-	secret := new(corev1.Secret)
-	if err := c.Get(ctx, types.NamespacedName{Name: cluster.GetSecretName(), Namespace: cluster.GetNamespace()}, secret); err != nil {
+	cfg, err := NewRestConfigFromCluster(ctx, c, cluster)
+	if err != nil {
 		return nil, err
 	}
+	return NewK8sClient(cfg)
+}
 
-	restClientGetter, err := NewRestClientGetterFromSecret(secret, cluster.GetNamespace(), WithPersistentConfig())
-	if err != nil {
+// NewRestConfigFromCluster returns a rest.Config based on the given clusters kubeconfig secret.
+func NewRestConfigFromCluster(ctx context.Context, c client.Client, cluster *greenhousev1alpha1.Cluster) (*rest.Config, error) {
+	secret := new(corev1.Secret)
+	if err := c.Get(ctx, types.NamespacedName{Name: cluster.GetSecretName(), Namespace: cluster.GetNamespace()}, secret); err != nil {
 		return nil, err
 	}
 
-	remoteRestClient, err := NewK8sClientFromRestClientGetter(restClientGetter)
+	restClientGetter, err := NewRestClientGetterFromSecret(secret, cluster.GetNamespace(), WithPersistentConfig())
 	if err != nil {
 		return nil, err
 	}
-	return remoteRestClient, nil
+	return restClientGetter.ToRESTConfig()
 }
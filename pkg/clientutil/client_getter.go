@@ -10,12 +10,17 @@ import (
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/tunnel"
 )
 
 // Implements the genericclioptions.RESTClientGetter interface and additionally allows to access the KubeConfig from Bytes/Secret.
@@ -40,6 +45,11 @@ type RestClientGetter struct {
 	// overrides is used to override entries in the kubeconfig
 	overrides *clientcmd.ConfigOverrides
 
+	// tunnelCluster is set for clusters with access mode agent. Connections are dialed through the tunnel of the cluster agent.
+	tunnelCluster *types.NamespacedName
+	// connectionSettings override the proxy and TLS settings of the kubeconfig.
+	connectionSettings *ConnectionSettings
+
 	clientConfig     clientcmd.ClientConfig
 	clientConfigLock sync.Mutex
 
@@ -154,6 +164,14 @@ func NewRestClientGetterFromSecret(secret *corev1.Secret, namespace string, opts
 		namespace:          namespace,
 		discoveryBurst:     discoveryBurst,
 	}
+	if secret.GetAnnotations()[greenhouseapis.SecretClusterAccessModeAnnotation] == string(greenhousev1alpha1.ClusterAccessModeAgent) {
+		g.tunnelCluster = &types.NamespacedName{Namespace: secret.GetNamespace(), Name: secret.GetName()}
+	}
+	connectionSettings, err := ConnectionSettingsFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	g.connectionSettings = connectionSettings
 
 	for _, opt := range opts {
 		opt(g)
@@ -200,6 +218,12 @@ func (cg *RestClientGetter) ToRESTConfig() (*rest.Config, error) {
 		cfg.QPS = cg.runtimeOpts.QPS
 		cfg.Burst = cg.runtimeOpts.Burst
 	}
+	if err := cg.connectionSettings.ApplyToRESTConfig(cfg); err != nil {
+		return nil, fmt.Errorf("RestClientGetter failed to apply connection settings: %w", err)
+	}
+	if cg.tunnelCluster != nil {
+		cfg.Dial = tunnel.DefaultDialer.DialFunc(*cg.tunnelCluster)
+	}
 	return cfg, nil
 }
 
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package clientutil
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+)
+
+// ConnectionSettings configure how the API server of a remote cluster is reached.
+// They are read from the secret of the cluster and take precedence over the values of the kubeconfig.
+type ConnectionSettings struct {
+	// ProxyURL is the HTTP(S) or SOCKS5 proxy used to connect to the API server.
+	ProxyURL *url.URL
+	// TLSServerName is used to verify the certificate of the API server.
+	TLSServerName string
+	// ExtraCAData is trusted in addition to the certificate authority of the kubeconfig.
+	// If the kubeconfig does not contain a certificate authority, only ExtraCAData is trusted.
+	ExtraCAData []byte
+}
+
+// ConnectionSettingsFromSecret returns the connection settings of the cluster secret. Nil is returned if none are set.
+func ConnectionSettingsFromSecret(secret *corev1.Secret) (*ConnectionSettings, error) {
+	annotations := secret.GetAnnotations()
+	settings := &ConnectionSettings{
+		TLSServerName: annotations[greenhouseapis.SecretTLSServerNameAnnotation],
+		ExtraCAData:   secret.Data[greenhouseapis.ExtraCABundleKey],
+	}
+	if proxyURL := annotations[greenhouseapis.SecretProxyURLAnnotation]; proxyURL != "" {
+		u, err := ParseProxyURL(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s/%s: %w", secret.GetNamespace(), secret.GetName(), err)
+		}
+		settings.ProxyURL = u
+	}
+	if len(settings.ExtraCAData) > 0 && !x509.NewCertPool().AppendCertsFromPEM(settings.ExtraCAData) {
+		return nil, fmt.Errorf("secret %s/%s: %s does not contain a PEM encoded certificate", secret.GetNamespace(), secret.GetName(), greenhouseapis.ExtraCABundleKey)
+	}
+	if settings.ProxyURL == nil && settings.TLSServerName == "" && len(settings.ExtraCAData) == 0 {
+		return nil, nil
+	}
+	return settings, nil
+}
+
+// ParseProxyURL parses the URL of a proxy. Only the schemes http, https and socks5 are supported.
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy URL scheme %q, must be one of http, https, socks5", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", proxyURL)
+	}
+	return u, nil
+}
+
+// ApplyToRESTConfig overrides the connection settings of the rest config.
+func (s *ConnectionSettings) ApplyToRESTConfig(cfg *rest.Config) error {
+	if s == nil {
+		return nil
+	}
+	if s.ProxyURL != nil {
+		cfg.Proxy = http.ProxyURL(s.ProxyURL)
+	}
+	if s.TLSServerName != "" {
+		cfg.TLSClientConfig.ServerName = s.TLSServerName
+	}
+	if len(s.ExtraCAData) > 0 {
+		caData := cfg.TLSClientConfig.CAData
+		if len(caData) == 0 && cfg.TLSClientConfig.CAFile != "" {
+			var err error
+			if caData, err = os.ReadFile(cfg.TLSClientConfig.CAFile); err != nil {
+				return err
+			}
+			cfg.TLSClientConfig.CAFile = ""
+		}
+		cfg.TLSClientConfig.CAData = bytes.Join([][]byte{caData, s.ExtraCAData}, []byte("\n"))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package clientutil_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+	"github.com/cloudoperators/greenhouse/pkg/test"
+)
+
+var _ = Describe("Testing the connection settings of cluster secrets", func() {
+	It("should apply the proxy and TLS settings of the secret to the rest config", func() {
+		secret := returnTestKubeConfigSecret(greenhouseapis.SecretTypeKubeConfig, greenhouseapis.KubeConfigKey, test.KubeConfig)
+		secret.Annotations = map[string]string{
+			greenhouseapis.SecretProxyURLAnnotation:      "socks5://proxy.example.com:1080",
+			greenhouseapis.SecretTLSServerNameAnnotation: "api.internal.example.com",
+		}
+		secret.Data[greenhouseapis.ExtraCABundleKey] = test.Cfg.CAData
+
+		restClientGetter, err := clientutil.NewRestClientGetterFromSecret(&secret, "")
+		Expect(err).ToNot(HaveOccurred(), "there should be no error creating the RestClientGetter")
+		cfg, err := restClientGetter.ToRESTConfig()
+		Expect(err).ToNot(HaveOccurred(), "there should be no error creating a rest config")
+
+		Expect(cfg.Proxy).ToNot(BeNil(), "the proxy should be set")
+		proxyURL, err := cfg.Proxy(&http.Request{})
+		Expect(err).ToNot(HaveOccurred(), "there should be no error resolving the proxy")
+		Expect(proxyURL.String()).To(Equal("socks5://proxy.example.com:1080"), "the proxy URL of the secret should be used")
+		Expect(cfg.ServerName).To(Equal("api.internal.example.com"), "the TLS server name of the secret should be used")
+		Expect(string(cfg.CAData)).To(ContainSubstring(string(test.Cfg.CAData)), "the extra CA bundle should be trusted")
+	})
+
+	It("should keep the kubeconfig settings if the secret has no connection settings", func() {
+		secret := returnTestKubeConfigSecret(greenhouseapis.SecretTypeKubeConfig, greenhouseapis.KubeConfigKey, test.KubeConfig)
+
+		restClientGetter, err := clientutil.NewRestClientGetterFromSecret(&secret, "")
+		Expect(err).ToNot(HaveOccurred(), "there should be no error creating the RestClientGetter")
+		cfg, err := restClientGetter.ToRESTConfig()
+		Expect(err).ToNot(HaveOccurred(), "there should be no error creating a rest config")
+		Expect(cfg.Proxy).To(BeNil(), "no proxy should be set")
+		Expect(cfg.ServerName).To(BeEmpty(), "no TLS server name should be set")
+	})
+
+	DescribeTable("should reject invalid connection settings",
+		func(annotations map[string]string, extraCAData []byte) {
+			secret := returnTestKubeConfigSecret(greenhouseapis.SecretTypeKubeConfig, greenhouseapis.KubeConfigKey, test.KubeConfig)
+			secret.Annotations = annotations
+			if extraCAData != nil {
+				secret.Data[greenhouseapis.ExtraCABundleKey] = extraCAData
+			}
+			_, err := clientutil.NewRestClientGetterFromSecret(&secret, "")
+			Expect(err).To(HaveOccurred(), "there should be an error creating the RestClientGetter")
+		},
+		Entry("unsupported proxy scheme", map[string]string{greenhouseapis.SecretProxyURLAnnotation: "ftp://proxy.example.com"}, nil),
+		Entry("proxy without host", map[string]string{greenhouseapis.SecretProxyURLAnnotation: "http://"}, nil),
+		Entry("extra CA bundle without certificate", nil, []byte("not a certificate")),
+	)
+})
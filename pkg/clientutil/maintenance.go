@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package clientutil
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+// ParseMaintenanceSchedule parses the standard five-field cron expression of a maintenance window.
+// Time zones are rejected, as schedules are evaluated in UTC.
+func ParseMaintenanceSchedule(schedule string) (cron.Schedule, error) {
+	if strings.HasPrefix(schedule, "TZ=") || strings.HasPrefix(schedule, "CRON_TZ=") {
+		return nil, fmt.Errorf("time zones are not supported, schedules are evaluated in UTC")
+	}
+	return cron.ParseStandard(schedule)
+}
+
+// IsClusterInMaintenance returns whether the cluster is in maintenance at the given time and when the maintenance ends.
+// The end is zero if the maintenance is enabled until it is disabled again.
+// Windows with an invalid schedule are ignored, they are rejected by the Cluster webhook.
+func IsClusterInMaintenance(cluster *greenhousev1alpha1.Cluster, now time.Time) (bool, time.Time) {
+	maintenance := cluster.Spec.Maintenance
+	if maintenance == nil {
+		return false, time.Time{}
+	}
+	if maintenance.Enabled {
+		return true, time.Time{}
+	}
+	now = now.UTC()
+	var end time.Time
+	for _, window := range maintenance.Windows {
+		schedule, err := ParseMaintenanceSchedule(window.Schedule)
+		if err != nil || window.Duration.Duration <= 0 {
+			continue
+		}
+		// The window is active if it started after now-duration and not after now.
+		// The latest start before now determines the end of overlapping windows.
+		for start := schedule.Next(now.Add(-window.Duration.Duration)); !start.IsZero() && !start.After(now); start = schedule.Next(start) {
+			if windowEnd := start.Add(window.Duration.Duration); windowEnd.After(end) {
+				end = windowEnd
+			}
+		}
+	}
+	return !end.IsZero(), end
+}
+
+// MaintenanceTracker collects the clusters skipped during a reconciliation as they are in maintenance.
+type MaintenanceTracker struct {
+	now time.Time
+	// clusters maps the names of the skipped clusters to their description in the condition message.
+	clusters map[string]string
+	// earliestEnd is the end of the first maintenance window to close. Zero if all skipped clusters are in maintenance until disabled.
+	earliestEnd time.Time
+}
+
+// NewMaintenanceTracker returns a MaintenanceTracker evaluating the maintenance of clusters at the given time.
+func NewMaintenanceTracker(now time.Time) *MaintenanceTracker {
+	return &MaintenanceTracker{now: now, clusters: make(map[string]string)}
+}
+
+// Skip returns true if the cluster is in maintenance and changes to it must not be applied.
+func (t *MaintenanceTracker) Skip(cluster *greenhousev1alpha1.Cluster) bool {
+	inMaintenance, end := IsClusterInMaintenance(cluster, t.now)
+	if !inMaintenance {
+		return false
+	}
+	if end.IsZero() {
+		t.clusters[cluster.GetName()] = cluster.GetName()
+		return true
+	}
+	t.clusters[cluster.GetName()] = fmt.Sprintf("%s (until %s)", cluster.GetName(), end.Format(time.RFC3339))
+	if t.earliestEnd.IsZero() || end.Before(t.earliestEnd) {
+		t.earliestEnd = end
+	}
+	return true
+}
+
+// conditionedObject is an object carrying status conditions.
+type conditionedObject interface {
+	GetConditions() greenhousev1alpha1.StatusConditions
+	SetCondition(greenhousev1alpha1.Condition)
+}
+
+// SetCondition sets the ClusterInMaintenance condition on the object.
+// The condition is only reset if it was set before, so objects of clusters never in maintenance do not carry it.
+func (t *MaintenanceTracker) SetCondition(obj conditionedObject) {
+	conditions := obj.GetConditions()
+	if len(t.clusters) == 0 && conditions.GetConditionByType(greenhousev1alpha1.ClusterInMaintenance) == nil {
+		return
+	}
+	obj.SetCondition(t.Condition())
+}
+
+// Condition returns the ClusterInMaintenance condition listing the skipped clusters.
+func (t *MaintenanceTracker) Condition() greenhousev1alpha1.Condition {
+	if len(t.clusters) == 0 {
+		return greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ClusterInMaintenance, "", "")
+	}
+	clusters := slices.Sorted(maps.Values(t.clusters))
+	return greenhousev1alpha1.TrueCondition(greenhousev1alpha1.ClusterInMaintenance, "",
+		"changes are paused for clusters in maintenance: "+strings.Join(clusters, ", "))
+}
+
+// RequeueAfter returns the duration until the first maintenance window of the skipped clusters ends.
+// Zero is returned if no window ends, the end of a maintenance without a window is observed by watching the Cluster.
+func (t *MaintenanceTracker) RequeueAfter() time.Duration {
+	if t.earliestEnd.IsZero() {
+		return 0
+	}
+	return t.earliestEnd.Sub(t.now)
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package clientutil_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+func clusterWithMaintenance(name string, maintenance *greenhousev1alpha1.ClusterMaintenance) *greenhousev1alpha1.Cluster {
+	return &greenhousev1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       greenhousev1alpha1.ClusterSpec{Maintenance: maintenance},
+	}
+}
+
+var _ = Describe("Testing the maintenance of clusters", func() {
+	// 2024-01-06 is a Saturday
+	saturdayNight := &greenhousev1alpha1.ClusterMaintenance{
+		Windows: []greenhousev1alpha1.MaintenanceWindow{
+			{Schedule: "0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		},
+	}
+
+	DescribeTable("should determine whether the cluster is in maintenance",
+		func(maintenance *greenhousev1alpha1.ClusterMaintenance, now time.Time, expectedActive bool, expectedEnd time.Time) {
+			active, end := clientutil.IsClusterInMaintenance(clusterWithMaintenance("test-cluster", maintenance), now)
+			Expect(active).To(Equal(expectedActive), "the maintenance state should match")
+			Expect(end).To(Equal(expectedEnd), "the end of the maintenance should match")
+		},
+		Entry("without maintenance", nil, time.Date(2024, time.January, 6, 23, 0, 0, 0, time.UTC), false, time.Time{}),
+		Entry("enabled maintenance", &greenhousev1alpha1.ClusterMaintenance{Enabled: true}, time.Date(2024, time.January, 6, 23, 0, 0, 0, time.UTC), true, time.Time{}),
+		Entry("before the window", saturdayNight, time.Date(2024, time.January, 6, 21, 59, 0, 0, time.UTC), false, time.Time{}),
+		Entry("at the start of the window", saturdayNight, time.Date(2024, time.January, 6, 22, 0, 0, 0, time.UTC), true, time.Date(2024, time.January, 7, 2, 0, 0, 0, time.UTC)),
+		Entry("within the window", saturdayNight, time.Date(2024, time.January, 7, 1, 30, 0, 0, time.UTC), true, time.Date(2024, time.January, 7, 2, 0, 0, 0, time.UTC)),
+		Entry("at the end of the window", saturdayNight, time.Date(2024, time.January, 7, 2, 0, 0, 0, time.UTC), false, time.Time{}),
+		Entry("invalid schedule", &greenhousev1alpha1.ClusterMaintenance{
+			Windows: []greenhousev1alpha1.MaintenanceWindow{{Schedule: "invalid", Duration: metav1.Duration{Duration: time.Hour}}},
+		}, time.Date(2024, time.January, 6, 23, 0, 0, 0, time.UTC), false, time.Time{}),
+		// A step in the day of month restricts the day, so either day field matching is sufficient. 2024-01-02 is a Tuesday.
+		Entry("step in the day of month", &greenhousev1alpha1.ClusterMaintenance{
+			Windows: []greenhousev1alpha1.MaintenanceWindow{{Schedule: "0 22 */2 * 6", Duration: metav1.Duration{Duration: time.Hour}}},
+		}, time.Date(2024, time.January, 3, 22, 30, 0, 0, time.UTC), true, time.Date(2024, time.January, 3, 23, 0, 0, 0, time.UTC)),
+		Entry("schedule with time zone", &greenhousev1alpha1.ClusterMaintenance{
+			Windows: []greenhousev1alpha1.MaintenanceWindow{{Schedule: "CRON_TZ=Europe/Berlin 0 22 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}}},
+		}, time.Date(2024, time.January, 6, 23, 0, 0, 0, time.UTC), false, time.Time{}),
+	)
+
+	It("should track the skipped clusters and requeue at the end of the first window", func() {
+		now := time.Date(2024, time.January, 6, 23, 0, 0, 0, time.UTC)
+		tracker := clientutil.NewMaintenanceTracker(now)
+		Expect(tracker.Skip(clusterWithMaintenance("cluster-a", nil))).To(BeFalse(), "a cluster without maintenance should not be skipped")
+		condition := tracker.Condition()
+		Expect(condition.IsFalse()).To(BeTrue(), "the condition should be false if no cluster was skipped")
+
+		Expect(tracker.Skip(clusterWithMaintenance("cluster-b", saturdayNight))).To(BeTrue(), "a cluster within its window should be skipped")
+		Expect(tracker.Skip(clusterWithMaintenance("cluster-c", &greenhousev1alpha1.ClusterMaintenance{Enabled: true}))).To(BeTrue(), "a cluster with enabled maintenance should be skipped")
+
+		condition = tracker.Condition()
+		Expect(condition.IsTrue()).To(BeTrue(), "the condition should be true")
+		Expect(condition.Message).To(ContainSubstring("cluster-b (until 2024-01-07T02:00:00Z)"), "the end of the window should be reported")
+		Expect(condition.Message).To(ContainSubstring("cluster-c"), "the cluster with enabled maintenance should be reported")
+		Expect(tracker.RequeueAfter()).To(Equal(3*time.Hour), "the reconciliation should be requeued at the end of the window")
+	})
+
+	It("should only reset the condition if it was set before", func() {
+		plugin := &greenhousev1alpha1.Plugin{}
+		clientutil.NewMaintenanceTracker(time.Now()).SetCondition(plugin)
+		Expect(plugin.Status.GetConditionByType(greenhousev1alpha1.ClusterInMaintenance)).To(BeNil(), "the condition should not be set if the cluster was never in maintenance")
+
+		tracker := clientutil.NewMaintenanceTracker(time.Now())
+		Expect(tracker.Skip(clusterWithMaintenance("cluster-a", &greenhousev1alpha1.ClusterMaintenance{Enabled: true}))).To(BeTrue(), "a cluster with enabled maintenance should be skipped")
+		tracker.SetCondition(plugin)
+		Expect(plugin.Status.GetConditionByType(greenhousev1alpha1.ClusterInMaintenance).IsTrue()).To(BeTrue(), "the condition should be set while the cluster is in maintenance")
+
+		clientutil.NewMaintenanceTracker(time.Now()).SetCondition(plugin)
+		Expect(plugin.Status.GetConditionByType(greenhousev1alpha1.ClusterInMaintenance).IsFalse()).To(BeTrue(), "the condition should be reset once the maintenance ended")
+	})
+})
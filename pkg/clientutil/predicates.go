@@ -17,11 +17,11 @@ import (
 	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
 )
 
-// PredicatePluginWithHelmSpec filters PluginDefinitions without an HelmChart specification.
+// PredicatePluginWithHelmSpec filters PluginDefinitions without an HelmChart or Manifests specification.
 var PredicatePluginWithHelmSpec = func() predicate.Funcs {
 	return predicate.NewPredicateFuncs(func(o client.Object) bool {
 		if pluginDefinition, ok := o.(*greenhousev1alpha1.PluginDefinition); ok {
-			return pluginDefinition.Spec.HelmChart != nil
+			return pluginDefinition.Spec.ChartReference() != nil
 		}
 		return false
 	})
@@ -48,10 +48,10 @@ func PredicateSecretContainsKey(key string) predicate.Predicate {
 	})
 }
 
-func PredicateClusterByAccessMode(accessMode greenhousev1alpha1.ClusterAccessMode) predicate.Predicate {
+func PredicateClusterByAccessMode(accessModes ...greenhousev1alpha1.ClusterAccessMode) predicate.Predicate {
 	return predicate.NewPredicateFuncs(func(o client.Object) bool {
 		if cluster, ok := o.(*greenhousev1alpha1.Cluster); ok {
-			return cluster.Spec.AccessMode == accessMode
+			return slices.Contains(accessModes, cluster.Spec.AccessMode)
 		}
 		return false
 	})
@@ -133,3 +133,17 @@ func PredicatePluginWithStatusReadyChange() predicate.Predicate {
 		GenericFunc: func(_ event.GenericEvent) bool { return false },
 	}
 }
+
+// PredicateClusterKubernetesVersionChange admits created and deleted clusters and updates changing the Kubernetes version of a cluster.
+func PredicateClusterKubernetesVersionChange() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, okOld := e.ObjectOld.(*greenhousev1alpha1.Cluster)
+			newCluster, okNew := e.ObjectNew.(*greenhousev1alpha1.Cluster)
+			if !okOld || !okNew {
+				return false
+			}
+			return oldCluster.Status.KubernetesVersion != newCluster.Status.KubernetesVersion
+		},
+	}
+}
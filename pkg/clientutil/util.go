@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"helm.sh/helm/v3/pkg/chartutil"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
@@ -54,6 +55,15 @@ func GetKubernetesVersion(restClientGetter genericclioptions.RESTClientGetter) (
 	return dc.ServerVersion()
 }
 
+// IsKubeVersionCompatible returns true if the Kubernetes version satisfies the kubeVersion constraint of a chart.
+// An empty constraint or an unknown Kubernetes version is considered compatible.
+func IsKubeVersionCompatible(constraint, kubeVersion string) bool {
+	if constraint == "" || kubeVersion == "" {
+		return true
+	}
+	return chartutil.IsCompatibleRange(constraint, kubeVersion)
+}
+
 // Searches for a directory upwards starting from the given path.
 func FindDirUpwards(path, dirName string, maxSteps int) (string, error) {
 	return findRecursively(path, dirName, maxSteps, 0)
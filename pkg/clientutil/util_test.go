@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package clientutil_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+var _ = Describe("Testing the Kubernetes version compatibility", func() {
+	DescribeTable("should check the Kubernetes version against the kubeVersion constraint",
+		func(constraint, kubeVersion string, expected bool) {
+			Expect(clientutil.IsKubeVersionCompatible(constraint, kubeVersion)).To(Equal(expected), "the compatibility should match")
+		},
+		Entry("without constraint", "", "v1.30.0", true),
+		Entry("with unknown version", ">= 1.27.0-0", "", true),
+		Entry("within the constraint", ">= 1.27.0-0", "v1.30.2", true),
+		Entry("within the constraint with pre-release", ">= 1.27.0-0", "v1.30.2-gke.100", true),
+		Entry("outside the constraint", "< 1.29.0-0", "v1.30.2", false),
+	)
+})
@@ -5,6 +5,9 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
 )
 
 func init() {
@@ -15,3 +18,20 @@ var clusterCmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "Cluster related commands",
 }
+
+// addGreenhouseClusterFlags adds the flags identifying a cluster in Greenhouse.
+func addGreenhouseClusterFlags(cmd *cobra.Command, greenhouseKubeConfig, orgName, clusterName *string) {
+	cmd.Flags().StringVar(greenhouseKubeConfig, "greenhouse-kubeconfig", "", "The kubeconfig of the greenhouse cluster")
+	cmd.Flags().StringVar(orgName, "org", clientutil.GetEnvOrDefault("GREENHOUSE_ORG", ""), "The organization name to use. Can be set via GREENHOUSE_ORG env var")
+	cmd.Flags().StringVar(clusterName, "cluster-name", clientutil.GetEnvOrDefault("GREENHOUSE_CLUSTER_NAME", ""), "The cluster name to use. Can be set via GREENHOUSE_CLUSTER_NAME env var")
+	for _, name := range []string{"greenhouse-kubeconfig", "org", "cluster-name"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			setupLog.Error(err, "Flag could not set as required", name)
+		}
+	}
+}
+
+// newGreenhouseClient returns a client for the Greenhouse cluster from the given kubeconfig.
+func newGreenhouseClient(greenhouseKubeConfig string) (client.Client, error) {
+	return clientutil.NewK8sClient(getClientKubeconfig(&greenhouseKubeConfig))
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -33,7 +34,6 @@ import (
 	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
 	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
 	"github.com/cloudoperators/greenhouse/pkg/clientutil"
-	clustercontroller "github.com/cloudoperators/greenhouse/pkg/controllers/cluster/utils"
 )
 
 const (
@@ -71,6 +71,14 @@ type newClusterBootstrapOptions struct {
 	clusterName          string
 	greenhouseKubeConfig string
 	onBehafOfUser        string
+	accessMode           string
+	tunnelURL            string
+	agentImage           string
+	connectivity         string
+	oidcUsernamePrefix   string
+	outputDir            string
+	tokenFile            string
+	dryRun               bool
 }
 
 func init() {
@@ -93,6 +101,14 @@ func newClusterBootstrapCmd() *cobra.Command {
 				return err
 			}
 			o.kubeconfig = cmd.Flag("kubeconfig").Value.String()
+			if o.outputDir != "" {
+				// Writing the manifests does not access the clusters, the kubeconfig only provides the API server and its certificate.
+				o.customerConfig = *getKubeconfigOrDie(o.kubecontext)
+				return nil
+			}
+			if o.greenhouseKubeConfig == "" {
+				return errors.New("--greenhouse-kubeconfig is required unless the manifests are written to --output-dir")
+			}
 			return o.permissionCheck()
 		},
 	}
@@ -105,6 +121,14 @@ func newClusterBootstrapCmd() *cobra.Command {
 	bootstrapCmd.Flags().StringVar(&o.clusterName, "cluster-name", clientutil.GetEnvOrDefault("GREENHOUSE_CLUSTER_NAME", ""), "The cluster name to use. Can be set via GREENHOUSE_CLUSTER_NAME env var")
 	bootstrapCmd.Flags().StringVar(&o.greenhouseKubeConfig, "greenhouse-kubeconfig", "", "The kubeconfig of the greenhouse cluster")
 	bootstrapCmd.Flags().StringVar(&o.onBehafOfUser, "as", "", "The user to impersonate for the operation")
+	bootstrapCmd.Flags().StringVar(&o.accessMode, "access-mode", string(greenhouseapisv1alpha1.ClusterAccessModeDirect), "How Greenhouse accesses the cluster, either direct or agent. With agent an agent connecting the cluster to Greenhouse is installed")
+	bootstrapCmd.Flags().StringVar(&o.tunnelURL, "tunnel-url", clientutil.GetEnvOrDefault("GREENHOUSE_TUNNEL_URL", ""), "The URL of the Greenhouse tunnel endpoint the agent connects to. Required for access mode agent")
+	bootstrapCmd.Flags().StringVar(&o.agentImage, "agent-image", defaultAgentImage, "The image of the agent installed for access mode agent")
+	bootstrapCmd.Flags().StringVar(&o.connectivity, "connectivity", greenhouseapis.ClusterConnectivityKubeconfig, "How Greenhouse authenticates against the cluster, either kubeconfig or oidc. With oidc the cluster must trust the ServiceAccount issuer of Greenhouse")
+	bootstrapCmd.Flags().StringVar(&o.oidcUsernamePrefix, "oidc-username-prefix", defaultOIDCUsernamePrefix, "The prefix of the username claim mapping configured for the Greenhouse issuer in the cluster. Used for connectivity oidc")
+	bootstrapCmd.Flags().StringVar(&o.outputDir, "output-dir", "", "Write the manifests for the cluster and for Greenhouse to this directory instead of applying them")
+	bootstrapCmd.Flags().StringVar(&o.tokenFile, "token-file", "", "The file containing the token of the greenhouse ServiceAccount. Required with --output-dir for connectivity kubeconfig")
+	bootstrapCmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Check the permissions and report the resources which would be created or updated without applying them")
 
 	// Mark required flags
 	if err := bootstrapCmd.MarkFlagRequired("org"); err != nil {
@@ -113,7 +137,7 @@ func newClusterBootstrapCmd() *cobra.Command {
 	if err := bootstrapCmd.MarkFlagRequired("bootstrap-kubeconfig"); err != nil {
 		setupLog.Error(err, "Flag could not set as required", "bootstrap-kubeconfig")
 	}
-	bootstrapCmd.MarkFlagsRequiredTogether("org", "greenhouse-kubeconfig")
+	bootstrapCmd.MarkFlagsMutuallyExclusive("output-dir", "dry-run")
 	// Silence usage to avoid confusing customers
 	bootstrapCmd.SilenceUsage = true
 
@@ -185,11 +209,39 @@ func (o *newClusterBootstrapOptions) fillDefaults() error {
 	if err := validateClusterName(o.clusterName, 40); err != nil {
 		return err
 	}
+	switch greenhouseapisv1alpha1.ClusterAccessMode(o.accessMode) {
+	case greenhouseapisv1alpha1.ClusterAccessModeDirect:
+	case greenhouseapisv1alpha1.ClusterAccessModeAgent:
+		if o.tunnelURL == "" {
+			return errors.New("--tunnel-url is required for access mode agent")
+		}
+	default:
+		return fmt.Errorf("unknown access mode %q, must be one of direct, agent", o.accessMode)
+	}
+	switch o.connectivity {
+	case greenhouseapis.ClusterConnectivityKubeconfig, greenhouseapis.ClusterConnectivityOIDC:
+	default:
+		return fmt.Errorf("unknown connectivity %q, must be one of kubeconfig, oidc", o.connectivity)
+	}
 
 	return nil
 }
 
 func (o *newClusterBootstrapOptions) run() error {
+	switch {
+	case o.outputDir != "":
+		return o.writeManifests()
+	case o.dryRun:
+		return o.reportChanges(ctx)
+	case o.isOIDCConnectivity():
+		setupLog.Info("Bootstraping cluster with connectivity oidc", "clusterName", o.clusterName, "orgName", o.orgName)
+		if err := o.applyOIDCManifests(ctx); err != nil {
+			return err
+		}
+		setupLog.Info("Bootstraping cluster finished", "clusterName", o.clusterName, "orgName", o.orgName)
+		return nil
+	}
+
 	bootstrapped := o.isClusterAlreadyBootstraped(ctx)
 	if !bootstrapped {
 		setupLog.Info("Bootstraping cluster", "clusterName", o.clusterName, "orgName", o.orgName)
@@ -206,9 +258,21 @@ func (o *newClusterBootstrapOptions) run() error {
 		return err
 	}
 
-	if err := o.createOrUpdateClusterObject(ctx, bootstrapped); err != nil {
+	var agentToken string
+	if o.isAgentAccessMode() {
+		var err error
+		if agentToken, err = generateAgentToken(); err != nil {
+			return err
+		}
+	}
+	if err := o.createOrUpdateClusterObject(ctx, bootstrapped, agentToken); err != nil {
 		return err
 	}
+	if o.isAgentAccessMode() {
+		if err := o.installAgentInRemoteCluster(ctx, agentToken); err != nil {
+			return err
+		}
+	}
 
 	setupLog.Info("Bootstraping cluster finished", "clusterName", o.clusterName, "orgName", o.orgName)
 	return nil
@@ -343,22 +407,13 @@ func (o *newClusterBootstrapOptions) isClusterAlreadyBootstraped(ctx context.Con
 	return true
 }
 
-func (o *newClusterBootstrapOptions) createOrUpdateClusterObject(ctx context.Context, bootstrapped bool) error {
+func (o *newClusterBootstrapOptions) createOrUpdateClusterObject(ctx context.Context, bootstrapped bool, agentToken string) error {
 	token, err := o.createServiceAccountToken()
 	if err != nil {
 		return err
 	}
 
-	generateKubeconfig := &clustercontroller.KubeConfigHelper{
-		Host:          o.customerConfig.Host,
-		TLSServerName: o.customerConfig.TLSClientConfig.ServerName,
-		CAData:        o.customerConfig.CAData,
-		BearerToken:   token,
-		Username:      serviceAccountName,
-		Namespace:     o.orgName,
-	}
-
-	genKubeConfig, err := clientcmd.Write(generateKubeconfig.RestConfigToAPIConfig(o.clusterName))
+	genKubeConfig, err := o.generateKubeconfig(token)
 	if err != nil {
 		return err
 	}
@@ -366,8 +421,14 @@ func (o *newClusterBootstrapOptions) createOrUpdateClusterObject(ctx context.Con
 	clusterSecret.Name = o.clusterName
 	clusterSecret.Namespace = o.orgName
 	clusterSecret.Type = greenhouseapis.SecretTypeKubeConfig
+	if o.isAgentAccessMode() {
+		clusterSecret.Annotations = map[string]string{greenhouseapis.SecretClusterAccessModeAnnotation: o.accessMode}
+	}
 	if !bootstrapped {
 		clusterSecret.Data = map[string][]byte{greenhouseapis.KubeConfigKey: genKubeConfig}
+		if agentToken != "" {
+			clusterSecret.Data[greenhouseapis.AgentTokenKey] = []byte(agentToken)
+		}
 		if err = o.ghClient.Create(ctx, clusterSecret); err != nil {
 			return err
 		}
@@ -375,6 +436,9 @@ func (o *newClusterBootstrapOptions) createOrUpdateClusterObject(ctx context.Con
 	} else {
 		clusterSecret.Data = map[string][]byte{greenhouseapis.KubeConfigKey: genKubeConfig}
 		clusterSecret.Data = map[string][]byte{greenhouseapis.GreenHouseKubeConfigKey: genKubeConfig}
+		if agentToken != "" {
+			clusterSecret.Data[greenhouseapis.AgentTokenKey] = []byte(agentToken)
+		}
 		if err = o.ghClient.Update(ctx, clusterSecret); err != nil {
 			return err
 		}
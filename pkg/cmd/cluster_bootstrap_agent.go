@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+const (
+	agentName              = "greenhouse-agent"
+	agentTokenEnv          = "GREENHOUSE_AGENT_TOKEN"
+	defaultAgentImage      = "ghcr.io/cloudoperators/greenhouse:main"
+	inClusterAPIServerHost = "https://kubernetes.default.svc"
+	// agentTokenHashAnnotation rolls out the agent if the token changes.
+	agentTokenHashAnnotation = "greenhouse.sap/agent-token-hash"
+)
+
+func (o *newClusterBootstrapOptions) isAgentAccessMode() bool {
+	return o.accessMode == string(greenhouseapisv1alpha1.ClusterAccessModeAgent)
+}
+
+// generateAgentToken returns a random token authenticating the agent against Greenhouse.
+func generateAgentToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// installAgentInRemoteCluster creates the agent connecting the remote cluster to Greenhouse.
+func (o *newClusterBootstrapOptions) installAgentInRemoteCluster(ctx context.Context, agentToken string) error {
+	desiredSecret, desiredDeployment := o.agentManifests(agentToken)
+	var secret = new(corev1.Secret)
+	secret.Name = desiredSecret.Name
+	secret.Namespace = desiredSecret.Namespace
+	result, err := clientutil.CreateOrPatch(ctx, o.customerClient, secret, func() error {
+		secret.Type = desiredSecret.Type
+		secret.Data = desiredSecret.Data
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logResult(result, "agent secret", secret.Name)
+
+	var deployment = new(appsv1.Deployment)
+	deployment.Name = desiredDeployment.Name
+	deployment.Namespace = desiredDeployment.Namespace
+	result, err = clientutil.CreateOrPatch(ctx, o.customerClient, deployment, func() error {
+		deployment.Labels = desiredDeployment.Labels
+		deployment.Spec.Replicas = desiredDeployment.Spec.Replicas
+		deployment.Spec.Selector = desiredDeployment.Spec.Selector
+		deployment.Spec.Template = desiredDeployment.Spec.Template
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logResult(result, "agent deployment", deployment.Name)
+	return nil
+}
+
+// agentManifests returns the secret holding the agent token and the deployment of the agent.
+func (o *newClusterBootstrapOptions) agentManifests(agentToken string) (*corev1.Secret, *appsv1.Deployment) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: agentName, Namespace: o.orgName},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{agentTokenEnv: []byte(agentToken)},
+	}
+
+	tokenHash := sha256.Sum256([]byte(agentToken))
+	labels := map[string]string{"app.kubernetes.io/name": agentName}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: agentName, Namespace: o.orgName, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: map[string]string{agentTokenHashAnnotation: hex.EncodeToString(tokenHash[:])},
+				},
+				Spec: corev1.PodSpec{
+					// The agent only forwards connections, it does not access the API server itself.
+					AutomountServiceAccountToken: ptr.To(false),
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot:   ptr.To(true),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    "agent",
+							Image:   o.agentImage,
+							Command: []string{"/cluster-agent"},
+							Args: []string{
+								"--greenhouse-url=" + o.tunnelURL,
+								"--cluster-namespace=" + o.orgName,
+								"--cluster-name=" + o.clusterName,
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name: agentTokenEnv,
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+											Key:                  agentTokenEnv,
+										},
+									},
+								},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: ptr.To(false),
+								ReadOnlyRootFilesystem:   ptr.To(true),
+								Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return secret, deployment
+}
+
+func logResult(result clientutil.OperationResult, kind, name string) {
+	switch result {
+	case clientutil.OperationResultNone:
+		setupLog.Info(kind+" already exists", "name", name)
+	case clientutil.OperationResultCreated:
+		setupLog.Info("created "+kind, "name", name)
+	case clientutil.OperationResultUpdated:
+		setupLog.Info("updated "+kind, "name", name)
+	}
+}
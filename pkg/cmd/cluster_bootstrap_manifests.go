@@ -0,0 +1,300 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+	clustercontroller "github.com/cloudoperators/greenhouse/pkg/controllers/cluster/utils"
+)
+
+const (
+	// defaultOIDCUsernamePrefix is the prefix of the username claim mapping in the structured authentication configuration of the cluster.
+	defaultOIDCUsernamePrefix = "greenhouse:"
+	remoteManifestsFile       = "remote-cluster.yaml"
+	greenhouseManifestsFile   = "greenhouse.yaml"
+	fieldManager              = "greenhousectl"
+)
+
+func (o *newClusterBootstrapOptions) isOIDCConnectivity() bool {
+	return o.connectivity == greenhouseapis.ClusterConnectivityOIDC
+}
+
+// remoteManifests returns the objects created in the cluster to be onboarded.
+func (o *newClusterBootstrapOptions) remoteManifests(agentToken string) []client.Object {
+	objs := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: o.orgName}},
+	}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+			APIGroup: rbacv1.GroupName,
+		},
+	}
+	if o.isOIDCConnectivity() {
+		// Greenhouse authenticates with a token of the ServiceAccount named after the cluster in the organization's namespace.
+		clusterRoleBinding.Name = fmt.Sprintf("greenhouse-%s-oidc-access", o.clusterName)
+		clusterRoleBinding.Subjects = []rbacv1.Subject{
+			{
+				Kind:     rbacv1.UserKind,
+				APIGroup: rbacv1.GroupName,
+				Name:     fmt.Sprintf("%ssystem:serviceaccount:%s:%s", o.oidcUsernamePrefix, o.orgName, o.clusterName),
+			},
+		}
+		objs = append(objs, clusterRoleBinding)
+	} else {
+		clusterRoleBinding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAccountName,
+				Namespace: o.orgName,
+			},
+		}
+		objs = append(objs,
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName, Namespace: o.orgName}},
+			clusterRoleBinding,
+		)
+	}
+	if o.isAgentAccessMode() {
+		secret, deployment := o.agentManifests(agentToken)
+		objs = append(objs, secret, deployment)
+	}
+	return objs
+}
+
+// greenhouseManifests returns the secret and the Cluster created in the organization's namespace in Greenhouse.
+// The token is only used for the kubeconfig connectivity.
+func (o *newClusterBootstrapOptions) greenhouseManifests(token, agentToken string) ([]client.Object, error) {
+	clusterSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: o.clusterName, Namespace: o.orgName, Annotations: map[string]string{}},
+		Data:       map[string][]byte{},
+	}
+	cluster := &greenhouseapisv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        o.clusterName,
+			Namespace:   o.orgName,
+			Annotations: map[string]string{greenhouseapis.ClusterConnectivityAnnotation: o.connectivity},
+		},
+		Spec: greenhouseapisv1alpha1.ClusterSpec{AccessMode: greenhouseapisv1alpha1.ClusterAccessMode(o.accessMode)},
+	}
+
+	if o.isOIDCConnectivity() {
+		caData, err := o.customerCAData()
+		if err != nil {
+			return nil, err
+		}
+		clusterSecret.Type = greenhouseapis.SecretTypeOIDCConfig
+		clusterSecret.Annotations[greenhouseapis.SecretAPIServerURLAnnotation] = o.customerConfig.Host
+		if serverName := o.customerConfig.TLSClientConfig.ServerName; serverName != "" {
+			clusterSecret.Annotations[greenhouseapis.SecretTLSServerNameAnnotation] = serverName
+		}
+		if o.isAgentAccessMode() {
+			// The agent connects to the API server within the cluster.
+			clusterSecret.Annotations[greenhouseapis.SecretAPIServerURLAnnotation] = inClusterAPIServerHost
+			delete(clusterSecret.Annotations, greenhouseapis.SecretTLSServerNameAnnotation)
+		}
+		// The bootstrap controller expects the certificate base64 encoded.
+		clusterSecret.Data[greenhouseapis.SecretAPIServerCAKey] = []byte(base64.StdEncoding.EncodeToString(caData))
+	} else {
+		kubeconfig, err := o.generateKubeconfig(token)
+		if err != nil {
+			return nil, err
+		}
+		clusterSecret.Type = greenhouseapis.SecretTypeKubeConfig
+		clusterSecret.Data[greenhouseapis.KubeConfigKey] = kubeconfig
+	}
+	if o.isAgentAccessMode() {
+		clusterSecret.Annotations[greenhouseapis.SecretClusterAccessModeAnnotation] = o.accessMode
+		clusterSecret.Data[greenhouseapis.AgentTokenKey] = []byte(agentToken)
+	}
+	return []client.Object{clusterSecret, cluster}, nil
+}
+
+// generateKubeconfig returns the kubeconfig used by Greenhouse to access the cluster with the token of the greenhouse ServiceAccount.
+func (o *newClusterBootstrapOptions) generateKubeconfig(token string) ([]byte, error) {
+	caData, err := o.customerCAData()
+	if err != nil {
+		return nil, err
+	}
+	host, tlsServerName := o.customerConfig.Host, o.customerConfig.TLSClientConfig.ServerName
+	if o.isAgentAccessMode() {
+		// The agent connects to the API server within the cluster.
+		host, tlsServerName = inClusterAPIServerHost, ""
+	}
+	generateKubeconfig := &clustercontroller.KubeConfigHelper{
+		Host:          host,
+		TLSServerName: tlsServerName,
+		CAData:        caData,
+		BearerToken:   token,
+		Username:      serviceAccountName,
+		Namespace:     o.orgName,
+	}
+	return clientcmd.Write(generateKubeconfig.RestConfigToAPIConfig(o.clusterName))
+}
+
+// customerCAData returns the certificate authority of the cluster to be onboarded, reading it from the file referenced in the kubeconfig if necessary.
+func (o *newClusterBootstrapOptions) customerCAData() ([]byte, error) {
+	cfg := rest.CopyConfig(&o.customerConfig)
+	if err := rest.LoadTLSFiles(cfg); err != nil {
+		return nil, err
+	}
+	return cfg.CAData, nil
+}
+
+// writeManifests writes the manifests for the cluster to be onboarded and for Greenhouse to the output directory instead of applying them.
+func (o *newClusterBootstrapOptions) writeManifests() error {
+	var token, agentToken string
+	if !o.isOIDCConnectivity() {
+		if o.tokenFile == "" {
+			return errors.New("--token-file is required to write the manifests for the kubeconfig connectivity, use --connectivity oidc to onboard without a token")
+		}
+		tokenBytes, err := os.ReadFile(o.tokenFile)
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(string(tokenBytes))
+	}
+	if o.isAgentAccessMode() {
+		var err error
+		if agentToken, err = generateAgentToken(); err != nil {
+			return err
+		}
+	}
+	greenhouseObjs, err := o.greenhouseManifests(token, agentToken)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(o.outputDir, 0o755); err != nil {
+		return err
+	}
+	if err := writeManifestsFile(filepath.Join(o.outputDir, remoteManifestsFile), o.remoteManifests(agentToken)); err != nil {
+		return err
+	}
+	if err := writeManifestsFile(filepath.Join(o.outputDir, greenhouseManifestsFile), greenhouseObjs); err != nil {
+		return err
+	}
+	setupLog.Info("Wrote manifests", "clusterName", o.clusterName, "orgName", o.orgName, "directory", o.outputDir)
+	return nil
+}
+
+// writeManifestsFile writes the objects as multi-document YAML. The file may contain secrets and is only readable by the owner.
+func writeManifestsFile(path string, objs []client.Object) error {
+	var buf bytes.Buffer
+	for i, obj := range objs {
+		if err := setGroupVersionKind(obj); err != nil {
+			return err
+		}
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(b)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// reportManifests logs which of the objects would be created or updated without changing anything.
+func reportManifests(ctx context.Context, c client.Client, host string, objs []client.Object) error {
+	for _, obj := range objs {
+		if err := setGroupVersionKind(obj); err != nil {
+			return err
+		}
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		existing, ok := obj.DeepCopyObject().(client.Object)
+		if !ok {
+			return fmt.Errorf("unexpected object %T", obj)
+		}
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+		switch {
+		case apierrors.IsNotFound(err):
+			setupLog.Info("would create "+kind, "name", obj.GetName(), "namespace", obj.GetNamespace(), "clusterName", host)
+		case err != nil:
+			return err
+		default:
+			setupLog.Info("would update "+kind, "name", obj.GetName(), "namespace", obj.GetNamespace(), "clusterName", host)
+		}
+	}
+	return nil
+}
+
+// applyManifests applies the objects using server-side apply.
+func applyManifests(ctx context.Context, c client.Client, objs []client.Object) error {
+	for _, obj := range objs {
+		if err := setGroupVersionKind(obj); err != nil {
+			return err
+		}
+		if err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+			return err
+		}
+		setupLog.Info("applied "+obj.GetObjectKind().GroupVersionKind().Kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+	}
+	return nil
+}
+
+func setGroupVersionKind(obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, clientutil.Scheme)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+// reportChanges reports the objects the bootstrap would create or update in both clusters.
+func (o *newClusterBootstrapOptions) reportChanges(ctx context.Context) error {
+	setupLog.Info("Dry run, no changes are applied", "clusterName", o.clusterName, "orgName", o.orgName)
+	if err := reportManifests(ctx, o.customerClient, o.customerConfig.Host, o.remoteManifests("")); err != nil {
+		return err
+	}
+	greenhouseObjs, err := o.greenhouseManifests("", "")
+	if err != nil {
+		return err
+	}
+	return reportManifests(ctx, o.ghClient, o.ghConfig.Host, greenhouseObjs)
+}
+
+// applyOIDCManifests onboards the cluster with OIDC connectivity. No token is requested, Greenhouse authenticates with a token of its own ServiceAccount.
+func (o *newClusterBootstrapOptions) applyOIDCManifests(ctx context.Context) error {
+	var agentToken string
+	if o.isAgentAccessMode() {
+		var err error
+		if agentToken, err = generateAgentToken(); err != nil {
+			return err
+		}
+	}
+	if err := applyManifests(ctx, o.customerClient, o.remoteManifests(agentToken)); err != nil {
+		return err
+	}
+	greenhouseObjs, err := o.greenhouseManifests("", agentToken)
+	if err != nil {
+		return err
+	}
+	return applyManifests(ctx, o.ghClient, greenhouseObjs)
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+var _ = Describe("Generate the manifests of the cluster bootstrap", func() {
+	var o *newClusterBootstrapOptions
+
+	BeforeEach(func() {
+		o = &newClusterBootstrapOptions{
+			customerConfig: rest.Config{
+				Host:            "https://api.test-cluster.example.com",
+				TLSClientConfig: rest.TLSClientConfig{CAData: []byte("test-ca")},
+			},
+			orgName:            "test-org",
+			clusterName:        "test-cluster",
+			accessMode:         string(greenhousev1alpha1.ClusterAccessModeDirect),
+			connectivity:       greenhouseapis.ClusterConnectivityKubeconfig,
+			oidcUsernamePrefix: defaultOIDCUsernamePrefix,
+		}
+	})
+
+	It("should generate the manifests for the connectivity oidc", func() {
+		o.connectivity = greenhouseapis.ClusterConnectivityOIDC
+
+		remoteObjs := o.remoteManifests("")
+		Expect(remoteObjs).To(HaveLen(2), "there should be the namespace and the ClusterRoleBinding")
+		clusterRoleBinding, ok := remoteObjs[1].(*rbacv1.ClusterRoleBinding)
+		Expect(ok).To(BeTrue(), "the second object should be the ClusterRoleBinding")
+		Expect(clusterRoleBinding.Name).To(Equal("greenhouse-test-cluster-oidc-access"), "the ClusterRoleBinding should be named after the cluster")
+		Expect(clusterRoleBinding.Subjects).To(ConsistOf(rbacv1.Subject{
+			Kind:     rbacv1.UserKind,
+			APIGroup: rbacv1.GroupName,
+			Name:     "greenhouse:system:serviceaccount:test-org:test-cluster",
+		}), "the ClusterRoleBinding should bind the prefixed ServiceAccount of the cluster")
+
+		greenhouseObjs, err := o.greenhouseManifests("", "")
+		Expect(err).ToNot(HaveOccurred(), "there should be no error generating the Greenhouse manifests")
+		secret, ok := greenhouseObjs[0].(*corev1.Secret)
+		Expect(ok).To(BeTrue(), "the first object should be the secret")
+		Expect(secret.Type).To(Equal(greenhouseapis.SecretTypeOIDCConfig), "the secret should be of type oidc")
+		Expect(secret.Annotations).To(HaveKeyWithValue(greenhouseapis.SecretAPIServerURLAnnotation, "https://api.test-cluster.example.com"), "the API server URL should be annotated")
+		Expect(secret.Data).To(HaveKeyWithValue(greenhouseapis.SecretAPIServerCAKey, []byte(base64.StdEncoding.EncodeToString([]byte("test-ca")))), "the certificate should be base64 encoded")
+		cluster, ok := greenhouseObjs[1].(*greenhousev1alpha1.Cluster)
+		Expect(ok).To(BeTrue(), "the second object should be the cluster")
+		Expect(cluster.Annotations).To(HaveKeyWithValue(greenhouseapis.ClusterConnectivityAnnotation, greenhouseapis.ClusterConnectivityOIDC), "the connectivity should be annotated")
+	})
+
+	It("should write the manifests for the connectivity kubeconfig", func() {
+		o.outputDir = GinkgoT().TempDir()
+		o.tokenFile = filepath.Join(o.outputDir, "token")
+		Expect(os.WriteFile(o.tokenFile, []byte("test-token\n"), 0o600)).To(Succeed(), "there should be no error writing the token")
+
+		Expect(o.writeManifests()).To(Succeed(), "there should be no error writing the manifests")
+
+		remote, err := os.ReadFile(filepath.Join(o.outputDir, remoteManifestsFile))
+		Expect(err).ToNot(HaveOccurred(), "there should be no error reading the remote manifests")
+		Expect(string(remote)).To(ContainSubstring("kind: Namespace"), "the namespace should be written")
+		Expect(string(remote)).To(ContainSubstring("kind: ServiceAccount"), "the ServiceAccount should be written")
+		Expect(string(remote)).To(ContainSubstring("kind: ClusterRoleBinding"), "the ClusterRoleBinding should be written")
+
+		greenhouseObjs, err := o.greenhouseManifests("test-token", "")
+		Expect(err).ToNot(HaveOccurred(), "there should be no error generating the Greenhouse manifests")
+		secret, ok := greenhouseObjs[0].(*corev1.Secret)
+		Expect(ok).To(BeTrue(), "the first object should be the secret")
+		Expect(secret.Type).To(Equal(greenhouseapis.SecretTypeKubeConfig), "the secret should be of type kubeconfig")
+		kubeconfig, err := clientcmd.Load(secret.Data[greenhouseapis.KubeConfigKey])
+		Expect(err).ToNot(HaveOccurred(), "the kubeconfig should be valid")
+		Expect(kubeconfig.AuthInfos).To(HaveKeyWithValue(serviceAccountName, HaveField("Token", "test-token")), "the kubeconfig should contain the token")
+
+		greenhouse, err := os.ReadFile(filepath.Join(o.outputDir, greenhouseManifestsFile))
+		Expect(err).ToNot(HaveOccurred(), "there should be no error reading the Greenhouse manifests")
+		Expect(string(greenhouse)).To(ContainSubstring("kind: Cluster\n"), "the cluster should be written")
+		Expect(string(greenhouse)).To(ContainSubstring("type: "+string(greenhouseapis.SecretTypeKubeConfig)), "the secret should be written")
+	})
+
+	It("should require the token file for the connectivity kubeconfig", func() {
+		o.outputDir = GinkgoT().TempDir()
+		Expect(o.writeManifests()).ToNot(Succeed(), "there should be an error without a token file")
+	})
+})
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+type clusterDeleteOptions struct {
+	ghClient             client.Client
+	greenhouseKubeConfig string
+	orgName              string
+	clusterName          string
+	schedule             string
+	cancel               bool
+}
+
+func init() {
+	clusterCmd.AddCommand(newClusterDeleteCmd())
+}
+
+func newClusterDeleteCmd() *cobra.Command {
+	o := &clusterDeleteOptions{}
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Schedule or cancel the deletion of a cluster in Greenhouse",
+		Long: "Schedule the deletion of a cluster in Greenhouse or cancel a scheduled deletion.\n" +
+			"The Plugins and TeamRoleBindings affected by the deletion of the cluster are listed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if o.ghClient, err = newGreenhouseClient(o.greenhouseKubeConfig); err != nil {
+				return err
+			}
+			return o.run(cmd.OutOrStdout())
+		},
+	}
+
+	addGreenhouseClusterFlags(deleteCmd, &o.greenhouseKubeConfig, &o.orgName, &o.clusterName)
+	deleteCmd.Flags().StringVar(&o.schedule, "schedule", "", "When to delete the cluster, either in the format '2006-01-02 15:04:05' (UTC) or as duration from now, e.g. 24h. Defaults to 48h")
+	deleteCmd.Flags().BoolVar(&o.cancel, "cancel", false, "Cancel the scheduled deletion of the cluster")
+	deleteCmd.MarkFlagsMutuallyExclusive("schedule", "cancel")
+	deleteCmd.SilenceUsage = true
+
+	return deleteCmd
+}
+
+func (o *clusterDeleteOptions) run(out io.Writer) error {
+	cluster := new(greenhouseapisv1alpha1.Cluster)
+	if err := o.ghClient.Get(ctx, client.ObjectKey{Namespace: o.orgName, Name: o.clusterName}, cluster); err != nil {
+		return err
+	}
+
+	base := cluster.DeepCopy()
+	if o.cancel {
+		annotations := cluster.GetAnnotations()
+		delete(annotations, greenhouseapis.MarkClusterDeletionAnnotation)
+		delete(annotations, greenhouseapis.ScheduleClusterDeletionAnnotation)
+		cluster.SetAnnotations(annotations)
+		if err := o.ghClient.Patch(ctx, cluster, client.MergeFrom(base)); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(out, "Cancelled the deletion of cluster %s/%s\n", o.orgName, o.clusterName)
+		return err
+	}
+
+	// Without a schedule, the webhook schedules the deletion with the default delay.
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, greenhouseapis.MarkClusterDeletionAnnotation, "true")
+	if o.schedule != "" {
+		schedule, err := parseDeletionSchedule(o.schedule, time.Now())
+		if err != nil {
+			return err
+		}
+		metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, greenhouseapis.ScheduleClusterDeletionAnnotation, schedule.Format(time.DateTime))
+	}
+	if err := o.ghClient.Patch(ctx, cluster, client.MergeFrom(base)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "Scheduled the deletion of cluster %s/%s at %s\n", o.orgName, o.clusterName,
+		cluster.GetAnnotations()[greenhouseapis.ScheduleClusterDeletionAnnotation]); err != nil {
+		return err
+	}
+
+	plugins := new(greenhouseapisv1alpha1.PluginList)
+	if err := o.ghClient.List(ctx, plugins, client.InNamespace(o.orgName)); err != nil {
+		return err
+	}
+	teamRoleBindings := new(greenhouseapisv1alpha1.TeamRoleBindingList)
+	if err := o.ghClient.List(ctx, teamRoleBindings, client.InNamespace(o.orgName)); err != nil {
+		return err
+	}
+	affectedTRBs, err := teamRoleBindingsForCluster(cluster, teamRoleBindings.Items)
+	if err != nil {
+		return err
+	}
+	return printAffectedResources(out, pluginsForCluster(cluster, plugins.Items), affectedTRBs)
+}
+
+// parseDeletionSchedule parses the schedule either as duration relative to now or as timestamp in the format of the deletion schedule annotation.
+func parseDeletionSchedule(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, errors.New("the deletion schedule must not be in the past")
+		}
+		return now.UTC().Add(d), nil
+	}
+	schedule, err := time.Parse(time.DateTime, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid deletion schedule %q, expected a duration or the format %s", value, time.DateTime)
+	}
+	return schedule, nil
+}
+
+// pluginsForCluster returns the names of the Plugins deployed to the cluster.
+func pluginsForCluster(cluster *greenhouseapisv1alpha1.Cluster, plugins []greenhouseapisv1alpha1.Plugin) []string {
+	var names []string
+	for _, plugin := range plugins {
+		if plugin.Spec.ClusterName == cluster.GetName() {
+			names = append(names, plugin.GetName())
+		}
+	}
+	return names
+}
+
+// teamRoleBindingsForCluster returns the names of the TeamRoleBindings selecting the cluster by name or label selector.
+func teamRoleBindingsForCluster(cluster *greenhouseapisv1alpha1.Cluster, teamRoleBindings []greenhouseapisv1alpha1.TeamRoleBinding) ([]string, error) {
+	var names []string
+	for _, trb := range teamRoleBindings {
+		if trb.Spec.ClusterName != "" {
+			if trb.Spec.ClusterName == cluster.GetName() {
+				names = append(names, trb.GetName())
+			}
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&trb.Spec.ClusterSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster selector of TeamRoleBinding %s: %w", trb.GetName(), err)
+		}
+		if selector.Matches(labels.Set(cluster.GetLabels())) {
+			names = append(names, trb.GetName())
+		}
+	}
+	return names, nil
+}
+
+func printAffectedResources(out io.Writer, plugins, teamRoleBindings []string) error {
+	if len(plugins) == 0 && len(teamRoleBindings) == 0 {
+		_, err := fmt.Fprintln(out, "No Plugins or TeamRoleBindings are affected")
+		return err
+	}
+	if _, err := fmt.Fprintln(out, "The following resources are affected by the deletion:"); err != nil {
+		return err
+	}
+	for _, name := range plugins {
+		if _, err := fmt.Fprintf(out, "  Plugin/%s\n", name); err != nil {
+			return err
+		}
+	}
+	for _, name := range teamRoleBindings {
+		if _, err := fmt.Fprintf(out, "  TeamRoleBinding/%s\n", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+var _ = Describe("Schedule the deletion of a cluster", func() {
+	const namespace = "test-org"
+
+	var (
+		cluster *greenhousev1alpha1.Cluster
+		o       *clusterDeleteOptions
+	)
+
+	BeforeEach(func() {
+		cluster = &greenhousev1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: namespace, Labels: map[string]string{"region": "eu"}},
+		}
+		objects := []client.Object{
+			cluster,
+			&greenhousev1alpha1.Plugin{
+				ObjectMeta: metav1.ObjectMeta{Name: "plugin-on-cluster", Namespace: namespace},
+				Spec:       greenhousev1alpha1.PluginSpec{ClusterName: "test-cluster"},
+			},
+			&greenhousev1alpha1.Plugin{
+				ObjectMeta: metav1.ObjectMeta{Name: "plugin-on-other-cluster", Namespace: namespace},
+				Spec:       greenhousev1alpha1.PluginSpec{ClusterName: "other-cluster"},
+			},
+			&greenhousev1alpha1.TeamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "trb-by-name", Namespace: namespace},
+				Spec:       greenhousev1alpha1.TeamRoleBindingSpec{ClusterName: "test-cluster"},
+			},
+			&greenhousev1alpha1.TeamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "trb-by-selector", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleBindingSpec{
+					ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}},
+				},
+			},
+			&greenhousev1alpha1.TeamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "trb-other-selector", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleBindingSpec{
+					ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"region": "us"}},
+				},
+			},
+		}
+		o = &clusterDeleteOptions{
+			ghClient:    fake.NewClientBuilder().WithScheme(clientutil.Scheme).WithObjects(objects...).Build(),
+			orgName:     namespace,
+			clusterName: "test-cluster",
+		}
+	})
+
+	It("should schedule the deletion and list the affected resources", func() {
+		o.schedule = "2030-01-02 15:04:05"
+		out := new(bytes.Buffer)
+		Expect(o.run(out)).To(Succeed(), "there should be no error scheduling the deletion")
+
+		Expect(o.ghClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed(), "there should be no error getting the cluster")
+		Expect(cluster.GetAnnotations()).To(HaveKeyWithValue(greenhouseapis.MarkClusterDeletionAnnotation, "true"), "the cluster should be marked for deletion")
+		Expect(cluster.GetAnnotations()).To(HaveKeyWithValue(greenhouseapis.ScheduleClusterDeletionAnnotation, "2030-01-02 15:04:05"), "the deletion should be scheduled")
+
+		Expect(out.String()).To(ContainSubstring("Plugin/plugin-on-cluster"), "the plugin of the cluster should be listed")
+		Expect(out.String()).ToNot(ContainSubstring("plugin-on-other-cluster"), "the plugin of another cluster should not be listed")
+		Expect(out.String()).To(ContainSubstring("TeamRoleBinding/trb-by-name"), "the TeamRoleBinding selecting the cluster by name should be listed")
+		Expect(out.String()).To(ContainSubstring("TeamRoleBinding/trb-by-selector"), "the TeamRoleBinding selecting the cluster by label should be listed")
+		Expect(out.String()).ToNot(ContainSubstring("trb-other-selector"), "the TeamRoleBinding selecting other clusters should not be listed")
+	})
+
+	It("should cancel the scheduled deletion", func() {
+		cluster.SetAnnotations(map[string]string{
+			greenhouseapis.MarkClusterDeletionAnnotation:     "true",
+			greenhouseapis.ScheduleClusterDeletionAnnotation: "2030-01-02 15:04:05",
+		})
+		Expect(o.ghClient.Update(ctx, cluster)).To(Succeed(), "there should be no error marking the cluster for deletion")
+
+		o.cancel = true
+		Expect(o.run(new(bytes.Buffer))).To(Succeed(), "there should be no error cancelling the deletion")
+		Expect(o.ghClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed(), "there should be no error getting the cluster")
+		Expect(cluster.GetAnnotations()).ToNot(HaveKey(greenhouseapis.MarkClusterDeletionAnnotation), "the deletion marker should be removed")
+		Expect(cluster.GetAnnotations()).ToNot(HaveKey(greenhouseapis.ScheduleClusterDeletionAnnotation), "the deletion schedule should be removed")
+	})
+
+	DescribeTable("should parse the deletion schedule",
+		func(value string, expected time.Time, expectErr bool) {
+			now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+			schedule, err := parseDeletionSchedule(value, now)
+			if expectErr {
+				Expect(err).To(HaveOccurred(), "there should be an error parsing the schedule")
+				return
+			}
+			Expect(err).ToNot(HaveOccurred(), "there should be no error parsing the schedule")
+			Expect(schedule).To(Equal(expected), "the schedule should match")
+		},
+		Entry("duration", "24h", time.Date(2024, time.January, 2, 12, 0, 0, 0, time.UTC), false),
+		Entry("timestamp", "2024-01-05 08:00:00", time.Date(2024, time.January, 5, 8, 0, 0, 0, time.UTC), false),
+		Entry("negative duration", "-1h", time.Time{}, true),
+		Entry("invalid format", "tomorrow", time.Time{}, true),
+	)
+})
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+type clusterOffboardOptions struct {
+	customerClient       client.Client
+	ghClient             client.Client
+	kubecontext          string
+	orgName              string
+	clusterName          string
+	greenhouseKubeConfig string
+	timeout              time.Duration
+}
+
+func init() {
+	clusterCmd.AddCommand(newClusterOffboardCmd())
+}
+
+func newClusterOffboardCmd() *cobra.Command {
+	o := &clusterOffboardOptions{}
+	offboardCmd := &cobra.Command{
+		Use:   "offboard",
+		Short: "Offboard a Kubernetes cluster from Greenhouse",
+		Long: "Delete the cluster in Greenhouse without waiting for a deletion schedule and remove the namespace, ServiceAccount and ClusterRoleBinding\n" +
+			"created by the bootstrap from the cluster. The Plugins of the cluster are uninstalled before the resources are removed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if o.ghClient, err = newGreenhouseClient(o.greenhouseKubeConfig); err != nil {
+				return err
+			}
+			if o.customerClient, err = clientutil.NewK8sClient(getKubeconfigOrDie(o.kubecontext)); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	offboardCmd.Flags().AddGoFlagSet(flag.CommandLine)
+	addGreenhouseClusterFlags(offboardCmd, &o.greenhouseKubeConfig, &o.orgName, &o.clusterName)
+	offboardCmd.Flags().StringVar(&o.kubecontext, "kubecontext", "", "The context to use from the kubeconfig for the cluster which needs to be offboarded(defaults to current-context)")
+	offboardCmd.Flags().DurationVar(&o.timeout, "timeout", 5*time.Minute, "How long to wait for the deletion of the cluster in Greenhouse")
+	offboardCmd.SilenceUsage = true
+
+	return offboardCmd
+}
+
+func (o *clusterOffboardOptions) run() error {
+	setupLog.Info("Offboarding cluster", "clusterName", o.clusterName, "orgName", o.orgName)
+	if err := o.deleteClusterInGreenhouse(ctx); err != nil {
+		return err
+	}
+
+	// The ClusterRoleBinding is owned by the namespace, it is deleted explicitly to revoke the access of Greenhouse immediately.
+	remoteObjects := []struct {
+		kind string
+		obj  client.Object
+	}{
+		{"clusterRoleBinding", &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName}}},
+		{"serviceAccount", &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName, Namespace: o.orgName}}},
+		{"namespace", &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: o.orgName}}},
+	}
+	for _, r := range remoteObjects {
+		if err := o.customerClient.Delete(ctx, r.obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				setupLog.Info(r.kind+" does not exist", "name", r.obj.GetName())
+				continue
+			}
+			return err
+		}
+		setupLog.Info("deleted "+r.kind, "name", r.obj.GetName())
+	}
+
+	setupLog.Info("Offboarding cluster finished", "clusterName", o.clusterName, "orgName", o.orgName)
+	return nil
+}
+
+// deleteClusterInGreenhouse deletes the Cluster and waits until it is gone, so that its Plugins are uninstalled while Greenhouse still has access.
+// The deletion schedule is set to the past, as the webhook only admits the deletion of clusters with an elapsed schedule.
+func (o *clusterOffboardOptions) deleteClusterInGreenhouse(ctx context.Context) error {
+	cluster := new(greenhouseapisv1alpha1.Cluster)
+	err := o.ghClient.Get(ctx, client.ObjectKey{Namespace: o.orgName, Name: o.clusterName}, cluster)
+	if apierrors.IsNotFound(err) {
+		setupLog.Info("cluster does not exist in Greenhouse", "clusterName", o.clusterName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	base := cluster.DeepCopy()
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, greenhouseapis.MarkClusterDeletionAnnotation, "true")
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, greenhouseapis.ScheduleClusterDeletionAnnotation, time.Now().UTC().Add(-time.Minute).Format(time.DateTime))
+	if err := o.ghClient.Patch(ctx, cluster, client.MergeFrom(base)); err != nil {
+		return err
+	}
+	if err := o.ghClient.Delete(ctx, cluster); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	setupLog.Info("deleting cluster in Greenhouse", "clusterName", o.clusterName)
+
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, o.timeout, true, func(ctx context.Context) (bool, error) {
+		err := o.ghClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("cluster %s was not deleted in Greenhouse within %s, check its Plugins: %w", o.clusterName, o.timeout, err)
+	}
+	setupLog.Info("deleted cluster in Greenhouse", "clusterName", o.clusterName)
+	return nil
+}
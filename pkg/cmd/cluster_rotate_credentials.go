@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+type clusterRotateCredentialsOptions struct {
+	ghClient             client.Client
+	greenhouseKubeConfig string
+	orgName              string
+	clusterName          string
+	timeout              time.Duration
+	pollInterval         time.Duration
+}
+
+func init() {
+	clusterCmd.AddCommand(newClusterRotateCredentialsCmd())
+}
+
+func newClusterRotateCredentialsCmd() *cobra.Command {
+	o := &clusterRotateCredentialsOptions{pollInterval: 2 * time.Second}
+	rotateCmd := &cobra.Command{
+		Use:   "rotate-credentials",
+		Short: "Revoke the credentials Greenhouse uses to access a cluster and issue new ones",
+		Long: "Request Greenhouse to recreate the ServiceAccount it uses to access the cluster, which invalidates all of its outstanding tokens,\n" +
+			"and to update the secret of the cluster with a new token. Use this if the secret of the cluster was leaked.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if o.ghClient, err = newGreenhouseClient(o.greenhouseKubeConfig); err != nil {
+				return err
+			}
+			return o.run(ctx)
+		},
+	}
+
+	addGreenhouseClusterFlags(rotateCmd, &o.greenhouseKubeConfig, &o.orgName, &o.clusterName)
+	rotateCmd.Flags().DurationVar(&o.timeout, "timeout", 5*time.Minute, "How long to wait for the rotation to finish, 0 returns right after requesting it")
+	rotateCmd.SilenceUsage = true
+
+	return rotateCmd
+}
+
+func (o *clusterRotateCredentialsOptions) run(ctx context.Context) error {
+	cluster := new(greenhouseapisv1alpha1.Cluster)
+	if err := o.ghClient.Get(ctx, client.ObjectKey{Namespace: o.orgName, Name: o.clusterName}, cluster); err != nil {
+		return err
+	}
+	// The remote cluster accepts all tokens of the OIDC issuer until they expire, so they cannot be revoked.
+	clusterSecret := new(corev1.Secret)
+	switch err := o.ghClient.Get(ctx, client.ObjectKeyFromObject(cluster), clusterSecret); {
+	case err != nil && !apierrors.IsNotFound(err):
+		return err
+	case err == nil && clusterSecret.Type == greenhouseapis.SecretTypeOIDCConfig:
+		return fmt.Errorf("credentials of cluster %s are issued via OIDC and cannot be revoked", o.clusterName)
+	}
+	base := cluster.DeepCopy()
+	metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, greenhouseapis.RotateCredentialsAnnotation, time.Now().UTC().Format(time.RFC3339))
+	if err := o.ghClient.Patch(ctx, cluster, client.MergeFrom(base)); err != nil {
+		return err
+	}
+	setupLog.Info("requested rotation of the credentials", "clusterName", o.clusterName, "orgName", o.orgName)
+	if o.timeout == 0 {
+		return nil
+	}
+
+	// The controller removes the annotation once the rotation succeeded.
+	var lastFailure string
+	err := wait.PollUntilContextTimeout(ctx, o.pollInterval, o.timeout, false, func(ctx context.Context) (bool, error) {
+		if err := o.ghClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster); err != nil {
+			return false, err
+		}
+		if condition := cluster.Status.GetConditionByType(greenhouseapisv1alpha1.CredentialsRotated); condition != nil && condition.IsFalse() {
+			lastFailure = condition.Message
+		}
+		_, requested := cluster.GetAnnotations()[greenhouseapis.RotateCredentialsAnnotation]
+		return !requested, nil
+	})
+	switch {
+	case err != nil && lastFailure != "":
+		return fmt.Errorf("credentials of cluster %s were not rotated within %s: %s", o.clusterName, o.timeout, lastFailure)
+	case err != nil:
+		return fmt.Errorf("credentials of cluster %s were not rotated within %s: %w", o.clusterName, o.timeout, err)
+	}
+	setupLog.Info("rotated credentials", "clusterName", o.clusterName, "orgName", o.orgName)
+	return nil
+}
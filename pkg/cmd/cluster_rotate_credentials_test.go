@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+var _ = Describe("Rotate the credentials of a cluster", func() {
+	var (
+		cluster *greenhousev1alpha1.Cluster
+		o       *clusterRotateCredentialsOptions
+	)
+
+	BeforeEach(func() {
+		cluster = &greenhousev1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-org"},
+			Status: greenhousev1alpha1.ClusterStatus{
+				StatusConditions: greenhousev1alpha1.StatusConditions{
+					Conditions: []greenhousev1alpha1.Condition{
+						greenhousev1alpha1.FalseCondition(greenhousev1alpha1.CredentialsRotated, greenhousev1alpha1.CredentialsRotationFailedReason, "failed to delete the ServiceAccount"),
+					},
+				},
+			},
+		}
+		o = &clusterRotateCredentialsOptions{
+			ghClient:     fake.NewClientBuilder().WithScheme(clientutil.Scheme).WithObjects(cluster).WithStatusSubresource(cluster).Build(),
+			orgName:      "test-org",
+			clusterName:  "test-cluster",
+			pollInterval: 10 * time.Millisecond,
+		}
+	})
+
+	It("should request the rotation", func() {
+		Expect(o.run(ctx)).To(Succeed(), "there should be no error requesting the rotation")
+		Expect(o.ghClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed(), "there should be no error getting the cluster")
+		Expect(cluster.GetAnnotations()).To(HaveKey(greenhouseapis.RotateCredentialsAnnotation), "the rotation should be requested")
+	})
+
+	It("should report the failure of the rotation", func() {
+		o.timeout = 50 * time.Millisecond
+		err := o.run(ctx)
+		Expect(err).To(HaveOccurred(), "there should be an error if the rotation does not finish")
+		Expect(err.Error()).To(ContainSubstring("failed to delete the ServiceAccount"), "the failure of the rotation should be reported")
+	})
+
+	It("should reject clusters accessed via OIDC", func() {
+		Expect(o.ghClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-org"},
+			Type:       greenhouseapis.SecretTypeOIDCConfig,
+		})).To(Succeed(), "there should be no error creating the cluster secret")
+		Expect(o.run(ctx)).To(MatchError(ContainSubstring("cannot be revoked")), "the rotation should be rejected")
+		Expect(o.ghClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed(), "there should be no error getting the cluster")
+		Expect(cluster.GetAnnotations()).NotTo(HaveKey(greenhouseapis.RotateCredentialsAnnotation), "the rotation should not be requested")
+	})
+})
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+type clusterStatusOptions struct {
+	ghClient             client.Client
+	greenhouseKubeConfig string
+	orgName              string
+	clusterName          string
+}
+
+func init() {
+	clusterCmd.AddCommand(newClusterStatusCmd())
+}
+
+func newClusterStatusCmd() *cobra.Command {
+	o := &clusterStatusOptions{}
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of a cluster in Greenhouse",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if o.ghClient, err = newGreenhouseClient(o.greenhouseKubeConfig); err != nil {
+				return err
+			}
+			cluster := new(greenhouseapisv1alpha1.Cluster)
+			if err := o.ghClient.Get(ctx, client.ObjectKey{Namespace: o.orgName, Name: o.clusterName}, cluster); err != nil {
+				return err
+			}
+			return printClusterStatus(cmd.OutOrStdout(), cluster, time.Now())
+		},
+	}
+
+	addGreenhouseClusterFlags(statusCmd, &o.greenhouseKubeConfig, &o.orgName, &o.clusterName)
+	statusCmd.SilenceUsage = true
+
+	return statusCmd
+}
+
+// printClusterStatus prints the conditions, the token expiry and the nodes of the cluster.
+func printClusterStatus(out io.Writer, cluster *greenhouseapisv1alpha1.Cluster, now time.Time) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Cluster:\t%s/%s\n", cluster.GetNamespace(), cluster.GetName())
+	fmt.Fprintf(&sb, "Access mode:\t%s\n", cluster.Spec.AccessMode)
+	fmt.Fprintf(&sb, "Kubernetes version:\t%s\n", valueOrUnknown(cluster.Status.KubernetesVersion))
+	tokenExpiry := "unknown"
+	if expiry := cluster.Status.BearerTokenExpirationTimestamp; !expiry.IsZero() {
+		tokenExpiry = fmt.Sprintf("%s (in %s)", expiry.UTC().Format(time.RFC3339), expiry.Sub(now).Round(time.Minute))
+		if expiry.Time.Before(now) {
+			tokenExpiry = expiry.UTC().Format(time.RFC3339) + " (expired)"
+		}
+	}
+	fmt.Fprintf(&sb, "Token expiry:\t%s\n", tokenExpiry)
+	if scheduled, schedule, err := clientutil.ExtractDeletionSchedule(cluster.GetAnnotations()); err == nil && scheduled {
+		fmt.Fprintf(&sb, "Deletion scheduled:\t%s\n", schedule.Format(time.DateTime))
+	}
+
+	sb.WriteString("\nConditions:\n")
+	sb.WriteString("  TYPE\tSTATUS\tREASON\tLAST TRANSITION\tMESSAGE\n")
+	conditions := slices.Clone(cluster.Status.StatusConditions.Conditions)
+	slices.SortFunc(conditions, func(a, b greenhouseapisv1alpha1.Condition) int {
+		return strings.Compare(string(a.Type), string(b.Type))
+	})
+	for _, condition := range conditions {
+		fmt.Fprintf(&sb, "  %s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, valueOrNone(string(condition.Reason)),
+			condition.LastTransitionTime.UTC().Format(time.RFC3339), condition.Message)
+	}
+
+	sb.WriteString("\nNodes:\n")
+	if len(cluster.Status.Nodes) == 0 {
+		sb.WriteString("  none\n")
+	} else {
+		sb.WriteString("  NAME\tREADY\tMESSAGE\n")
+	}
+	for _, name := range slices.Sorted(maps.Keys(cluster.Status.Nodes)) {
+		node := cluster.Status.Nodes[name]
+		message := ""
+		if ready := node.GetConditionByType(greenhouseapisv1alpha1.ReadyCondition); !node.Ready && ready != nil {
+			message = ready.Message
+		}
+		fmt.Fprintf(&sb, "  %s\t%t\t%s\n", name, node.Ready, message)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+var _ = Describe("Print the status of a cluster", func() {
+	It("should print the conditions, the token expiry and the nodes", func() {
+		now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+		cluster := &greenhousev1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-org"},
+			Spec:       greenhousev1alpha1.ClusterSpec{AccessMode: greenhousev1alpha1.ClusterAccessModeDirect},
+			Status: greenhousev1alpha1.ClusterStatus{
+				KubernetesVersion:              "v1.30.1",
+				BearerTokenExpirationTimestamp: metav1.NewTime(now.Add(6 * time.Hour)),
+				StatusConditions: greenhousev1alpha1.StatusConditions{
+					Conditions: []greenhousev1alpha1.Condition{
+						greenhousev1alpha1.TrueCondition(greenhousev1alpha1.ReadyCondition, "", "ready"),
+						greenhousev1alpha1.FalseCondition(greenhousev1alpha1.AllNodesReady, "", "node-2 not ready"),
+					},
+				},
+				Nodes: map[string]greenhousev1alpha1.NodeStatus{
+					"node-1": {Ready: true},
+					"node-2": {
+						StatusConditions: greenhousev1alpha1.StatusConditions{
+							Conditions: []greenhousev1alpha1.Condition{
+								greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ReadyCondition, "", "kubelet stopped posting node status"),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		out := new(bytes.Buffer)
+		Expect(printClusterStatus(out, cluster, now)).To(Succeed(), "there should be no error printing the status")
+		Expect(out.String()).To(ContainSubstring("test-org/test-cluster"), "the cluster should be printed")
+		Expect(out.String()).To(ContainSubstring("v1.30.1"), "the Kubernetes version should be printed")
+		Expect(out.String()).To(ContainSubstring("2024-01-01T18:00:00Z (in 6h0m0s)"), "the token expiry should be printed")
+		Expect(out.String()).To(MatchRegexp(`AllNodesReady\s+False`), "the conditions should be printed")
+		Expect(out.String()).To(MatchRegexp(`node-2\s+false\s+kubelet stopped posting node status`), "the not ready node should be printed with its message")
+	})
+})
@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+const defaultKubeconfigPrefix = "greenhouse"
+
+// errWatchExpired is returned if the resource version of the watch is too old, the ClusterKubeconfigs need to be listed again.
+var errWatchExpired = errors.New("watch of ClusterKubeconfigs expired")
+
+type kubeconfigSyncOptions struct {
+	ghClient             client.WithWatch
+	greenhouseKubeConfig string
+	orgName              string
+	kubeconfigPath       string
+	prefix               string
+	watch                bool
+	// kubeconfigs holds the ClusterKubeconfigs of the organization by name.
+	kubeconfigs map[string]*greenhouseapisv1alpha1.ClusterKubeconfig
+}
+
+func init() {
+	kubeconfigCmd.AddCommand(newKubeconfigSyncCmd())
+}
+
+func newKubeconfigSyncCmd() *cobra.Command {
+	o := &kubeconfigSyncOptions{}
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Merge the kubeconfigs of all clusters of an organization into a local kubeconfig",
+		Long: "Merge the ClusterKubeconfigs of all clusters of an organization into a local kubeconfig file.\n" +
+			"The clusters, users and contexts are named <prefix>:<org>:<name>. Entries with this prefix of clusters which no longer exist are removed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restConfig := getClientKubeconfig(&o.greenhouseKubeConfig)
+			var err error
+			if o.ghClient, err = client.NewWithWatch(restConfig, client.Options{Scheme: clientutil.Scheme}); err != nil {
+				return err
+			}
+			return o.run(ctx)
+		},
+	}
+
+	syncCmd.Flags().StringVar(&o.greenhouseKubeConfig, "greenhouse-kubeconfig", "", "The kubeconfig of the greenhouse cluster")
+	syncCmd.Flags().StringVar(&o.orgName, "org", clientutil.GetEnvOrDefault("GREENHOUSE_ORG", ""), "The organization name to use. Can be set via GREENHOUSE_ORG env var")
+	syncCmd.Flags().StringVar(&o.kubeconfigPath, "local-kubeconfig", clientcmd.NewDefaultPathOptions().GetDefaultFilename(), "The local kubeconfig file the kubeconfigs are merged into")
+	syncCmd.Flags().StringVar(&o.prefix, "prefix", defaultKubeconfigPrefix, "The prefix of the names of the clusters, users and contexts")
+	syncCmd.Flags().BoolVar(&o.watch, "watch", false, "Keep the local kubeconfig updated until interrupted")
+	for _, name := range []string{"greenhouse-kubeconfig", "org"} {
+		if err := syncCmd.MarkFlagRequired(name); err != nil {
+			setupLog.Error(err, "Flag could not set as required", name)
+		}
+	}
+	syncCmd.SilenceUsage = true
+
+	return syncCmd
+}
+
+func (o *kubeconfigSyncOptions) run(ctx context.Context) error {
+	for {
+		resourceVersion, err := o.sync(ctx)
+		if err != nil || !o.watch {
+			return err
+		}
+		if err := o.watchKubeconfigs(ctx, resourceVersion); err != nil {
+			return err
+		}
+		// The watch was closed by the server or expired, start over with a new list.
+		setupLog.Info("watch closed, resyncing", "orgName", o.orgName)
+	}
+}
+
+// sync lists the ClusterKubeconfigs of the organization and writes them to the local kubeconfig.
+// It returns the resource version of the list to start watching from.
+func (o *kubeconfigSyncOptions) sync(ctx context.Context) (string, error) {
+	list := new(greenhouseapisv1alpha1.ClusterKubeconfigList)
+	if err := o.ghClient.List(ctx, list, client.InNamespace(o.orgName)); err != nil {
+		return "", err
+	}
+	o.kubeconfigs = make(map[string]*greenhouseapisv1alpha1.ClusterKubeconfig, len(list.Items))
+	for i := range list.Items {
+		o.kubeconfigs[list.Items[i].Name] = &list.Items[i]
+	}
+	return list.ResourceVersion, o.writeKubeconfig()
+}
+
+func (o *kubeconfigSyncOptions) watchKubeconfigs(ctx context.Context, resourceVersion string) error {
+	watcher, err := o.ghClient.Watch(ctx, new(greenhouseapisv1alpha1.ClusterKubeconfigList), client.InNamespace(o.orgName), &client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: resourceVersion}})
+	if isWatchExpired(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			changed, err := o.handleEvent(event)
+			if errors.Is(err, errWatchExpired) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+			if err := o.writeKubeconfig(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleEvent updates the known ClusterKubeconfigs and reports whether the local kubeconfig needs to be written.
+// Only changes of the generation are considered, as status updates do not change the kubeconfig.
+func (o *kubeconfigSyncOptions) handleEvent(event watch.Event) (bool, error) {
+	if event.Type == watch.Error {
+		if err := apierrors.FromObject(event.Object); isWatchExpired(err) {
+			return false, errWatchExpired
+		}
+		return false, fmt.Errorf("watching ClusterKubeconfigs failed: %v", event.Object)
+	}
+	kubeconfig, ok := event.Object.(*greenhouseapisv1alpha1.ClusterKubeconfig)
+	if !ok {
+		return false, nil
+	}
+	current, exists := o.kubeconfigs[kubeconfig.Name]
+	switch event.Type {
+	case watch.Deleted:
+		delete(o.kubeconfigs, kubeconfig.Name)
+		return exists, nil
+	case watch.Added, watch.Modified:
+		o.kubeconfigs[kubeconfig.Name] = kubeconfig
+		return !exists || current.Generation != kubeconfig.Generation, nil
+	}
+	return false, nil
+}
+
+// isWatchExpired reports whether the resource version to watch from is no longer available (410 Gone).
+func isWatchExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+// writeKubeconfig merges the known ClusterKubeconfigs into the local kubeconfig and removes the entries of clusters which no longer exist.
+func (o *kubeconfigSyncOptions) writeKubeconfig() error {
+	config, err := clientcmd.LoadFromFile(o.kubeconfigPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		config = clientcmdapi.NewConfig()
+	case err != nil:
+		return err
+	}
+
+	prefix := o.prefix + ":" + o.orgName + ":"
+	desired := clientcmdapi.NewConfig()
+	for _, name := range slices.Sorted(maps.Keys(o.kubeconfigs)) {
+		mergeClusterKubeconfig(desired, o.kubeconfigs[name], prefix)
+	}
+	for name, authInfo := range desired.AuthInfos {
+		// The oidc auth provider persists its tokens in the kubeconfig, they are retained to avoid logging in again.
+		if existing, ok := config.AuthInfos[name]; ok && existing.AuthProvider != nil && authInfo.AuthProvider != nil {
+			for key, value := range existing.AuthProvider.Config {
+				if _, managed := authInfo.AuthProvider.Config[key]; !managed {
+					authInfo.AuthProvider.Config[key] = value
+				}
+			}
+		}
+	}
+	syncEntries(config.Clusters, desired.Clusters, prefix)
+	syncEntries(config.AuthInfos, desired.AuthInfos, prefix)
+	syncEntries(config.Contexts, desired.Contexts, prefix)
+	if strings.HasPrefix(config.CurrentContext, prefix) && config.Contexts[config.CurrentContext] == nil {
+		config.CurrentContext = ""
+	}
+
+	if err := clientcmd.WriteToFile(*config, o.kubeconfigPath); err != nil {
+		return err
+	}
+	setupLog.Info("synced kubeconfig", "orgName", o.orgName, "clusters", len(o.kubeconfigs), "path", o.kubeconfigPath)
+	return nil
+}
+
+// syncEntries replaces the entries with the prefix by the desired ones, other entries are left untouched.
+func syncEntries[T any](entries, desired map[string]T, prefix string) {
+	for name := range entries {
+		if _, ok := desired[name]; strings.HasPrefix(name, prefix) && !ok {
+			delete(entries, name)
+		}
+	}
+	maps.Copy(entries, desired)
+}
+
+// mergeClusterKubeconfig adds the clusters, users and contexts of the ClusterKubeconfig with prefixed names to the config.
+func mergeClusterKubeconfig(config *clientcmdapi.Config, kubeconfig *greenhouseapisv1alpha1.ClusterKubeconfig, prefix string) {
+	data := kubeconfig.Spec.Kubeconfig
+	for _, item := range data.Clusters {
+		config.Clusters[prefix+item.Name] = &clientcmdapi.Cluster{
+			Server:                   item.Cluster.Server,
+			CertificateAuthorityData: item.Cluster.CertificateAuthorityData,
+		}
+	}
+	for _, item := range data.AuthInfo {
+		authInfo := &clientcmdapi.AuthInfo{
+			ClientCertificateData: item.AuthInfo.ClientCertificateData,
+			ClientKeyData:         item.AuthInfo.ClientKeyData,
+		}
+		if provider := item.AuthInfo.AuthProvider; provider != nil {
+			authInfo.AuthProvider = &clientcmdapi.AuthProviderConfig{Name: provider.Name, Config: maps.Clone(provider.Config)}
+			if authInfo.AuthProvider.Config == nil {
+				authInfo.AuthProvider.Config = make(map[string]string)
+			}
+		}
+		if exec := item.AuthInfo.Exec; exec != nil {
+			authInfo.Exec = &clientcmdapi.ExecConfig{
+				APIVersion:      exec.APIVersion,
+				Command:         exec.Command,
+				Args:            slices.Clone(exec.Args),
+				InstallHint:     exec.InstallHint,
+				InteractiveMode: clientcmdapi.ExecInteractiveMode(exec.InteractiveMode),
+			}
+		}
+		config.AuthInfos[prefix+item.Name] = authInfo
+	}
+	for _, item := range data.Contexts {
+		config.Contexts[prefix+item.Name] = &clientcmdapi.Context{
+			Cluster:   prefix + item.Context.Cluster,
+			AuthInfo:  prefix + item.Context.AuthInfo,
+			Namespace: item.Context.Namespace,
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+func testClusterKubeconfig(name string) *greenhousev1alpha1.ClusterKubeconfig {
+	return &greenhousev1alpha1.ClusterKubeconfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-org", Generation: 1},
+		Spec: greenhousev1alpha1.ClusterKubeconfigSpec{
+			Kubeconfig: greenhousev1alpha1.ClusterKubeconfigData{
+				Clusters: []greenhousev1alpha1.ClusterKubeconfigClusterItem{
+					{Name: name, Cluster: greenhousev1alpha1.ClusterKubeconfigCluster{Server: "https://" + name}},
+				},
+				AuthInfo: []greenhousev1alpha1.ClusterKubeconfigAuthInfoItem{
+					{Name: "oidc@" + name, AuthInfo: greenhousev1alpha1.ClusterKubeconfigAuthInfo{
+						AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "oidc", Config: map[string]string{"client-id": "test-client"}},
+					}},
+				},
+				Contexts: []greenhousev1alpha1.ClusterKubeconfigContextItem{
+					{Name: name, Context: greenhousev1alpha1.ClusterKubeconfigContext{Cluster: name, AuthInfo: "oidc@" + name}},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Sync the kubeconfigs of an organization", func() {
+	var o *kubeconfigSyncOptions
+
+	BeforeEach(func() {
+		o = &kubeconfigSyncOptions{
+			ghClient:       fake.NewClientBuilder().WithScheme(clientutil.Scheme).WithObjects(testClusterKubeconfig("cluster-a"), testClusterKubeconfig("cluster-b")).Build(),
+			orgName:        "test-org",
+			prefix:         defaultKubeconfigPrefix,
+			kubeconfigPath: filepath.Join(GinkgoT().TempDir(), "config"),
+		}
+	})
+
+	It("should merge the kubeconfigs into the local kubeconfig", func() {
+		existing := clientcmdapi.NewConfig()
+		existing.Clusters["other"] = &clientcmdapi.Cluster{Server: "https://other"}
+		existing.Contexts["other"] = &clientcmdapi.Context{Cluster: "other"}
+		existing.Contexts["greenhouse:test-org:deleted"] = &clientcmdapi.Context{Cluster: "greenhouse:test-org:deleted"}
+		existing.Contexts["greenhouse:test-org-other:deleted"] = &clientcmdapi.Context{Cluster: "greenhouse:test-org-other:deleted"}
+		existing.AuthInfos["greenhouse:test-org:oidc@cluster-a"] = &clientcmdapi.AuthInfo{
+			AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "oidc", Config: map[string]string{"client-id": "outdated", "id-token": "token"}},
+		}
+		existing.CurrentContext = "greenhouse:test-org:deleted"
+		Expect(clientcmd.WriteToFile(*existing, o.kubeconfigPath)).To(Succeed(), "there should be no error writing the local kubeconfig")
+
+		Expect(o.run(ctx)).To(Succeed(), "there should be no error syncing the kubeconfigs")
+
+		config, err := clientcmd.LoadFromFile(o.kubeconfigPath)
+		Expect(err).NotTo(HaveOccurred(), "there should be no error loading the local kubeconfig")
+		Expect(config.Contexts).To(HaveKey("greenhouse:test-org:cluster-a"), "the context of cluster-a should be added")
+		Expect(config.Contexts).To(HaveKey("greenhouse:test-org:cluster-b"), "the context of cluster-b should be added")
+		Expect(config.Contexts["greenhouse:test-org:cluster-a"].Cluster).To(Equal("greenhouse:test-org:cluster-a"), "the context should reference the prefixed cluster")
+		Expect(config.Clusters["greenhouse:test-org:cluster-b"].Server).To(Equal("https://cluster-b"), "the cluster should be added")
+		Expect(config.Contexts).NotTo(HaveKey("greenhouse:test-org:deleted"), "the context of a deleted cluster should be removed")
+		Expect(config.Contexts).To(HaveKey("greenhouse:test-org-other:deleted"), "the contexts of other organizations should be kept")
+		Expect(config.Contexts).To(HaveKey("other"), "unrelated contexts should be kept")
+		Expect(config.CurrentContext).To(BeEmpty(), "the current context should be unset if it was removed")
+		Expect(config.AuthInfos["greenhouse:test-org:oidc@cluster-a"].AuthProvider.Config).To(And(
+			HaveKeyWithValue("client-id", "test-client"),
+			HaveKeyWithValue("id-token", "token"),
+		), "the auth provider should be updated while retaining the tokens")
+	})
+
+	It("should only update the local kubeconfig if the generation changed", func() {
+		_, err := o.sync(ctx)
+		Expect(err).NotTo(HaveOccurred(), "there should be no error syncing the kubeconfigs")
+
+		kubeconfig := testClusterKubeconfig("cluster-a")
+		changed, err := o.handleEvent(watch.Event{Type: watch.Modified, Object: kubeconfig})
+		Expect(err).NotTo(HaveOccurred(), "there should be no error handling the event")
+		Expect(changed).To(BeFalse(), "a status update should not change the local kubeconfig")
+
+		kubeconfig = testClusterKubeconfig("cluster-a")
+		kubeconfig.Generation = 2
+		changed, err = o.handleEvent(watch.Event{Type: watch.Modified, Object: kubeconfig})
+		Expect(err).NotTo(HaveOccurred(), "there should be no error handling the event")
+		Expect(changed).To(BeTrue(), "a spec update should change the local kubeconfig")
+
+		changed, err = o.handleEvent(watch.Event{Type: watch.Deleted, Object: testClusterKubeconfig("cluster-b")})
+		Expect(err).NotTo(HaveOccurred(), "there should be no error handling the event")
+		Expect(changed).To(BeTrue(), "a deletion should change the local kubeconfig")
+		Expect(o.kubeconfigs).NotTo(HaveKey("cluster-b"), "the deleted ClusterKubeconfig should be forgotten")
+	})
+
+	It("should restart the watch if the resource version expired", func() {
+		expired := apierrors.NewResourceExpired("too old resource version")
+		_, err := o.handleEvent(watch.Event{Type: watch.Error, Object: &expired.ErrStatus})
+		Expect(err).To(MatchError(errWatchExpired), "an expired watch should be restarted")
+
+		internal := apierrors.NewInternalError(errors.New("boom"))
+		_, err = o.handleEvent(watch.Event{Type: watch.Error, Object: &internal.ErrStatus})
+		Expect(err).To(HaveOccurred(), "other watch errors should be reported")
+		Expect(err).NotTo(MatchError(errWatchExpired), "other watch errors should not restart the watch")
+	})
+})
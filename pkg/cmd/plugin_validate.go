@@ -119,7 +119,7 @@ func validateOptions(pluginDefinition *greenhousev1alpha1.PluginDefinition, plug
 }
 
 func validateHelmChart(pluginDefinition *greenhousev1alpha1.PluginDefinition, plugin *greenhousev1alpha1.Plugin) error {
-	if pluginDefinition.Spec.HelmChart == nil {
+	if pluginDefinition.Spec.ChartReference() == nil {
 		return nil
 	}
 
@@ -131,7 +131,7 @@ func validateHelmChart(pluginDefinition *greenhousev1alpha1.PluginDefinition, pl
 		return err
 	}
 
-	fmt.Printf("rendering helm chart %s\n", pluginDefinition.Spec.HelmChart.String())
+	fmt.Printf("rendering helm chart %s\n", pluginDefinition.Spec.ChartReference().String())
 	_, err = helm.TemplateHelmChartFromPlugin(context.Background(), local, restClientGetter, pluginDefinition, plugin)
 	return err
 }
@@ -0,0 +1,304 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+	"github.com/cloudoperators/greenhouse/pkg/rbac"
+)
+
+// greenhouseClusterName identifies the Greenhouse cluster, in which the organization roles are granted.
+const greenhouseClusterName = "(greenhouse)"
+
+func init() {
+	rootCmd.AddCommand(rbacCmd)
+}
+
+var rbacCmd = &cobra.Command{
+	Use:   "rbac",
+	Short: "Review the access granted by Greenhouse",
+}
+
+// addGreenhouseOrgFlags adds the flags identifying an organization in Greenhouse.
+func addGreenhouseOrgFlags(cmd *cobra.Command, greenhouseKubeConfig, orgName *string) {
+	cmd.Flags().StringVar(greenhouseKubeConfig, "greenhouse-kubeconfig", "", "The kubeconfig of the greenhouse cluster")
+	cmd.Flags().StringVar(orgName, "org", clientutil.GetEnvOrDefault("GREENHOUSE_ORG", ""), "The organization name to use. Can be set via GREENHOUSE_ORG env var")
+	for _, name := range []string{"greenhouse-kubeconfig", "org"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			setupLog.Error(err, "Flag could not set as required", name)
+		}
+	}
+}
+
+// grant is the access granted to the members of teams and to users in a namespace or cluster-wide on a cluster.
+type grant struct {
+	cluster string
+	// namespace is empty for access granted cluster-wide.
+	namespace string
+	// binding and role identify the Greenhouse object granting the access, e.g. TeamRoleBinding/<name> and TeamRole/<name>.
+	binding string
+	role    string
+	teams   []string
+	// groups are the groups the access is granted to on the cluster by team.
+	groups    map[string]string
+	usernames []string
+	rules     []rbacv1.PolicyRule
+}
+
+// grantsTo returns true if the grant applies to the team.
+func (g grant) grantsTo(team string) bool {
+	return slices.Contains(g.teams, team)
+}
+
+// appliesTo returns true if the grant applies to the namespace on the cluster, an empty namespace only matches grants for the whole cluster.
+func (g grant) appliesTo(cluster, namespace string, allNamespaces bool) bool {
+	if cluster != "" && g.cluster != cluster {
+		return false
+	}
+	return g.namespace == "" || allNamespaces || g.namespace == namespace
+}
+
+// accessReview contains the Greenhouse objects of an organization the granted access is computed from.
+type accessReview struct {
+	organization     *greenhouseapisv1alpha1.Organization
+	teams            []greenhouseapisv1alpha1.Team
+	members          map[string][]greenhouseapisv1alpha1.User
+	teamRoles        map[string]*greenhouseapisv1alpha1.TeamRole
+	teamRoleBindings []greenhouseapisv1alpha1.TeamRoleBinding
+}
+
+// loadAccessReview lists the Teams, TeamMemberships, TeamRoles and TeamRoleBindings of the organization.
+func loadAccessReview(ctx context.Context, c client.Client, orgName string) (*accessReview, error) {
+	a := &accessReview{
+		organization: new(greenhouseapisv1alpha1.Organization),
+		members:      make(map[string][]greenhouseapisv1alpha1.User),
+		teamRoles:    make(map[string]*greenhouseapisv1alpha1.TeamRole),
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: orgName}, a.organization); err != nil {
+		return nil, err
+	}
+	teams := new(greenhouseapisv1alpha1.TeamList)
+	if err := c.List(ctx, teams, client.InNamespace(orgName)); err != nil {
+		return nil, err
+	}
+	a.teams = teams.Items
+	teamMemberships := new(greenhouseapisv1alpha1.TeamMembershipList)
+	if err := c.List(ctx, teamMemberships, client.InNamespace(orgName)); err != nil {
+		return nil, err
+	}
+	// The TeamMembership is named after the Team.
+	for _, teamMembership := range teamMemberships.Items {
+		a.members[teamMembership.GetName()] = teamMembership.Spec.Members
+	}
+	teamRoles := new(greenhouseapisv1alpha1.TeamRoleList)
+	if err := c.List(ctx, teamRoles, client.InNamespace(orgName)); err != nil {
+		return nil, err
+	}
+	for _, teamRole := range teamRoles.Items {
+		a.teamRoles[teamRole.GetName()] = teamRole.DeepCopy()
+	}
+	teamRoleBindings := new(greenhouseapisv1alpha1.TeamRoleBindingList)
+	if err := c.List(ctx, teamRoleBindings, client.InNamespace(orgName)); err != nil {
+		return nil, err
+	}
+	a.teamRoleBindings = teamRoleBindings.Items
+	return a, nil
+}
+
+// grants returns the access granted by the organization roles in the Greenhouse cluster and by the TeamRoleBindings on the clusters they were propagated to.
+func (a *accessReview) grants() ([]grant, error) {
+	orgName := a.organization.GetName()
+	var allTeams, adminTeams []string
+	for _, team := range a.teams {
+		allTeams = append(allTeams, team.GetName())
+		if team.Spec.MappedIDPGroup != "" && team.Spec.MappedIDPGroup == a.organization.Spec.MappedOrgAdminIDPGroup {
+			adminTeams = append(adminTeams, team.GetName())
+		}
+	}
+	// The organization roles are granted to the same group for all teams.
+	groupsOf := func(teams []string, group string) map[string]string {
+		groups := make(map[string]string, len(teams))
+		for _, team := range teams {
+			groups[team] = group
+		}
+		return groups
+	}
+	adminRole := rbac.OrganizationAdminRoleName(orgName)
+	memberRole := rbac.OrganizationRoleName(orgName)
+	grants := []grant{
+		{cluster: greenhouseClusterName, binding: "ClusterRoleBinding/" + adminRole, role: "ClusterRole/" + adminRole, teams: adminTeams, groups: groupsOf(adminTeams, adminRole),
+			rules: rbac.OrganizationAdminClusterRolePolicyRules(orgName)},
+		{cluster: greenhouseClusterName, namespace: orgName, binding: "RoleBinding/" + adminRole, role: "Role/" + adminRole, teams: adminTeams, groups: groupsOf(adminTeams, adminRole),
+			rules: rbac.OrganizationAdminPolicyRules()},
+		{cluster: greenhouseClusterName, binding: "ClusterRoleBinding/" + memberRole, role: "ClusterRole/" + memberRole, teams: allTeams, groups: groupsOf(allTeams, memberRole),
+			rules: rbac.OrganizationMemberClusterRolePolicyRules(orgName)},
+		{cluster: greenhouseClusterName, namespace: orgName, binding: "RoleBinding/" + memberRole, role: "Role/" + memberRole, teams: allTeams, groups: groupsOf(allTeams, memberRole),
+			rules: rbac.OrganizationMemberPolicyRules()},
+	}
+
+	for _, trb := range a.teamRoleBindings {
+		teams := []string{trb.Spec.TeamRef}
+		if trb.Spec.IncludeChildTeams {
+			teams = append(teams, rbac.DescendantTeams(trb.Spec.TeamRef, a.teams)...)
+		}
+		// The members of every team are bound by the IdP group of their team.
+		groups := make(map[string]string, len(teams))
+		for _, team := range a.teams {
+			if slices.Contains(teams, team.GetName()) {
+				groups[team.GetName()] = team.Spec.MappedIDPGroup
+			}
+		}
+		for _, ps := range trb.Status.PropagationStatus {
+			if ps.Status != metav1.ConditionTrue {
+				continue
+			}
+			rules, err := a.effectiveRules(trb.Spec.TeamRoleRef, ps.ClusterName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute the rules of TeamRole %s: %w", trb.Spec.TeamRoleRef, err)
+			}
+			g := grant{
+				cluster:   ps.ClusterName,
+				binding:   "TeamRoleBinding/" + trb.GetName(),
+				role:      "TeamRole/" + trb.Spec.TeamRoleRef,
+				teams:     teams,
+				groups:    groups,
+				usernames: trb.Spec.Usernames,
+				rules:     rules,
+			}
+			if len(trb.Spec.Namespaces) == 0 && trb.Spec.NamespaceSelector == nil {
+				grants = append(grants, g)
+				continue
+			}
+			for _, ns := range ps.Namespaces {
+				if ns.Status == metav1.ConditionTrue {
+					g.namespace = ns.Namespace
+					grants = append(grants, g)
+				}
+			}
+		}
+	}
+	return grants, nil
+}
+
+// effectiveRules returns the rules of the ClusterRole rendered from the TeamRole on the cluster.
+// The rules of an aggregated ClusterRole are the rules of the TeamRoles selected by its AggregationRule, which exist on the cluster.
+// ClusterRoles not managed by Greenhouse matching the AggregationRule are not known and therefore not considered.
+func (a *accessReview) effectiveRules(teamRoleName, cluster string) ([]rbacv1.PolicyRule, error) {
+	teamRole, ok := a.teamRoles[teamRoleName]
+	if !ok {
+		return nil, nil
+	}
+	var onCluster []greenhouseapisv1alpha1.TeamRole
+	for _, name := range slices.Sorted(maps.Keys(a.teamRoles)) {
+		if slices.Contains(a.teamRoles[name].Status.Clusters, cluster) {
+			onCluster = append(onCluster, *a.teamRoles[name])
+		}
+	}
+	return rbac.EffectiveTeamRoleRules(teamRole, onCluster)
+}
+
+// teamsOf returns the teams the user is a member of, the user is identified by ID or email.
+func (a *accessReview) teamsOf(user string) []string {
+	var teams []string
+	for _, team := range a.teams {
+		if slices.ContainsFunc(a.members[team.GetName()], func(member greenhouseapisv1alpha1.User) bool {
+			return isUser(member, user)
+		}) {
+			teams = append(teams, team.GetName())
+		}
+	}
+	return teams
+}
+
+// isUser returns true if the user is identified by the ID or email.
+func isUser(user greenhouseapisv1alpha1.User, idOrEmail string) bool {
+	return user.ID == idOrEmail || (user.Email != "" && strings.EqualFold(user.Email, idOrEmail))
+}
+
+// formatResources returns the resources of the PolicyRule in the form resource[.group].
+func formatResources(rule rbacv1.PolicyRule) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return strings.Join(rule.NonResourceURLs, ",")
+	}
+	var resources []string
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			if group != "" {
+				resource += "." + group
+			}
+			resources = append(resources, resource)
+		}
+	}
+	return strings.Join(resources, ",")
+}
+
+// subjectAccessReviewer verifies the access against SubjectAccessReviews on the Greenhouse and the remote clusters.
+type subjectAccessReviewer struct {
+	ghClient client.Client
+	orgName  string
+	clients  map[string]client.Client
+}
+
+func newSubjectAccessReviewer(ghClient client.Client, orgName string) *subjectAccessReviewer {
+	return &subjectAccessReviewer{ghClient: ghClient, orgName: orgName, clients: map[string]client.Client{greenhouseClusterName: ghClient}}
+}
+
+// review returns whether the user or group is allowed the request on the cluster.
+func (r *subjectAccessReviewer) review(ctx context.Context, cluster, user, group string, attrs authorizationv1.ResourceAttributes) (bool, error) {
+	c, err := r.clientFor(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{ResourceAttributes: &attrs, User: user},
+	}
+	if group != "" {
+		sar.Spec.Groups = []string{group}
+	}
+	if err := c.Create(ctx, sar); err != nil {
+		return false, err
+	}
+	return sar.Status.Allowed, nil
+}
+
+func (r *subjectAccessReviewer) clientFor(ctx context.Context, clusterName string) (client.Client, error) {
+	if c, ok := r.clients[clusterName]; ok {
+		return c, nil
+	}
+	cluster := new(greenhouseapisv1alpha1.Cluster)
+	if err := r.ghClient.Get(ctx, client.ObjectKey{Namespace: r.orgName, Name: clusterName}, cluster); err != nil {
+		return nil, err
+	}
+	c, err := clientutil.NewK8sClientFromCluster(ctx, r.ghClient, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a client for cluster %s: %w", clusterName, err)
+	}
+	r.clients[clusterName] = c
+	return c, nil
+}
+
+// verificationResult formats the result of a SubjectAccessReview.
+func verificationResult(allowed bool, err error) string {
+	switch {
+	case err != nil:
+		return "error: " + err.Error()
+	case allowed:
+		return "allowed"
+	default:
+		return "denied"
+	}
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var rbacAccessForCmdUsage = "access-for (user|team) NAME"
+
+type rbacAccessForOptions struct {
+	ghClient             client.Client
+	greenhouseKubeConfig string
+	orgName              string
+	clusterName          string
+	verify               bool
+	kind                 string
+	name                 string
+}
+
+func init() {
+	rbacCmd.AddCommand(newRBACAccessForCmd())
+}
+
+func newRBACAccessForCmd() *cobra.Command {
+	o := &rbacAccessForOptions{}
+	accessForCmd := &cobra.Command{
+		Use:   rbacAccessForCmdUsage,
+		Short: "List the access granted to a user or team by Greenhouse",
+		Long: "List the rules granted to a user or team on the clusters of an organization by TeamRoleBindings,\n" +
+			"and in the Greenhouse cluster by the organization roles.\n" +
+			"Users are identified by their ID or email and are granted the access of the teams they are a member of.",
+		Example: "  greenhousectl rbac access-for user jane.doe@example.com --org my-org\n" +
+			"  greenhousectl rbac access-for team my-team --org my-org --cluster-name my-cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.complete(args); err != nil {
+				return err
+			}
+			var err error
+			if o.ghClient, err = newGreenhouseClient(o.greenhouseKubeConfig); err != nil {
+				return err
+			}
+			return o.run(cmd.OutOrStdout())
+		},
+	}
+
+	addGreenhouseOrgFlags(accessForCmd, &o.greenhouseKubeConfig, &o.orgName)
+	accessForCmd.Flags().StringVar(&o.clusterName, "cluster-name", "", "The cluster to review, defaults to all clusters")
+	accessForCmd.Flags().BoolVar(&o.verify, "verify", false, "Verify the access with SubjectAccessReviews on the clusters, requires access to the cluster secrets")
+	accessForCmd.SilenceUsage = true
+
+	return accessForCmd
+}
+
+func (o *rbacAccessForOptions) complete(args []string) error {
+	if len(args) != 2 || (args[0] != "user" && args[0] != "team") {
+		return errors.New(rbacAccessForCmdUsage)
+	}
+	o.kind, o.name = args[0], args[1]
+	return nil
+}
+
+func (o *rbacAccessForOptions) run(out io.Writer) error {
+	review, err := loadAccessReview(ctx, o.ghClient, o.orgName)
+	if err != nil {
+		return err
+	}
+	teams := []string{o.name}
+	if o.kind == "user" {
+		teams = review.teamsOf(o.name)
+	}
+	var reviewer *subjectAccessReviewer
+	if o.verify {
+		reviewer = newSubjectAccessReviewer(o.ghClient, o.orgName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CLUSTER\tNAMESPACE\tBINDING\tROLE\tVIA\tVERBS\tRESOURCES")
+	if o.verify {
+		sb.WriteString("\tVERIFIED")
+	}
+	sb.WriteString("\n")
+	grants, err := review.grants()
+	if err != nil {
+		return err
+	}
+	var rows int
+	for _, g := range grants {
+		if !g.appliesTo(o.clusterName, "", true) {
+			continue
+		}
+		// The user and group of the SubjectAccessReview verifying the access.
+		var via, user, group string
+		switch idx := slices.IndexFunc(teams, g.grantsTo); {
+		case idx >= 0:
+			via, group = "team "+teams[idx], g.groups[teams[idx]]
+			if o.kind == "user" {
+				user = o.name
+			}
+		case o.kind == "user" && slices.Contains(g.usernames, o.name):
+			via, user = "user", o.name
+		default:
+			continue
+		}
+		for _, rule := range g.rules {
+			fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\t%s\t%s\t%s", g.cluster, valueOrAll(g.namespace), g.binding, g.role, via,
+				strings.Join(rule.Verbs, ","), formatResources(rule))
+			if o.verify {
+				sb.WriteString("\t" + verifyRule(reviewer, g, rule, user, group))
+			}
+			sb.WriteString("\n")
+			rows++
+		}
+	}
+	if rows == 0 {
+		_, err := fmt.Fprintf(out, "No access is granted to %s %s\n", o.kind, o.name)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// verifyRule reviews every verb on every resource of the rule and returns the number of denied requests.
+func verifyRule(reviewer *subjectAccessReviewer, g grant, rule rbacv1.PolicyRule, user, group string) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return "skipped"
+	}
+	var reviewed, denied int
+	for _, verb := range rule.Verbs {
+		for _, apiGroup := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				attrs := authorizationv1.ResourceAttributes{Namespace: g.namespace, Verb: verb, Group: apiGroup}
+				attrs.Resource, attrs.Subresource, _ = strings.Cut(resource, "/")
+				if len(rule.ResourceNames) > 0 {
+					attrs.Name = rule.ResourceNames[0]
+				}
+				allowed, err := reviewer.review(ctx, g.cluster, user, group, attrs)
+				if err != nil {
+					return verificationResult(false, err)
+				}
+				reviewed++
+				if !allowed {
+					denied++
+				}
+			}
+		}
+	}
+	if denied > 0 {
+		return fmt.Sprintf("denied %d of %d", denied, reviewed)
+	}
+	return verificationResult(true, nil)
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/rbac"
+)
+
+var rbacLintCmdUsage = "lint [TEAMROLE...]"
+
+type rbacLintOptions struct {
+	ghClient             client.Client
+	greenhouseKubeConfig string
+	orgName              string
+	sensitiveResources   []string
+	sensitiveVerbs       []string
+	teamRoleNames        []string
+}
+
+func init() {
+	rbacCmd.AddCommand(newRBACLintCmd())
+}
+
+func newRBACLintCmd() *cobra.Command {
+	o := &rbacLintOptions{}
+	lintCmd := &cobra.Command{
+		Use:   rbacLintCmdUsage,
+		Short: "Check the TeamRoles of an organization for privilege escalation",
+		Long: "Check the effective rules of the TeamRoles of an organization, including the rules of aggregated TeamRoles,\n" +
+			"for sensitive resources and verbs. The TeamRolePolicy of the organization is used unless overridden by flags.\n" +
+			"The command fails if any TeamRole violates the policy.",
+		Example: "  greenhousectl rbac lint --org my-org\n" +
+			"  greenhousectl rbac lint cluster-developer --org my-org --sensitive-resources secrets,pods/exec",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.teamRoleNames = args
+			var err error
+			if o.ghClient, err = newGreenhouseClient(o.greenhouseKubeConfig); err != nil {
+				return err
+			}
+			return o.run(cmd.OutOrStdout())
+		},
+	}
+
+	addGreenhouseOrgFlags(lintCmd, &o.greenhouseKubeConfig, &o.orgName)
+	lintCmd.Flags().StringSliceVar(&o.sensitiveResources, "sensitive-resources", nil, "The sensitive resources in the format resource[.group][/subresource], overrides the policy of the organization")
+	lintCmd.Flags().StringSliceVar(&o.sensitiveVerbs, "sensitive-verbs", nil, "The sensitive verbs, overrides the policy of the organization")
+	lintCmd.SilenceUsage = true
+
+	return lintCmd
+}
+
+func (o *rbacLintOptions) run(out io.Writer) error {
+	org := new(greenhousev1alpha1.Organization)
+	if err := o.ghClient.Get(ctx, types.NamespacedName{Name: o.orgName}, org); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	policy := new(greenhousev1alpha1.TeamRolePolicy)
+	if org.Spec.TeamRolePolicy != nil {
+		policy = org.Spec.TeamRolePolicy.DeepCopy()
+	}
+	if len(o.sensitiveResources) > 0 {
+		policy.SensitiveResources = o.sensitiveResources
+	}
+	if len(o.sensitiveVerbs) > 0 {
+		policy.SensitiveVerbs = o.sensitiveVerbs
+	}
+
+	teamRoles := new(greenhousev1alpha1.TeamRoleList)
+	if err := o.ghClient.List(ctx, teamRoles, client.InNamespace(o.orgName)); err != nil {
+		return err
+	}
+	for _, name := range o.teamRoleNames {
+		if !slices.ContainsFunc(teamRoles.Items, func(tr greenhousev1alpha1.TeamRole) bool { return tr.GetName() == name }) {
+			return fmt.Errorf("TeamRole %s not found in organization %s", name, o.orgName)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("TEAMROLE\tFINDING\n")
+	var violations int
+	for _, teamRole := range teamRoles.Items {
+		if len(o.teamRoleNames) > 0 && !slices.Contains(o.teamRoleNames, teamRole.GetName()) {
+			continue
+		}
+		findings, err := rbac.LintTeamRole(&teamRole, teamRoles.Items, policy)
+		if err != nil {
+			return fmt.Errorf("failed to lint TeamRole %s: %w", teamRole.GetName(), err)
+		}
+		if len(findings) > 0 {
+			violations++
+		}
+		for _, finding := range findings {
+			fmt.Fprintf(&sb, "%s\t%s\n", teamRole.GetName(), finding)
+		}
+	}
+	if violations == 0 {
+		_, err := fmt.Fprintln(out, "No TeamRoles violate the TeamRolePolicy")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%d TeamRoles violate the TeamRolePolicy of organization %s", violations, o.orgName)
+}
@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+	"github.com/cloudoperators/greenhouse/pkg/rbac"
+)
+
+var _ = Describe("Review the access granted by Greenhouse", func() {
+	const namespace = "test-org"
+
+	var ghClient client.Client
+
+	BeforeEach(func() {
+		objects := []client.Object{
+			&greenhousev1alpha1.Organization{
+				ObjectMeta: metav1.ObjectMeta{Name: namespace},
+				Spec:       greenhousev1alpha1.OrganizationSpec{MappedOrgAdminIDPGroup: "idp-admins"},
+			},
+			&greenhousev1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: namespace},
+				Spec:       greenhousev1alpha1.TeamSpec{MappedIDPGroup: "idp-team-a"},
+			},
+			&greenhousev1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-admins", Namespace: namespace},
+				Spec:       greenhousev1alpha1.TeamSpec{MappedIDPGroup: "idp-admins"},
+			},
+			&greenhousev1alpha1.TeamMembership{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: namespace},
+				Spec:       greenhousev1alpha1.TeamMembershipSpec{Members: []greenhousev1alpha1.User{{ID: "alice", Email: "alice@example.com"}}},
+			},
+			&greenhousev1alpha1.TeamMembership{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-admins", Namespace: namespace},
+				Spec:       greenhousev1alpha1.TeamMembershipSpec{Members: []greenhousev1alpha1.User{{ID: "bob", Email: "bob@example.com"}}},
+			},
+			&greenhousev1alpha1.TeamRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-deleter", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleSpec{
+					Rules: []rbacv1.PolicyRule{{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+				},
+			},
+			&greenhousev1alpha1.TeamRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "viewer", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleSpec{
+					Rules:  []rbacv1.PolicyRule{{Verbs: []string{"get", "list"}, APIGroups: []string{"", "apps"}, Resources: []string{"pods", "deployments"}}},
+					Labels: map[string]string{"aggregate": "true"},
+				},
+				Status: greenhousev1alpha1.TeamRoleStatus{Clusters: []string{"cluster-a"}},
+			},
+			&greenhousev1alpha1.TeamRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "aggregator", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleSpec{
+					AggregationRule: &rbacv1.AggregationRule{
+						ClusterRoleSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"aggregate": "true"}}},
+					},
+				},
+				Status: greenhousev1alpha1.TeamRoleStatus{AggregatedTeamRoles: []string{"viewer"}},
+			},
+			&greenhousev1alpha1.TeamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "trb-delete", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleBindingSpec{
+					TeamRef: "team-a", TeamRoleRef: "pod-deleter", ClusterName: "cluster-a", Namespaces: []string{"kube-system"},
+				},
+				Status: greenhousev1alpha1.TeamRoleBindingStatus{
+					PropagationStatus: []greenhousev1alpha1.TeamRoleBindingPropagationStatus{{
+						PropagationStatus: greenhousev1alpha1.PropagationStatus{
+							ClusterName: "cluster-a",
+							Condition:   greenhousev1alpha1.TrueCondition(greenhousev1alpha1.RBACReady, greenhousev1alpha1.RBACReconciled, ""),
+						},
+						Namespaces: []greenhousev1alpha1.NamespacePropagationStatus{{
+							Namespace: "kube-system",
+							Condition: greenhousev1alpha1.TrueCondition(greenhousev1alpha1.RBACReady, greenhousev1alpha1.RBACReconciled, ""),
+						}},
+					}},
+				},
+			},
+			&greenhousev1alpha1.TeamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "trb-aggregated", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleBindingSpec{
+					TeamRef: "team-admins", TeamRoleRef: "aggregator", ClusterName: "cluster-a", Usernames: []string{"carol"},
+				},
+				Status: greenhousev1alpha1.TeamRoleBindingStatus{
+					PropagationStatus: []greenhousev1alpha1.TeamRoleBindingPropagationStatus{{
+						PropagationStatus: greenhousev1alpha1.PropagationStatus{
+							ClusterName: "cluster-a",
+							Condition:   greenhousev1alpha1.TrueCondition(greenhousev1alpha1.RBACReady, greenhousev1alpha1.RBACReconciled, ""),
+						},
+					}},
+				},
+			},
+			&greenhousev1alpha1.TeamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "trb-failed", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleBindingSpec{
+					TeamRef: "team-a", TeamRoleRef: "pod-deleter", ClusterName: "cluster-b",
+				},
+				Status: greenhousev1alpha1.TeamRoleBindingStatus{
+					PropagationStatus: []greenhousev1alpha1.TeamRoleBindingPropagationStatus{{
+						PropagationStatus: greenhousev1alpha1.PropagationStatus{
+							ClusterName: "cluster-b",
+							Condition:   greenhousev1alpha1.FalseCondition(greenhousev1alpha1.RBACReady, greenhousev1alpha1.ClusterConnectionFailed, ""),
+						},
+					}},
+				},
+			},
+		}
+		ghClient = fake.NewClientBuilder().WithScheme(clientutil.Scheme).WithObjects(objects...).Build()
+	})
+
+	Context("who-can", func() {
+		runWhoCan := func(o *rbacWhoCanOptions, args ...string) string {
+			o.ghClient, o.orgName = ghClient, namespace
+			Expect(o.complete(args)).To(Succeed(), "there should be no error parsing the arguments")
+			out := new(bytes.Buffer)
+			Expect(o.run(out)).To(Succeed(), "there should be no error reviewing the access")
+			return out.String()
+		}
+
+		It("should list the members of the teams bound in the namespace", func() {
+			out := runWhoCan(&rbacWhoCanOptions{clusterName: "cluster-a", namespace: "kube-system"}, "delete", "pods")
+			Expect(out).To(MatchRegexp(`cluster-a\s+kube-system\s+alice\s+team-a\s+TeamRoleBinding/trb-delete\s+TeamRole/pod-deleter`), "the team member should be listed")
+			Expect(out).ToNot(ContainSubstring("bob"), "members of teams without the permission should not be listed")
+		})
+
+		It("should not list access granted in other namespaces for cluster-scoped actions", func() {
+			out := runWhoCan(&rbacWhoCanOptions{clusterName: "cluster-a"}, "delete", "pods")
+			Expect(out).To(ContainSubstring("No users or teams are allowed the action"), "no one should be allowed to delete pods in all namespaces")
+		})
+
+		It("should resolve the rules of aggregated TeamRoles and list the usernames", func() {
+			out := runWhoCan(&rbacWhoCanOptions{clusterName: "cluster-a", namespace: "default"}, "list", "deployments.apps")
+			Expect(out).To(MatchRegexp(`cluster-a\s+\*\s+bob\s+team-admins\s+TeamRoleBinding/trb-aggregated\s+TeamRole/aggregator`), "the member of the team bound to the aggregated TeamRole should be listed")
+			Expect(out).To(MatchRegexp(`cluster-a\s+\*\s+carol\s+-\s+TeamRoleBinding/trb-aggregated`), "the username of the TeamRoleBinding should be listed")
+		})
+
+		It("should list the members of child teams if the TeamRoleBinding includes them", func() {
+			Expect(ghClient.Create(ctx, &greenhousev1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a-child", Namespace: namespace},
+				Spec:       greenhousev1alpha1.TeamSpec{MappedIDPGroup: "idp-team-a-child", ParentTeamRef: "team-a"},
+			})).To(Succeed(), "there should be no error creating the child team")
+			Expect(ghClient.Create(ctx, &greenhousev1alpha1.TeamMembership{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a-child", Namespace: namespace},
+				Spec:       greenhousev1alpha1.TeamMembershipSpec{Members: []greenhousev1alpha1.User{{ID: "dave"}}},
+			})).To(Succeed(), "there should be no error creating the TeamMembership of the child team")
+
+			out := runWhoCan(&rbacWhoCanOptions{clusterName: "cluster-a", namespace: "kube-system"}, "delete", "pods")
+			Expect(out).ToNot(ContainSubstring("dave"), "members of child teams should not be listed by default")
+
+			trb := &greenhousev1alpha1.TeamRoleBinding{}
+			Expect(ghClient.Get(ctx, client.ObjectKey{Name: "trb-delete", Namespace: namespace}, trb)).To(Succeed(), "there should be no error getting the TeamRoleBinding")
+			trb.Spec.IncludeChildTeams = true
+			Expect(ghClient.Update(ctx, trb)).To(Succeed(), "there should be no error updating the TeamRoleBinding")
+
+			out = runWhoCan(&rbacWhoCanOptions{clusterName: "cluster-a", namespace: "kube-system"}, "delete", "pods")
+			Expect(out).To(MatchRegexp(`cluster-a\s+kube-system\s+dave\s+team-a-child\s+TeamRoleBinding/trb-delete`), "the member of the child team should be listed")
+
+			review, err := loadAccessReview(ctx, ghClient, namespace)
+			Expect(err).ToNot(HaveOccurred(), "there should be no error loading the access review")
+			grants, err := review.grants()
+			Expect(err).ToNot(HaveOccurred(), "there should be no error computing the grants")
+			idx := slices.IndexFunc(grants, func(g grant) bool { return g.binding == "TeamRoleBinding/trb-delete" })
+			Expect(idx).To(BeNumerically(">=", 0), "the TeamRoleBinding should grant access")
+			Expect(grants[idx].groups).To(Equal(map[string]string{"team-a": "idp-team-a", "team-a-child": "idp-team-a-child"}), "every team should be bound by its own IdP group")
+		})
+
+		It("should list the access granted by the organization roles", func() {
+			out := runWhoCan(&rbacWhoCanOptions{namespace: namespace}, "create", "teams.greenhouse.sap")
+			Expect(out).To(MatchRegexp(`\(greenhouse\)\s+test-org\s+bob\s+team-admins\s+RoleBinding/role:test-org:admin`), "the organization admins should be listed")
+			Expect(out).ToNot(ContainSubstring("alice"), "organization members should not be listed")
+		})
+	})
+
+	Context("access-for", func() {
+		runAccessFor := func(o *rbacAccessForOptions, args ...string) string {
+			o.ghClient, o.orgName = ghClient, namespace
+			Expect(o.complete(args)).To(Succeed(), "there should be no error parsing the arguments")
+			out := new(bytes.Buffer)
+			Expect(o.run(out)).To(Succeed(), "there should be no error reviewing the access")
+			return out.String()
+		}
+
+		It("should list the access of a user identified by email", func() {
+			out := runAccessFor(&rbacAccessForOptions{}, "user", "alice@example.com")
+			Expect(out).To(MatchRegexp(`cluster-a\s+kube-system\s+TeamRoleBinding/trb-delete\s+TeamRole/pod-deleter\s+team team-a\s+delete\s+pods`), "the access granted to the team of the user should be listed")
+			Expect(out).To(MatchRegexp(`\(greenhouse\)\s+test-org\s+RoleBinding/organization:test-org\s+`), "the access of organization members should be listed")
+			Expect(out).ToNot(ContainSubstring("cluster-b"), "TeamRoleBindings not propagated should not be listed")
+			Expect(out).ToNot(ContainSubstring("role:test-org:admin"), "the access of organization admins should not be listed")
+		})
+
+		It("should list the access of a team on a cluster", func() {
+			out := runAccessFor(&rbacAccessForOptions{clusterName: "cluster-a"}, "team", "team-admins")
+			Expect(out).To(MatchRegexp(`cluster-a\s+\*\s+TeamRoleBinding/trb-aggregated\s+TeamRole/aggregator\s+team team-admins\s+get,list\s+pods,deployments,pods.apps,deployments.apps`), "the rules of the aggregated TeamRoles should be listed")
+			Expect(out).ToNot(ContainSubstring("(greenhouse)"), "the access in the Greenhouse cluster should not be listed")
+		})
+
+		It("should list the access of a user granted by username", func() {
+			out := runAccessFor(&rbacAccessForOptions{}, "user", "carol")
+			Expect(out).To(MatchRegexp(`cluster-a\s+\*\s+TeamRoleBinding/trb-aggregated\s+TeamRole/aggregator\s+user\s+get,list`), "the access granted to the username should be listed")
+		})
+	})
+
+	Context("lint", func() {
+		runLint := func(o *rbacLintOptions) (string, error) {
+			o.ghClient, o.orgName = ghClient, namespace
+			out := new(bytes.Buffer)
+			err := o.run(out)
+			return out.String(), err
+		}
+
+		BeforeEach(func() {
+			Expect(ghClient.Create(ctx, &greenhousev1alpha1.TeamRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-exec", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleSpec{
+					Rules:  []rbacv1.PolicyRule{{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/exec"}}},
+					Labels: map[string]string{"aggregate": "true"},
+				},
+			})).To(Succeed(), "there should be no error creating the TeamRole")
+		})
+
+		It("should report sensitive resources in the effective rules of TeamRoles", func() {
+			out, err := runLint(&rbacLintOptions{})
+			Expect(err).To(MatchError(ContainSubstring("2 TeamRoles violate the TeamRolePolicy")), "the command should fail for violations")
+			Expect(out).To(MatchRegexp(`pod-exec\s+grants create on the sensitive resource pods/exec`), "the TeamRole granting pods/exec should be reported")
+			Expect(out).To(MatchRegexp(`pod-exec\s+is aggregated into the TeamRoles aggregator`), "the aggregating TeamRoles should be named")
+			Expect(out).To(MatchRegexp(`aggregator\s+grants create on the sensitive resource pods/exec`), "the aggregated rules should be reported")
+			Expect(out).ToNot(ContainSubstring("viewer"), "TeamRoles without sensitive rules should not be reported")
+		})
+
+		It("should use the sensitive resources and verbs given by flags", func() {
+			out, err := runLint(&rbacLintOptions{sensitiveResources: []string{"deployments.apps"}, sensitiveVerbs: []string{"delete"}})
+			Expect(err).To(HaveOccurred(), "the command should fail for violations")
+			Expect(out).To(MatchRegexp(`viewer\s+grants get,list on the sensitive resource deployments.apps`), "the sensitive resource should be reported")
+			Expect(out).To(MatchRegexp(`pod-deleter\s+grants the sensitive verbs delete on pods`), "the sensitive verb should be reported")
+			Expect(out).ToNot(ContainSubstring("pods/exec"), "the default sensitive resources should not be used")
+		})
+
+		It("should exempt the cluster-admin TeamRole without a TeamRolePolicy", func() {
+			Expect(ghClient.Create(ctx, &greenhousev1alpha1.TeamRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin", Namespace: namespace},
+				Spec: greenhousev1alpha1.TeamRoleSpec{
+					Rules: []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+				},
+			})).To(Succeed(), "there should be no error creating the TeamRole")
+			out, err := runLint(&rbacLintOptions{})
+			Expect(err).To(HaveOccurred(), "the command should fail for violations")
+			Expect(out).ToNot(ContainSubstring("cluster-admin"), "the cluster-admin TeamRole should be exempt by default")
+		})
+
+		It("should only lint the given TeamRoles", func() {
+			out, err := runLint(&rbacLintOptions{teamRoleNames: []string{"viewer"}})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error for TeamRoles without violations")
+			Expect(out).To(ContainSubstring("No TeamRoles violate the TeamRolePolicy"), "no violations should be reported")
+		})
+	})
+
+	DescribeTable("should parse resources in the kubectl format",
+		func(value, resource, group, subresource string) {
+			r, g, s := rbac.ParseResource(value)
+			Expect([]string{r, g, s}).To(Equal([]string{resource, group, subresource}), "the resource should be parsed")
+		},
+		Entry("core resource", "pods", "pods", "", ""),
+		Entry("resource with group", "deployments.apps", "deployments", "apps", ""),
+		Entry("subresource", "pods/log", "pods", "", "log"),
+		Entry("resource with group and subresource", "deployments.apps/scale", "deployments", "apps", "scale"),
+	)
+})
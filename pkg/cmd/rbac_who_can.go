@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudoperators/greenhouse/pkg/rbac"
+)
+
+var rbacWhoCanCmdUsage = "who-can VERB RESOURCE[.GROUP][/SUBRESOURCE] [NAME]"
+
+type rbacWhoCanOptions struct {
+	ghClient             client.Client
+	greenhouseKubeConfig string
+	orgName              string
+	clusterName          string
+	namespace            string
+	allNamespaces        bool
+	verify               bool
+	attrs                authorizationv1.ResourceAttributes
+}
+
+func init() {
+	rbacCmd.AddCommand(newRBACWhoCanCmd())
+}
+
+func newRBACWhoCanCmd() *cobra.Command {
+	o := &rbacWhoCanOptions{}
+	whoCanCmd := &cobra.Command{
+		Use:   rbacWhoCanCmdUsage,
+		Short: "List the users and teams allowed an action by Greenhouse",
+		Long: "List the users and teams allowed an action on the clusters of an organization by TeamRoleBindings,\n" +
+			"and in the organization namespace of the Greenhouse cluster by the organization roles.\n" +
+			"The access is computed from the TeamRoleBindings, TeamRoles and TeamMemberships of the organization.",
+		Example: "  greenhousectl rbac who-can delete pods --org my-org --cluster-name my-cluster --namespace kube-system",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.complete(args); err != nil {
+				return err
+			}
+			var err error
+			if o.ghClient, err = newGreenhouseClient(o.greenhouseKubeConfig); err != nil {
+				return err
+			}
+			return o.run(cmd.OutOrStdout())
+		},
+	}
+
+	addGreenhouseOrgFlags(whoCanCmd, &o.greenhouseKubeConfig, &o.orgName)
+	whoCanCmd.Flags().StringVar(&o.clusterName, "cluster-name", "", "The cluster to review, defaults to all clusters")
+	whoCanCmd.Flags().StringVarP(&o.namespace, "namespace", "n", "", "The namespace of the action, omit for cluster-scoped actions")
+	whoCanCmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "Include the access granted in any namespace")
+	whoCanCmd.Flags().BoolVar(&o.verify, "verify", false, "Verify the access with SubjectAccessReviews on the clusters, requires access to the cluster secrets")
+	whoCanCmd.MarkFlagsMutuallyExclusive("namespace", "all-namespaces")
+	whoCanCmd.SilenceUsage = true
+
+	return whoCanCmd
+}
+
+func (o *rbacWhoCanOptions) complete(args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return errors.New(rbacWhoCanCmdUsage)
+	}
+	o.attrs.Verb = args[0]
+	o.attrs.Resource, o.attrs.Group, o.attrs.Subresource = rbac.ParseResource(args[1])
+	if len(args) == 3 {
+		o.attrs.Name = args[2]
+	}
+	o.attrs.Namespace = o.namespace
+	return nil
+}
+
+func (o *rbacWhoCanOptions) run(out io.Writer) error {
+	review, err := loadAccessReview(ctx, o.ghClient, o.orgName)
+	if err != nil {
+		return err
+	}
+	var reviewer *subjectAccessReviewer
+	if o.verify {
+		reviewer = newSubjectAccessReviewer(o.ghClient, o.orgName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CLUSTER\tNAMESPACE\tUSER\tTEAM\tBINDING\tROLE")
+	if o.verify {
+		sb.WriteString("\tVERIFIED")
+	}
+	sb.WriteString("\n")
+	grants, err := review.grants()
+	if err != nil {
+		return err
+	}
+	var rows int
+	for _, g := range grants {
+		if !g.appliesTo(o.clusterName, o.namespace, o.allNamespaces) || !rbac.RulesAllow(g.rules, &o.attrs) {
+			continue
+		}
+		attrs := o.attrs
+		attrs.Namespace = cmp.Or(g.namespace, o.namespace)
+		for _, team := range g.teams {
+			verified := ""
+			if o.verify {
+				verified = "\t" + verificationResult(reviewer.review(ctx, g.cluster, "", g.groups[team], attrs))
+			}
+			members := review.members[team]
+			rows += max(len(members), 1)
+			if len(members) == 0 {
+				fmt.Fprintf(&sb, "%s\t%s\t-\t%s\t%s\t%s%s\n", g.cluster, valueOrAll(g.namespace), team, g.binding, g.role, verified)
+			}
+			for _, member := range members {
+				fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\t%s\t%s%s\n", g.cluster, valueOrAll(g.namespace), member.ID, team, g.binding, g.role, verified)
+			}
+		}
+		for _, username := range g.usernames {
+			verified := ""
+			if o.verify {
+				verified = "\t" + verificationResult(reviewer.review(ctx, g.cluster, username, "", attrs))
+			}
+			fmt.Fprintf(&sb, "%s\t%s\t%s\t-\t%s\t%s%s\n", g.cluster, valueOrAll(g.namespace), username, g.binding, g.role, verified)
+			rows++
+		}
+	}
+	if rows == 0 {
+		_, err := fmt.Fprintln(out, "No users or teams are allowed the action")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// valueOrAll formats the namespace of a grant, an empty namespace stands for all namespaces.
+func valueOrAll(namespace string) string {
+	if namespace == "" {
+		return "*"
+	}
+	return namespace
+}
@@ -4,6 +4,7 @@
 package cluster
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
@@ -115,11 +116,16 @@ func (r *BootstrapReconciler) createKubeConfigKey(ctx context.Context, secret *c
 
 	// generate kubeconfig with oidc token
 	generator := &utils.KubeConfigHelper{
-		Host:        remoteAPIServerURL,
-		CAData:      certDecoded,
-		BearerToken: tokenRequest.Status.Token,
-		Username:    fmt.Sprintf("system:serviceaccount:%s:%s", clusterResourceSA.GetNamespace(), clusterResourceSA.GetName()),
-		Namespace:   clusterResourceSA.GetNamespace(),
+		Host:          remoteAPIServerURL,
+		CAData:        certDecoded,
+		BearerToken:   tokenRequest.Status.Token,
+		Username:      fmt.Sprintf("system:serviceaccount:%s:%s", clusterResourceSA.GetNamespace(), clusterResourceSA.GetName()),
+		Namespace:     clusterResourceSA.GetNamespace(),
+		TLSServerName: annotations[greenhouseapis.SecretTLSServerNameAnnotation],
+		ProxyURL:      annotations[greenhouseapis.SecretProxyURLAnnotation],
+	}
+	if extraCAData := secret.Data[greenhouseapis.ExtraCABundleKey]; len(extraCAData) > 0 {
+		generator.CAData = bytes.Join([][]byte{certDecoded, extraCAData}, []byte("\n"))
 	}
 	kubeconfigByte, err := clientcmd.Write(generator.RestConfigToAPIConfig(secret.GetName()))
 	if err != nil {
@@ -162,6 +168,9 @@ func (r *BootstrapReconciler) createOrPatchCluster(
 		return nil
 	}
 	accessMode := greenhousev1alpha1.ClusterAccessModeDirect
+	if kubeConfigSecret.GetAnnotations()[greenhouseapis.SecretClusterAccessModeAnnotation] == string(greenhousev1alpha1.ClusterAccessModeAgent) {
+		accessMode = greenhousev1alpha1.ClusterAccessModeAgent
+	}
 
 	cluster.SetName(kubeConfigSecret.Name)
 	cluster.SetNamespace(kubeConfigSecret.Namespace)
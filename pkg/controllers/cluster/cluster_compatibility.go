@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+// reconcilePluginsCompatibility checks the Kubernetes version of the cluster against the kubeVersion constraints of the PluginDefinitions of the Plugins deployed to it.
+// A warning is emitted if a change of the Kubernetes version, e.g. by an upgrade of the cluster, makes Plugins incompatible.
+func (r *RemoteClusterReconciler) reconcilePluginsCompatibility(ctx context.Context, cluster *greenhousev1alpha1.Cluster, kubeVersion string) greenhousev1alpha1.Condition {
+	condition := greenhousev1alpha1.UnknownCondition(greenhousev1alpha1.PluginsKubeVersionCompatible, "", "")
+	if kubeVersion == "" {
+		return condition
+	}
+
+	pluginList := new(greenhousev1alpha1.PluginList)
+	if err := r.List(ctx, pluginList, client.InNamespace(cluster.GetNamespace()), client.MatchingLabels{greenhouseapis.LabelKeyCluster: cluster.GetName()}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list the plugins of the cluster")
+		return condition
+	}
+	kubeVersionConstraints := make(map[string]string)
+	var incompatiblePlugins []string
+	for _, plugin := range pluginList.Items {
+		constraint, ok := kubeVersionConstraints[plugin.Spec.PluginDefinition]
+		if !ok {
+			pluginDefinition := new(greenhousev1alpha1.PluginDefinition)
+			if err := r.Get(ctx, client.ObjectKey{Name: plugin.Spec.PluginDefinition}, pluginDefinition); client.IgnoreNotFound(err) != nil {
+				ctrl.LoggerFrom(ctx).Error(err, "failed to get the plugin definition", "pluginDefinition", plugin.Spec.PluginDefinition)
+				return condition
+			}
+			constraint = pluginDefinition.Status.KubeVersion
+			kubeVersionConstraints[plugin.Spec.PluginDefinition] = constraint
+		}
+		if !clientutil.IsKubeVersionCompatible(constraint, kubeVersion) {
+			incompatiblePlugins = append(incompatiblePlugins, fmt.Sprintf("%s (%s)", plugin.GetName(), constraint))
+		}
+	}
+	if len(incompatiblePlugins) == 0 {
+		return greenhousev1alpha1.TrueCondition(greenhousev1alpha1.PluginsKubeVersionCompatible, "", "")
+	}
+
+	slices.Sort(incompatiblePlugins)
+	message := fmt.Sprintf("Kubernetes %s is incompatible with the Plugins: %s", kubeVersion, strings.Join(incompatiblePlugins, ", "))
+	if previousVersion := cluster.Status.KubernetesVersion; previousVersion != "" && previousVersion != kubeVersion {
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, greenhousev1alpha1.KubeVersionIncompatibleEvent,
+			"Kubernetes version changed from %s to %s, the Plugins %s are incompatible", previousVersion, kubeVersion, strings.Join(incompatiblePlugins, ", "))
+	}
+	return greenhousev1alpha1.FalseCondition(greenhousev1alpha1.PluginsKubeVersionCompatible, greenhousev1alpha1.KubeVersionIncompatibleReason, message)
+}
@@ -6,6 +6,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -30,7 +31,7 @@ import (
 	"github.com/cloudoperators/greenhouse/pkg/clientutil"
 )
 
-// RemoteClusterReconciler reconciles a Cluster object with accessMode=direct set.
+// RemoteClusterReconciler reconciles a Cluster object with accessMode=direct or accessMode=agent set.
 type RemoteClusterReconciler struct {
 	client.Client
 	recorder                           record.EventRecorder
@@ -42,7 +43,8 @@ type RemoteClusterReconciler struct {
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=clusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=clusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;patch;create
-//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;update;patch;create
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=plugindefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;update;patch;create;delete
 //+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update;patch;create;delete
 //+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups="rbac",resources=clusterrolebindings,verbs=get;list;watch;update;patch;create
@@ -55,7 +57,7 @@ func (r *RemoteClusterReconciler) SetupWithManager(name string, mgr ctrl.Manager
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&greenhousev1alpha1.Cluster{}, builder.WithPredicates(
-			clientutil.PredicateClusterByAccessMode(greenhousev1alpha1.ClusterAccessModeDirect),
+			clientutil.PredicateClusterByAccessMode(greenhousev1alpha1.ClusterAccessModeDirect, greenhousev1alpha1.ClusterAccessModeAgent),
 		)).
 		// Watch the secret owned by this cluster.
 		Watches(&corev1.Secret{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &greenhousev1alpha1.Cluster{})).
@@ -72,7 +74,7 @@ func (r *RemoteClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 func (r *RemoteClusterReconciler) EnsureCreated(ctx context.Context, resource lifecycle.RuntimeObject) (ctrl.Result, lifecycle.ReconcileResult, error) {
 	cluster := resource.(*greenhousev1alpha1.Cluster) //nolint:errcheck
-	if cluster.Spec.AccessMode != greenhousev1alpha1.ClusterAccessModeDirect {
+	if cluster.Spec.AccessMode != greenhousev1alpha1.ClusterAccessModeDirect && cluster.Spec.AccessMode != greenhousev1alpha1.ClusterAccessModeAgent {
 		return ctrl.Result{}, lifecycle.Failed, nil
 	}
 	// Deletion Schedule mechanism
@@ -94,6 +96,9 @@ func (r *RemoteClusterReconciler) EnsureCreated(ctx context.Context, resource li
 	if err := r.Get(ctx, types.NamespacedName{Name: cluster.GetSecretName(), Namespace: cluster.GetNamespace()}, clusterSecret); err != nil {
 		return ctrl.Result{}, lifecycle.Failed, err
 	}
+	if err := r.reconcileSecretAccessMode(ctx, cluster, clusterSecret); err != nil {
+		return ctrl.Result{}, lifecycle.Failed, err
+	}
 
 	restClientGetter, err := clientutil.NewRestClientGetterFromSecret(clusterSecret, cluster.Namespace)
 	if err != nil {
@@ -126,12 +131,45 @@ func (r *RemoteClusterReconciler) EnsureCreated(ctx context.Context, resource li
 			return ctrl.Result{}, lifecycle.Failed, err
 		}
 	}
+	// The token is refreshed even if a rotation is requested, so a failing rotation does not let the current token expire.
 	if err := r.reconcileServiceAccountToken(ctx, restClientGetter, remoteClient, cluster, clusterSecret.Type); err != nil {
 		return ctrl.Result{}, lifecycle.Failed, err
 	}
+	if err := r.reconcileInventoryLabels(ctx, cluster); err != nil {
+		return ctrl.Result{}, lifecycle.Failed, err
+	}
+	if err := r.reconcileCredentialsRotation(ctx, cluster, clusterSecret, restClientGetter, remoteClient, crb); err != nil {
+		return ctrl.Result{}, lifecycle.Failed, err
+	}
 	return ctrl.Result{RequeueAfter: utils.DefaultRequeueInterval}, lifecycle.Success, nil
 }
 
+// reconcileSecretAccessMode - annotates the cluster secret with the access mode of the cluster, so clients created from the secret access the cluster accordingly
+func (r *RemoteClusterReconciler) reconcileSecretAccessMode(ctx context.Context, cluster *greenhousev1alpha1.Cluster, clusterSecret *corev1.Secret) error {
+	current := clusterSecret.GetAnnotations()[greenhouseapis.SecretClusterAccessModeAnnotation]
+	switch {
+	case cluster.Spec.AccessMode == greenhousev1alpha1.ClusterAccessModeAgent && current != string(greenhousev1alpha1.ClusterAccessModeAgent):
+		if len(clusterSecret.Data[greenhouseapis.AgentTokenKey]) == 0 {
+			return fmt.Errorf("secret %s/%s does not contain the agent token %s", clusterSecret.GetNamespace(), clusterSecret.GetName(), greenhouseapis.AgentTokenKey)
+		}
+	case cluster.Spec.AccessMode == greenhousev1alpha1.ClusterAccessModeDirect && current == string(greenhousev1alpha1.ClusterAccessModeAgent):
+	default:
+		return nil
+	}
+	_, err := clientutil.Patch(ctx, r.Client, clusterSecret, func() error {
+		if cluster.Spec.AccessMode == greenhousev1alpha1.ClusterAccessModeDirect {
+			delete(clusterSecret.Annotations, greenhouseapis.SecretClusterAccessModeAnnotation)
+			return nil
+		}
+		if clusterSecret.Annotations == nil {
+			clusterSecret.Annotations = make(map[string]string, 1)
+		}
+		clusterSecret.Annotations[greenhouseapis.SecretClusterAccessModeAnnotation] = string(cluster.Spec.AccessMode)
+		return nil
+	})
+	return err
+}
+
 // reconcileClusterRoleBindingInRemoteCluster - creates or updates the cluster role binding in the remote cluster
 func (r *RemoteClusterReconciler) reconcileClusterRoleBindingInRemoteCluster(ctx context.Context, k8sClient client.Client, cluster *greenhousev1alpha1.Cluster) (*rbacv1.ClusterRoleBinding, error) {
 	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
@@ -240,7 +278,7 @@ func (r *RemoteClusterReconciler) reconcileServiceAccountToken(
 	}
 	var generatedKubeConfig []byte
 	switch cluster.Spec.AccessMode {
-	case greenhousev1alpha1.ClusterAccessModeDirect:
+	case greenhousev1alpha1.ClusterAccessModeDirect, greenhousev1alpha1.ClusterAccessModeAgent:
 		generatedKubeConfig, err = utils.GenerateNewClientKubeConfig(restClientGetter, tokenRequest.Status.Token, cluster)
 		if err != nil {
 			return err
@@ -277,8 +315,22 @@ func (r *RemoteClusterReconciler) reconcileServiceAccountToken(
 }
 
 // EnsureDeleted - handles the deletion / cleanup of cluster resource
-func (r *RemoteClusterReconciler) EnsureDeleted(ctx context.Context, resource lifecycle.RuntimeObject) (ctrl.Result, lifecycle.ReconcileResult, error) {
+func (r *RemoteClusterReconciler) EnsureDeleted(ctx context.Context, resource lifecycle.RuntimeObject) (result ctrl.Result, reconcileResult lifecycle.ReconcileResult, err error) {
 	cluster := resource.(*greenhousev1alpha1.Cluster) //nolint:errcheck
+	// the metrics of a deleted cluster would otherwise keep alerts firing
+	defer func() {
+		if reconcileResult == lifecycle.Success {
+			deleteMetrics(cluster)
+		}
+	}()
+	if cluster.SkipsRemoteCleanup() {
+		// the plugins are deleted without uninstalling them, the TeamRoleBindings forget the cluster on their own
+		if _, err := deletePlugins(ctx, r.Client, cluster); err != nil {
+			return ctrl.Result{}, lifecycle.Failed, err
+		}
+		log.FromContext(ctx).Info("forgetting the resources deployed to the cluster", "cluster", cluster.Name)
+		return ctrl.Result{}, lifecycle.Success, nil
+	}
 	c := cluster.Status.StatusConditions.GetConditionByType(greenhousev1alpha1.KubeConfigValid)
 	if c != nil && c.IsFalse() {
 		return ctrl.Result{}, lifecycle.Success, nil
@@ -291,6 +343,22 @@ func (r *RemoteClusterReconciler) EnsureDeleted(ctx context.Context, resource li
 	if deletionCount > 0 {
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, lifecycle.Pending, nil
 	}
+	// wait for the TeamRoleBindings to remove their RBAC while Greenhouse can still access the cluster
+	if cluster.Status.IsReadyTrue() {
+		blocking, err := listTeamRoleBindingsPropagatedTo(ctx, r.Client, cluster)
+		if err != nil {
+			return ctrl.Result{}, lifecycle.Failed, err
+		}
+		setBlockingTeamRoleBindings(cluster, blocking)
+		if len(blocking) > 0 {
+			// TeamRoleBindings skipping the cluster due to its maintenance or failing to clean up must not block the deletion forever
+			if time.Since(cluster.DeletionTimestamp.Time) < teamRoleBindingCleanupTimeout {
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, lifecycle.Pending, nil
+			}
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent,
+				"TeamRoleBindings %s did not remove their RBAC within %s, deleting the cluster anyway", strings.Join(blocking, ", "), teamRoleBindingCleanupTimeout)
+		}
+	}
 
 	kubeConfigSecret := &corev1.Secret{}
 	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: cluster.GetNamespace(), Name: cluster.GetSecretName()}, kubeConfigSecret); err != nil {
@@ -352,6 +420,14 @@ func deletePlugins(ctx context.Context, c client.Client, cluster *greenhousev1al
 		return
 	}
 	for _, plugin := range pluginList.Items {
+		// without the finalizer the plugin is deleted without uninstalling it from the cluster
+		if cluster.SkipsRemoteCleanup() && controllerutil.ContainsFinalizer(&plugin, lifecycle.CommonCleanupFinalizer) {
+			base := plugin.DeepCopy()
+			controllerutil.RemoveFinalizer(&plugin, lifecycle.CommonCleanupFinalizer)
+			if err = c.Patch(ctx, &plugin, client.MergeFrom(base)); client.IgnoreNotFound(err) != nil {
+				return
+			}
+		}
 		if err = c.Delete(ctx, &plugin); client.IgnoreNotFound(err) != nil {
 			return
 		}
@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -119,5 +120,104 @@ var _ = Describe("KubeConfig controller", func() {
 				Expect(kubeVersion).
 					ToNot(BeNil(), "the kubernetes version should not be nil")
 			})
+
+		It("Should rotate the credentials when requested", func() {
+			By("Creating a secret with a valid kubeconfig for a remote cluster")
+			secret := setup.CreateSecret(test.Ctx, directAccessTestCase,
+				test.WithSecretType(greenhouseapis.SecretTypeKubeConfig),
+				test.WithSecretData(map[string][]byte{greenhouseapis.KubeConfigKey: remoteKubeConfig}))
+
+			By("Waiting for the greenhouse kubeconfig")
+			clusterSecret := &corev1.Secret{}
+			Eventually(func(g Gomega) {
+				g.Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(secret), clusterSecret)).To(Succeed(), "there should be no error getting the secret")
+				g.Expect(clusterSecret.Data).To(HaveKey(greenhouseapis.GreenHouseKubeConfigKey), "the secret should contain the greenhouse kubeconfig")
+			}).Should(Succeed(), "eventually the greenhouse kubeconfig should be generated")
+			previousKubeConfig := clusterSecret.Data[greenhouseapis.GreenHouseKubeConfigKey]
+
+			Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(secret), &cluster)).To(Succeed(), "there should be no error getting the cluster")
+			remoteClient, err := clientutil.NewK8sClientFromCluster(test.Ctx, test.K8sClient, &cluster)
+			Expect(err).ToNot(HaveOccurred(), "there should be no error creating a new k8s client from the cluster")
+			serviceAccount := &corev1.ServiceAccount{}
+			Eventually(func() error {
+				return remoteClient.Get(test.Ctx, types.NamespacedName{Namespace: setup.Namespace(), Name: clusterutils.ServiceAccountName}, serviceAccount)
+			}).Should(Succeed(), "eventually the service account should exist")
+			previousUID := serviceAccount.GetUID()
+
+			By("Requesting the rotation of the credentials")
+			_, err = clientutil.Patch(test.Ctx, test.K8sClient, &cluster, func() error {
+				metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, greenhouseapis.RotateCredentialsAnnotation, "now")
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error annotating the cluster")
+
+			By("Checking the credentials have been rotated")
+			Eventually(func(g Gomega) {
+				g.Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(secret), &cluster)).To(Succeed(), "there should be no error getting the cluster")
+				g.Expect(cluster.GetAnnotations()).ToNot(HaveKey(greenhouseapis.RotateCredentialsAnnotation), "the annotation should be removed")
+				condition := cluster.Status.GetConditionByType(greenhousev1alpha1.CredentialsRotated)
+				g.Expect(condition).ToNot(BeNil(), "the CredentialsRotated condition should be set")
+				g.Expect(condition.IsTrue()).To(BeTrue(), "the CredentialsRotated condition should be true")
+			}).Should(Succeed(), "eventually the credentials should be rotated")
+
+			Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(secret), clusterSecret)).To(Succeed(), "there should be no error getting the secret")
+			Expect(clusterSecret.Data[greenhouseapis.GreenHouseKubeConfigKey]).ToNot(Equal(previousKubeConfig), "the greenhouse kubeconfig should be replaced")
+
+			remoteClient, err = clientutil.NewK8sClientFromCluster(test.Ctx, test.K8sClient, &cluster)
+			Expect(err).ToNot(HaveOccurred(), "there should be no error creating a client with the new credentials")
+			Expect(remoteClient.Get(test.Ctx, types.NamespacedName{Namespace: setup.Namespace(), Name: clusterutils.ServiceAccountName}, serviceAccount)).
+				To(Succeed(), "the service account should be recreated")
+			Expect(serviceAccount.GetUID()).ToNot(Equal(previousUID), "the service account should be a new one")
+			Eventually(func() bool {
+				err := remoteClient.Get(test.Ctx, types.NamespacedName{Name: "greenhouse-rotation"}, &rbacv1.ClusterRoleBinding{})
+				return apierrors.IsNotFound(err)
+			}).Should(BeTrue(), "the temporary ClusterRoleBinding should be removed")
+		})
+
+		It("Should report the resources affected by a scheduled deletion", func() {
+			By("Creating a secret with a valid kubeconfig for a remote cluster")
+			secret := setup.CreateSecret(test.Ctx, directAccessTestCase,
+				test.WithSecretType(greenhouseapis.SecretTypeKubeConfig),
+				test.WithSecretData(map[string][]byte{greenhouseapis.KubeConfigKey: remoteKubeConfig}))
+			Eventually(func() error {
+				return test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(secret), &cluster)
+			}).Should(Succeed(), "eventually the cluster should exist")
+			Expect(cluster.Spec.DeletionPolicy).To(Equal(greenhousev1alpha1.ClusterDeletionPolicyUninstall), "the deletion policy should default to Uninstall")
+
+			By("Creating a Plugin and a TeamRoleBinding for the cluster")
+			plugin := setup.CreatePlugin(test.Ctx, "deletion-report", test.WithCluster(cluster.Name), func(p *greenhousev1alpha1.Plugin) {
+				p.SetLabels(map[string]string{greenhouseapis.LabelKeyCluster: cluster.Name})
+			})
+			trb := setup.CreateTeamRoleBinding(test.Ctx, "deletion-report", test.WithClusterName(cluster.Name))
+
+			By("Scheduling the deletion of the cluster")
+			_, err := clientutil.Patch(test.Ctx, test.K8sClient, &cluster, func() error {
+				metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, greenhouseapis.MarkClusterDeletionAnnotation, "true")
+				metav1.SetMetaDataAnnotation(&cluster.ObjectMeta, greenhouseapis.ScheduleClusterDeletionAnnotation, "2099-01-01 00:00:00")
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error scheduling the deletion")
+
+			Eventually(func(g Gomega) {
+				g.Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(secret), &cluster)).To(Succeed(), "there should be no error getting the cluster")
+				g.Expect(cluster.Status.DeletionReport).ToNot(BeNil(), "the deletion report should be set")
+				g.Expect(cluster.Status.DeletionReport.Plugins).To(ConsistOf(plugin.Name), "the report should list the Plugin")
+				g.Expect(cluster.Status.DeletionReport.TeamRoleBindings).To(ConsistOf(trb.Name), "the report should list the TeamRoleBinding")
+			}).Should(Succeed(), "eventually the deletion report should list the affected resources")
+
+			By("Cancelling the deletion of the cluster")
+			_, err = clientutil.Patch(test.Ctx, test.K8sClient, &cluster, func() error {
+				delete(cluster.Annotations, greenhouseapis.MarkClusterDeletionAnnotation)
+				delete(cluster.Annotations, greenhouseapis.ScheduleClusterDeletionAnnotation)
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error cancelling the deletion")
+			Eventually(func(g Gomega) {
+				g.Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(secret), &cluster)).To(Succeed(), "there should be no error getting the cluster")
+				g.Expect(cluster.Status.DeletionReport).To(BeNil(), "the deletion report should be removed")
+			}).Should(Succeed(), "eventually the deletion report should be removed")
+
+			Expect(test.K8sClient.Delete(test.Ctx, trb)).To(Succeed(), "there should be no error deleting the TeamRoleBinding")
+		})
 	})
 })
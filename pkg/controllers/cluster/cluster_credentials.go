@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+	"github.com/cloudoperators/greenhouse/pkg/controllers/cluster/utils"
+)
+
+const (
+	// rotationServiceAccountName is the temporary ServiceAccount used to recreate the greenhouse ServiceAccount in the remote cluster.
+	rotationServiceAccountName = "greenhouse-rotation"
+	// rotationTokenValidity is the validity of the token of the temporary ServiceAccount.
+	rotationTokenValidity = 10 * time.Minute
+)
+
+func isCredentialsRotationRequested(cluster *greenhousev1alpha1.Cluster) bool {
+	_, ok := cluster.GetAnnotations()[greenhouseapis.RotateCredentialsAnnotation]
+	return ok
+}
+
+// reconcileCredentialsRotation revokes the credentials of the cluster and issues new ones if requested by the rotate-credentials annotation.
+// The annotation is removed once the rotation succeeded, on failure the rotation is retried.
+// Clusters accessed via OIDC do not support the rotation, as the remote cluster accepts all tokens of the issuer until they expire.
+func (r *RemoteClusterReconciler) reconcileCredentialsRotation(
+	ctx context.Context,
+	cluster *greenhousev1alpha1.Cluster,
+	clusterSecret *corev1.Secret,
+	restClientGetter *clientutil.RestClientGetter,
+	remoteClient client.Client,
+	crb *rbacv1.ClusterRoleBinding,
+) error {
+
+	if !isCredentialsRotationRequested(cluster) {
+		// An interrupted rotation might have left the temporary ServiceAccount behind.
+		if clusterSecret.Type != greenhouseapis.SecretTypeOIDCConfig && isCredentialsRotationFailed(cluster) {
+			return deleteRotationServiceAccount(ctx, remoteClient)
+		}
+		return nil
+	}
+	if clusterSecret.Type == greenhouseapis.SecretTypeOIDCConfig {
+		cluster.Status.SetConditions(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.CredentialsRotated, greenhousev1alpha1.CredentialsRotationNotSupportedReason,
+			"the credentials of clusters accessed via OIDC cannot be revoked, tokens issued before remain valid until they expire"))
+		r.recorder.Event(cluster, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "Rotating the credentials of clusters accessed via OIDC is not supported")
+		return r.removeCredentialsRotationAnnotation(ctx, cluster)
+	}
+
+	log.FromContext(ctx).Info("rotating credentials", "cluster", cluster.Name)
+	tokenRequest, err := r.rotateServiceAccountCredentials(ctx, cluster, clusterSecret, restClientGetter, remoteClient, crb)
+	if err != nil {
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "Failed to rotate credentials: %s", err.Error())
+		cluster.Status.SetConditions(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.CredentialsRotated, greenhousev1alpha1.CredentialsRotationFailedReason, err.Error()))
+		return err
+	}
+	cluster.Status.BearerTokenExpirationTimestamp = tokenRequest.Status.ExpirationTimestamp
+	cluster.Status.SetConditions(greenhousev1alpha1.TrueCondition(greenhousev1alpha1.CredentialsRotated, "", "credentials rotated at "+time.Now().UTC().Format(time.DateTime)))
+	r.recorder.Event(cluster, corev1.EventTypeNormal, greenhousev1alpha1.CredentialsRotationEvent, "Rotated credentials")
+	return r.removeCredentialsRotationAnnotation(ctx, cluster)
+}
+
+func isCredentialsRotationFailed(cluster *greenhousev1alpha1.Cluster) bool {
+	condition := cluster.Status.GetConditionByType(greenhousev1alpha1.CredentialsRotated)
+	return condition != nil && condition.Reason == greenhousev1alpha1.CredentialsRotationFailedReason
+}
+
+// removeCredentialsRotationAnnotation removes the rotate-credentials annotation from the cluster.
+// A copy of the cluster is patched, so the status computed during this reconciliation is not overwritten by the response.
+func (r *RemoteClusterReconciler) removeCredentialsRotationAnnotation(ctx context.Context, cluster *greenhousev1alpha1.Cluster) error {
+	patched := cluster.DeepCopy()
+	delete(patched.Annotations, greenhouseapis.RotateCredentialsAnnotation)
+	if err := r.Patch(ctx, patched, client.MergeFrom(cluster)); err != nil {
+		return fmt.Errorf("failed to remove the %s annotation: %w", greenhouseapis.RotateCredentialsAnnotation, err)
+	}
+	delete(cluster.Annotations, greenhouseapis.RotateCredentialsAnnotation)
+	return nil
+}
+
+// deleteRotationServiceAccount deletes the temporary ClusterRoleBinding from the remote cluster, the temporary ServiceAccount is garbage collected with it.
+func deleteRotationServiceAccount(ctx context.Context, c client.Client) error {
+	rotationCRB := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: rotationServiceAccountName}}
+	if err := c.Delete(ctx, rotationCRB); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete the temporary ClusterRoleBinding: %w", err)
+	}
+	return nil
+}
+
+// rotateServiceAccountCredentials recreates the greenhouse ServiceAccount in the remote cluster, which invalidates all of its outstanding tokens.
+// As this also invalidates the token used by Greenhouse, the ServiceAccount is recreated with the token of a temporary ServiceAccount.
+// The temporary ServiceAccount is deleted on every exit, using the most recent credentials still valid.
+func (r *RemoteClusterReconciler) rotateServiceAccountCredentials(
+	ctx context.Context,
+	cluster *greenhousev1alpha1.Cluster,
+	clusterSecret *corev1.Secret,
+	restClientGetter *clientutil.RestClientGetter,
+	remoteClient client.Client,
+	crb *rbacv1.ClusterRoleBinding,
+) (*authenticationv1.TokenRequest, error) {
+
+	cleanupClient := remoteClient
+	defer func() {
+		if err := deleteRotationServiceAccount(ctx, cleanupClient); err != nil {
+			log.FromContext(ctx).Error(err, "failed to delete the temporary ServiceAccount", "cluster", cluster.Name)
+			return
+		}
+		r.recorder.Eventf(cluster, corev1.EventTypeNormal, greenhousev1alpha1.CredentialsRotationEvent, "Deleted temporary ServiceAccount %s/%s", cluster.GetNamespace(), rotationServiceAccountName)
+	}()
+
+	rotationCRB := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: rotationServiceAccountName}}
+	if _, err := clientutil.CreateOrPatch(ctx, remoteClient, rotationCRB, func() error {
+		rotationCRB.Subjects = []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: rotationServiceAccountName, Namespace: cluster.GetNamespace()}}
+		rotationCRB.RoleRef = rbacv1.RoleRef{Kind: utils.CRoleKind, Name: utils.CRoleRef, APIGroup: rbacv1.GroupName}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create the temporary ClusterRoleBinding: %w", err)
+	}
+	rotationSA := utils.NewServiceAccount(rotationServiceAccountName, cluster.GetNamespace())
+	if _, err := clientutil.CreateOrPatch(ctx, remoteClient, rotationSA, func() error {
+		// The temporary ServiceAccount is garbage collected with its ClusterRoleBinding.
+		return controllerutil.SetOwnerReference(rotationCRB, rotationSA, remoteClient.Scheme())
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create the temporary ServiceAccount: %w", err)
+	}
+	rotationToken := &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: ptr.To(int64(rotationTokenValidity / time.Second))}}
+	if err := remoteClient.SubResource("token").Create(ctx, rotationSA, rotationToken); err != nil {
+		return nil, fmt.Errorf("failed to request a token for the temporary ServiceAccount: %w", err)
+	}
+	rotationClient, err := newRemoteClientWithToken(restClientGetter, clusterSecret, cluster, rotationToken.Status.Token)
+	if err != nil {
+		return nil, err
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, greenhousev1alpha1.CredentialsRotationEvent, "Created temporary ServiceAccount %s/%s", cluster.GetNamespace(), rotationServiceAccountName)
+
+	serviceAccount := utils.NewServiceAccount(utils.ServiceAccountName, cluster.GetNamespace())
+	if err := rotationClient.Delete(ctx, serviceAccount); client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to delete the ServiceAccount: %w", err)
+	}
+	// The token of the remote client is revoked with the ServiceAccount.
+	cleanupClient = rotationClient
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, greenhousev1alpha1.CredentialsRotationEvent, "Deleted ServiceAccount %s/%s to revoke its tokens", cluster.GetNamespace(), utils.ServiceAccountName)
+	if err := r.reconcileServiceAccountInRemoteCluster(ctx, rotationClient, crb, cluster); err != nil {
+		return nil, fmt.Errorf("failed to recreate the ServiceAccount: %w", err)
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, greenhousev1alpha1.CredentialsRotationEvent, "Recreated ServiceAccount %s/%s", cluster.GetNamespace(), utils.ServiceAccountName)
+
+	t := &utils.TokenHelper{
+		RemoteClusterClient:              rotationClient,
+		RemoteClusterBearerTokenValidity: time.Duration(cluster.Spec.KubeConfig.MaxTokenValidity) * time.Hour,
+		SecretType:                       greenhouseapis.SecretTypeKubeConfig,
+	}
+	tokenRequest, err := t.RequestToken(ctx, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request a new token: %w", err)
+	}
+	if err := r.updateClusterSecretToken(ctx, restClientGetter, clusterSecret, cluster, tokenRequest.Status.Token); err != nil {
+		return nil, err
+	}
+	if newClient, err := newRemoteClientWithToken(restClientGetter, clusterSecret, cluster, tokenRequest.Status.Token); err == nil {
+		cleanupClient = newClient
+	}
+	return tokenRequest, nil
+}
+
+// updateClusterSecretToken writes the kubeconfig with the new token to the secret of the cluster.
+func (r *RemoteClusterReconciler) updateClusterSecretToken(
+	ctx context.Context,
+	restClientGetter *clientutil.RestClientGetter,
+	clusterSecret *corev1.Secret,
+	cluster *greenhousev1alpha1.Cluster,
+	token string,
+) error {
+
+	kubeconfig, err := utils.GenerateNewClientKubeConfig(restClientGetter, token, cluster)
+	if err != nil {
+		return err
+	}
+	_, err = clientutil.Patch(ctx, r.Client, clusterSecret, func() error {
+		if clusterSecret.Type == greenhouseapis.SecretTypeOIDCConfig {
+			metav1.SetMetaDataAnnotation(&clusterSecret.ObjectMeta, greenhouseapis.SecretOIDCConfigGeneratedOnAnnotation, metav1.Now().Format(time.DateTime))
+		}
+		clusterSecret.Data[greenhouseapis.GreenHouseKubeConfigKey] = kubeconfig
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the secret: %w", err)
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, greenhousev1alpha1.CredentialsRotationEvent, "Updated secret %s/%s with the new credentials", clusterSecret.GetNamespace(), clusterSecret.GetName())
+	return nil
+}
+
+// newRemoteClientWithToken returns a client for the remote cluster authenticating with the token.
+// The connection settings of the cluster secret, such as the proxy or the agent tunnel, are retained.
+func newRemoteClientWithToken(
+	restClientGetter *clientutil.RestClientGetter,
+	clusterSecret *corev1.Secret,
+	cluster *greenhousev1alpha1.Cluster,
+	token string,
+) (client.Client, error) {
+
+	kubeconfig, err := utils.GenerateNewClientKubeConfig(restClientGetter, token, cluster)
+	if err != nil {
+		return nil, err
+	}
+	secret := clusterSecret.DeepCopy()
+	secret.Data[greenhouseapis.GreenHouseKubeConfigKey] = kubeconfig
+	tokenRestClientGetter, err := clientutil.NewRestClientGetterFromSecret(secret, cluster.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	return clientutil.NewK8sClientFromRestClientGetter(tokenRestClientGetter)
+}
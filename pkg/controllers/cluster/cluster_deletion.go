@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+// teamRoleBindingCleanupTimeout is how long the deletion of a cluster waits for the TeamRoleBindings to remove their RBAC from it.
+const teamRoleBindingCleanupTimeout = 30 * time.Minute
+
+// reconcileDeletionReport lists the resources affected by the deletion of the cluster while it is scheduled or in progress.
+func (r *RemoteClusterReconciler) reconcileDeletionReport(ctx context.Context, cluster *greenhousev1alpha1.Cluster) error {
+	isScheduled, _, err := clientutil.ExtractDeletionSchedule(cluster.GetAnnotations())
+	if err != nil {
+		return err
+	}
+	if !isScheduled && cluster.DeletionTimestamp == nil {
+		cluster.Status.DeletionReport = nil
+		return nil
+	}
+
+	report := &greenhousev1alpha1.ClusterDeletionReport{DeletionPolicy: cluster.Spec.DeletionPolicy}
+	clusterLabels := labels.Set(cluster.GetLabels())
+
+	pluginList := new(greenhousev1alpha1.PluginList)
+	if err := r.List(ctx, pluginList, client.InNamespace(cluster.GetNamespace()), client.MatchingLabels{greenhouseapis.LabelKeyCluster: cluster.GetName()}); err != nil {
+		return err
+	}
+	for _, plugin := range pluginList.Items {
+		report.Plugins = append(report.Plugins, plugin.GetName())
+	}
+
+	pluginPresetList := new(greenhousev1alpha1.PluginPresetList)
+	if err := r.List(ctx, pluginPresetList, client.InNamespace(cluster.GetNamespace())); err != nil {
+		return err
+	}
+	for _, preset := range pluginPresetList.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&preset.Spec.ClusterSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(clusterLabels) {
+			report.PluginPresets = append(report.PluginPresets, preset.GetName())
+		}
+	}
+
+	teamRoleBindingList := new(greenhousev1alpha1.TeamRoleBindingList)
+	if err := r.List(ctx, teamRoleBindingList, client.InNamespace(cluster.GetNamespace())); err != nil {
+		return err
+	}
+	for _, trb := range teamRoleBindingList.Items {
+		if isTeamRoleBindingAppliedTo(&trb, cluster) {
+			report.TeamRoleBindings = append(report.TeamRoleBindings, trb.GetName())
+		}
+	}
+
+	slices.Sort(report.Plugins)
+	slices.Sort(report.PluginPresets)
+	slices.Sort(report.TeamRoleBindings)
+	cluster.Status.DeletionReport = report
+	return nil
+}
+
+// isTeamRoleBindingAppliedTo returns true if the TeamRoleBinding selects the cluster or has RBAC deployed to it.
+func isTeamRoleBindingAppliedTo(trb *greenhousev1alpha1.TeamRoleBinding, cluster *greenhousev1alpha1.Cluster) bool {
+	if isTeamRoleBindingPropagatedTo(trb, cluster) {
+		return true
+	}
+	if trb.Spec.ClusterName != "" {
+		return trb.Spec.ClusterName == cluster.GetName()
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&trb.Spec.ClusterSelector)
+	return err == nil && selector.Matches(labels.Set(cluster.GetLabels()))
+}
+
+// isTeamRoleBindingPropagatedTo returns true if the TeamRoleBinding reports RBAC deployed to the cluster.
+func isTeamRoleBindingPropagatedTo(trb *greenhousev1alpha1.TeamRoleBinding, cluster *greenhousev1alpha1.Cluster) bool {
+	return slices.ContainsFunc(trb.Status.PropagationStatus, func(ps greenhousev1alpha1.TeamRoleBindingPropagationStatus) bool {
+		return ps.ClusterName == cluster.GetName()
+	})
+}
+
+// listTeamRoleBindingsPropagatedTo returns the names of the TeamRoleBindings which did not yet remove their RBAC from the cluster.
+func listTeamRoleBindingsPropagatedTo(ctx context.Context, c client.Client, cluster *greenhousev1alpha1.Cluster) ([]string, error) {
+	teamRoleBindingList := new(greenhousev1alpha1.TeamRoleBindingList)
+	if err := c.List(ctx, teamRoleBindingList, client.InNamespace(cluster.GetNamespace())); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, trb := range teamRoleBindingList.Items {
+		if isTeamRoleBindingPropagatedTo(&trb, cluster) {
+			names = append(names, trb.GetName())
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// setBlockingTeamRoleBindings reports the TeamRoleBindings blocking the deletion of the cluster in its deletion report.
+func setBlockingTeamRoleBindings(cluster *greenhousev1alpha1.Cluster, names []string) {
+	if cluster.Status.DeletionReport == nil {
+		cluster.Status.DeletionReport = &greenhousev1alpha1.ClusterDeletionReport{DeletionPolicy: cluster.Spec.DeletionPolicy}
+	}
+	cluster.Status.DeletionReport.BlockingTeamRoleBindings = names
+}
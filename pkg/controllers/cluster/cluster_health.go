@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+// apiServerProbeTimeout is the timeout for a single health endpoint of the API server.
+const apiServerProbeTimeout = 10 * time.Second
+
+// apiServerHealthEndpoints are probed to determine the health of the API server.
+var apiServerHealthEndpoints = []string{"readyz", "livez"}
+
+// apiServerProbeResult is the result of probing a health endpoint of the API server.
+type apiServerProbeResult struct {
+	endpoint string
+	// reachable is true if the API server responded, regardless of the status code.
+	reachable bool
+	latency   time.Duration
+	// failedChecks contains the names of the failing checks reported by the API server.
+	failedChecks []string
+	err          error
+}
+
+// reconcileAPIServerHealth probes the health endpoints of the API server and returns the APIServerHealthy condition.
+// Latency and reachability are recorded as metrics.
+func (r *RemoteClusterReconciler) reconcileAPIServerHealth(
+	ctx context.Context,
+	cluster *greenhousev1alpha1.Cluster,
+	restClientGetter genericclioptions.RESTClientGetter,
+) greenhousev1alpha1.Condition {
+
+	condition := greenhousev1alpha1.UnknownCondition(greenhousev1alpha1.APIServerHealthy, "", "")
+	if restClientGetter == nil {
+		condition.Message = "kubeconfig not valid - cannot probe API server"
+		return condition
+	}
+	restConfig, err := restClientGetter.ToRESTConfig()
+	if err != nil {
+		condition.Message = err.Error()
+		return condition
+	}
+	serverURL, _, err := rest.DefaultServerUrlFor(restConfig)
+	if err != nil {
+		condition.Message = err.Error()
+		return condition
+	}
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		condition.Message = err.Error()
+		return condition
+	}
+
+	results := make([]apiServerProbeResult, 0, len(apiServerHealthEndpoints))
+	reachable := true
+	for _, endpoint := range apiServerHealthEndpoints {
+		result := probeAPIServer(ctx, httpClient, serverURL, endpoint)
+		if result.reachable {
+			updateAPIServerLatencyMetrics(cluster, endpoint, result.latency)
+		}
+		reachable = reachable && result.reachable
+		results = append(results, result)
+	}
+	updateAPIServerReachableMetrics(cluster, reachable)
+	return apiServerHealthCondition(results)
+}
+
+// probeAPIServer requests the verbose output of the health endpoint and extracts the failing checks.
+func probeAPIServer(ctx context.Context, httpClient *http.Client, serverURL *url.URL, endpoint string) apiServerProbeResult {
+	probeCtx, cancel := context.WithTimeout(ctx, apiServerProbeTimeout)
+	defer cancel()
+
+	result := apiServerProbeResult{endpoint: endpoint}
+	probeURL := serverURL.JoinPath(endpoint)
+	probeURL.RawQuery = "verbose"
+	req, err := http.NewRequestWithContext(probeCtx, http.MethodGet, probeURL.String(), http.NoBody)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	result.latency = time.Since(start)
+	result.reachable = true
+	switch {
+	case err != nil:
+		result.err = err
+	case resp.StatusCode != http.StatusOK:
+		result.err = fmt.Errorf("%s returned %s", endpoint, resp.Status)
+		result.failedChecks = parseFailedChecks(body)
+	}
+	return result
+}
+
+// parseFailedChecks returns the names of the checks reported as failed in the verbose output of a health endpoint, e.g. "[-]etcd failed: reason withheld".
+func parseFailedChecks(body []byte) []string {
+	var failed []string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "[-]")
+		if !ok {
+			continue
+		}
+		if name, _, found := strings.Cut(line, " "); found {
+			line = name
+		}
+		failed = append(failed, line)
+	}
+	return failed
+}
+
+// apiServerHealthCondition aggregates the probe results. An unreachable API server takes precedence over failing checks.
+func apiServerHealthCondition(results []apiServerProbeResult) greenhousev1alpha1.Condition {
+	var failing []string
+	for _, result := range results {
+		if !result.reachable {
+			return greenhousev1alpha1.FalseCondition(greenhousev1alpha1.APIServerHealthy, greenhousev1alpha1.APIServerUnreachableReason,
+				fmt.Sprintf("API server is unreachable: %s", result.err))
+		}
+		if result.err == nil {
+			continue
+		}
+		if len(result.failedChecks) == 0 {
+			failing = append(failing, fmt.Sprintf("%s: %s", result.endpoint, result.err))
+			continue
+		}
+		for _, check := range result.failedChecks {
+			failing = append(failing, result.endpoint+"/"+check)
+		}
+	}
+	if len(failing) > 0 {
+		return greenhousev1alpha1.FalseCondition(greenhousev1alpha1.APIServerHealthy, greenhousev1alpha1.APIServerChecksFailingReason,
+			"failing API server checks: "+strings.Join(failing, ", "))
+	}
+	return greenhousev1alpha1.TrueCondition(greenhousev1alpha1.APIServerHealthy, "", "")
+}
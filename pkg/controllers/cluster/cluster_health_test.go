@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/test"
+)
+
+const failingReadyz = `[+]ping ok
+[+]log ok
+[-]etcd failed: reason withheld
+[+]informer-sync ok
+[-]poststarthook/start-apiextensions-controllers failed: reason withheld
+readyz check failed
+`
+
+func mustParseURL(rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	Expect(err).ToNot(HaveOccurred(), "there should be no error parsing the URL")
+	return u
+}
+
+var _ = Describe("API server health", func() {
+	It("should report the failing checks of the API server", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/readyz" {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(failingReadyz))
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+		serverURL := mustParseURL(server.URL)
+
+		readyz := probeAPIServer(test.Ctx, server.Client(), serverURL, "readyz")
+		Expect(readyz.reachable).To(BeTrue(), "the API server should be reachable")
+		Expect(readyz.failedChecks).To(Equal([]string{"etcd", "poststarthook/start-apiextensions-controllers"}), "the failing checks should be parsed")
+		livez := probeAPIServer(test.Ctx, server.Client(), serverURL, "livez")
+		Expect(livez.err).ToNot(HaveOccurred(), "the livez check should succeed")
+
+		condition := apiServerHealthCondition([]apiServerProbeResult{readyz, livez})
+		Expect(condition.IsFalse()).To(BeTrue(), "the condition should be false")
+		Expect(condition.Reason).To(Equal(greenhousev1alpha1.APIServerChecksFailingReason), "the checks should be reported as failing")
+		Expect(condition.Message).To(ContainSubstring("readyz/etcd"), "the failing check should be named")
+	})
+
+	It("should report an unreachable API server", func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		host := server.URL
+		server.Close()
+
+		result := probeAPIServer(test.Ctx, http.DefaultClient, mustParseURL(host), "readyz")
+		Expect(result.reachable).To(BeFalse(), "the API server should not be reachable")
+
+		condition := apiServerHealthCondition([]apiServerProbeResult{result})
+		Expect(condition.IsFalse()).To(BeTrue(), "the condition should be false")
+		Expect(condition.Reason).To(Equal(greenhousev1alpha1.APIServerUnreachableReason), "the API server should be reported as unreachable")
+	})
+
+	It("should report a healthy API server", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+		serverURL := mustParseURL(server.URL)
+
+		condition := apiServerHealthCondition([]apiServerProbeResult{
+			probeAPIServer(test.Ctx, server.Client(), serverURL, "readyz"),
+			probeAPIServer(test.Ctx, server.Client(), serverURL, "livez"),
+		})
+		Expect(condition.IsTrue()).To(BeTrue(), "the condition should be true")
+	})
+})
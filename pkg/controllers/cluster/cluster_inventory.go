@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+const (
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+	nodeRoleNone        = "none"
+)
+
+// reconcileInventory returns the inventory of the cluster based on its nodes and installed CustomResourceDefinitions.
+func (r *RemoteClusterReconciler) reconcileInventory(
+	ctx context.Context,
+	restClientGetter genericclioptions.RESTClientGetter,
+	nodes []corev1.Node,
+) *greenhousev1alpha1.ClusterInventory {
+
+	inventory := inventoryFromNodes(nodes)
+	if restConfig, err := restClientGetter.ToRESTConfig(); err == nil {
+		inventory.APIServerEndpoint = restConfig.Host
+	}
+
+	remoteClient, err := clientutil.NewK8sClientFromRestClientGetter(restClientGetter)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to create client for cluster inventory")
+		return inventory
+	}
+	if inventory.CRDGroups, err = listCRDGroups(ctx, remoteClient); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list CustomResourceDefinitions for cluster inventory")
+	}
+	return inventory
+}
+
+// inventoryFromNodes summarizes the nodes of the cluster.
+func inventoryFromNodes(nodes []corev1.Node) *greenhousev1alpha1.ClusterInventory {
+	inventory := &greenhousev1alpha1.ClusterInventory{
+		NodeCount:                len(nodes),
+		NodesByRole:              make(map[string]int),
+		KubeletVersions:          make(map[string]int),
+		ContainerRuntimeVersions: make(map[string]int),
+		OSImages:                 make(map[string]int),
+	}
+	allocatableCPU, allocatableMemory := resource.Quantity{}, resource.Quantity{}
+	providers := make(map[string]int)
+	for _, node := range nodes {
+		hasRole := false
+		for label := range node.GetLabels() {
+			if role, ok := strings.CutPrefix(label, nodeRoleLabelPrefix); ok && role != "" {
+				inventory.NodesByRole[role]++
+				hasRole = true
+			}
+		}
+		if !hasRole {
+			inventory.NodesByRole[nodeRoleNone]++
+		}
+		if cpu, ok := node.Status.Allocatable[corev1.ResourceCPU]; ok {
+			allocatableCPU.Add(cpu)
+		}
+		if memory, ok := node.Status.Allocatable[corev1.ResourceMemory]; ok {
+			allocatableMemory.Add(memory)
+		}
+		countIfSet(inventory.KubeletVersions, node.Status.NodeInfo.KubeletVersion)
+		countIfSet(inventory.ContainerRuntimeVersions, node.Status.NodeInfo.ContainerRuntimeVersion)
+		countIfSet(inventory.OSImages, node.Status.NodeInfo.OSImage)
+		if provider, _, ok := strings.Cut(node.Spec.ProviderID, "://"); ok {
+			countIfSet(providers, provider)
+		}
+	}
+	inventory.AllocatableCPU = allocatableCPU
+	inventory.AllocatableMemory = allocatableMemory
+	inventory.Provider = mostCommon(providers)
+	return inventory
+}
+
+// listCRDGroups returns the sorted API groups of the CustomResourceDefinitions installed in the cluster.
+func listCRDGroups(ctx context.Context, c client.Client) ([]string, error) {
+	crdList := &metav1.PartialObjectMetadataList{}
+	crdList.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinitionList"))
+	if err := c.List(ctx, crdList); err != nil {
+		return nil, err
+	}
+	var groups []string
+	for _, crd := range crdList.Items {
+		// CustomResourceDefinitions are named <plural>.<group>
+		if _, group, ok := strings.Cut(crd.GetName(), "."); ok && !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	slices.Sort(groups)
+	return groups, nil
+}
+
+// reconcileInventoryLabels publishes well-known facts of the cluster inventory as labels on the cluster, so they can be used in label selectors.
+// Labels are kept while the facts are unknown, e.g. if the cluster is temporarily unreachable.
+func (r *RemoteClusterReconciler) reconcileInventoryLabels(ctx context.Context, cluster *greenhousev1alpha1.Cluster) error {
+	base := cluster.DeepCopy()
+	labels := cluster.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	changed := false
+	for key, value := range inventoryLabels(cluster) {
+		current, exists := labels[key]
+		switch {
+		case value == "" && exists:
+			delete(labels, key)
+		case value != "" && current != value:
+			labels[key] = value
+		default:
+			continue
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	cluster.SetLabels(labels)
+	if err := r.Patch(ctx, cluster, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed to label cluster with inventory: %w", err)
+	}
+	return nil
+}
+
+// inventoryLabels returns the labels for the known facts of the cluster status. Empty values remove the label.
+func inventoryLabels(cluster *greenhousev1alpha1.Cluster) map[string]string {
+	labels := make(map[string]string)
+	if v, err := version.ParseGeneric(cluster.Status.KubernetesVersion); err == nil {
+		labels[greenhouseapis.LabelKeyClusterK8sMinor] = fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+	}
+	if inventory := cluster.Status.Inventory; inventory != nil {
+		labels[greenhouseapis.LabelKeyClusterProvider] = ""
+		if len(validation.IsValidLabelValue(inventory.Provider)) == 0 {
+			labels[greenhouseapis.LabelKeyClusterProvider] = inventory.Provider
+		}
+	}
+	return labels
+}
+
+func countIfSet(counts map[string]int, key string) {
+	if key != "" {
+		counts[key]++
+	}
+}
+
+// mostCommon returns the key with the highest count. Ties are broken alphabetically.
+func mostCommon(counts map[string]int) string {
+	var result string
+	for key, count := range counts {
+		if count > counts[result] || (count == counts[result] && key < result) {
+			result = key
+		}
+	}
+	return result
+}
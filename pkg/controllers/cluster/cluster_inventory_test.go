@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+func inventoryTestNode(name, providerID, kubeletVersion string, roles ...string) corev1.Node {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}},
+		Spec:       corev1.NodeSpec{ProviderID: providerID},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+			NodeInfo: corev1.NodeSystemInfo{
+				KubeletVersion:          kubeletVersion,
+				ContainerRuntimeVersion: "containerd://1.7.0",
+				OSImage:                 "Garden Linux 1443.3",
+			},
+		},
+	}
+	for _, role := range roles {
+		node.Labels[nodeRoleLabelPrefix+role] = ""
+	}
+	return node
+}
+
+var _ = Describe("Cluster inventory", func() {
+	It("should summarize the nodes of the cluster", func() {
+		inventory := inventoryFromNodes([]corev1.Node{
+			inventoryTestNode("control-plane", "openstack:///a", "v1.31.2", "control-plane"),
+			inventoryTestNode("worker-1", "openstack:///b", "v1.31.2"),
+			inventoryTestNode("worker-2", "aws:///c", "v1.30.5"),
+		})
+
+		Expect(inventory.NodeCount).To(Equal(3), "all nodes should be counted")
+		Expect(inventory.NodesByRole).To(Equal(map[string]int{"control-plane": 1, nodeRoleNone: 2}), "nodes should be counted by role")
+		Expect(inventory.AllocatableCPU.String()).To(Equal("6"), "the allocatable CPU should be summed up")
+		Expect(inventory.AllocatableMemory.String()).To(Equal("12Gi"), "the allocatable memory should be summed up")
+		Expect(inventory.KubeletVersions).To(Equal(map[string]int{"v1.31.2": 2, "v1.30.5": 1}), "nodes should be counted by kubelet version")
+		Expect(inventory.ContainerRuntimeVersions).To(Equal(map[string]int{"containerd://1.7.0": 3}), "nodes should be counted by container runtime version")
+		Expect(inventory.OSImages).To(Equal(map[string]int{"Garden Linux 1443.3": 3}), "nodes should be counted by OS image")
+		Expect(inventory.Provider).To(Equal("openstack"), "the most common provider should be detected")
+	})
+
+	It("should derive the labels from the known facts of the cluster status", func() {
+		cluster := &greenhousev1alpha1.Cluster{}
+		cluster.Status.KubernetesVersion = clusterK8sVersionUnknown
+		Expect(inventoryLabels(cluster)).To(BeEmpty(), "no labels should be derived if nothing is known about the cluster")
+
+		cluster.Status.KubernetesVersion = "v1.31.2"
+		cluster.Status.Inventory = &greenhousev1alpha1.ClusterInventory{Provider: "aws"}
+		Expect(inventoryLabels(cluster)).To(Equal(map[string]string{
+			greenhouseapis.LabelKeyClusterK8sMinor: "1.31",
+			greenhouseapis.LabelKeyClusterProvider: "aws",
+		}), "the minor version and provider should be published")
+
+		cluster.Status.Inventory.Provider = ""
+		Expect(inventoryLabels(cluster)).To(HaveKeyWithValue(greenhouseapis.LabelKeyClusterProvider, ""), "the provider label should be removed if no provider is detected")
+	})
+})
@@ -15,13 +15,19 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
 	"github.com/cloudoperators/greenhouse/pkg/clientutil"
 	"github.com/cloudoperators/greenhouse/pkg/lifecycle"
+	"github.com/cloudoperators/greenhouse/pkg/tunnel"
 )
 
-const clusterK8sVersionUnknown = "unknown"
+const (
+	clusterK8sVersionUnknown = "unknown"
+	// agentPingTimeout is the timeout for the health check of the tunnel to a cluster agent.
+	agentPingTimeout = 5 * time.Second
+)
 
 func (r *RemoteClusterReconciler) setConditions() lifecycle.Conditioner {
 	return func(ctx context.Context, resource lifecycle.RuntimeObject) {
@@ -33,26 +39,46 @@ func (r *RemoteClusterReconciler) setConditions() lifecycle.Conditioner {
 		}
 		var conditions []greenhousev1alpha1.Condition
 
+		agentConnectedCondition := r.reconcileAgentStatus(ctx, cluster)
+
 		kubeConfigValidCondition, restClientGetter, k8sVersion := r.reconcileClusterSecret(ctx, cluster)
 
 		allNodesReadyCondition := greenhousev1alpha1.UnknownCondition(greenhousev1alpha1.AllNodesReady, "", "")
 		clusterNodeStatus := make(map[string]greenhousev1alpha1.NodeStatus)
-		// Can only reconcile node status if kubeconfig is valid
+		// Can only reconcile node status and inventory if kubeconfig is valid
 		if restClientGetter == nil || kubeConfigValidCondition.IsFalse() {
 			allNodesReadyCondition.Message = "kubeconfig not valid - cannot know node status"
 		} else {
-			allNodesReadyCondition, clusterNodeStatus = r.reconcileNodeStatus(ctx, restClientGetter)
+			var nodes []corev1.Node
+			allNodesReadyCondition, clusterNodeStatus, nodes = r.reconcileNodeStatus(ctx, restClientGetter)
+			if !allNodesReadyCondition.IsFalse() || len(nodes) > 0 {
+				cluster.Status.Inventory = r.reconcileInventory(ctx, restClientGetter, nodes)
+			}
 		}
 
-		// set ready condition if kubeconfig is valid and all nodes are ready
-		readyCondition := r.reconcileReadyStatus(kubeConfigValidCondition, allNodesReadyCondition)
+		apiServerHealthyCondition := r.reconcileAPIServerHealth(ctx, cluster, restClientGetter)
+
+		// set ready condition if the agent is connected, kubeconfig is valid, the API server is healthy and all nodes are ready
+		readyCondition := r.reconcileReadyStatus(agentConnectedCondition, kubeConfigValidCondition, apiServerHealthyCondition, allNodesReadyCondition)
 
-		conditions = append(conditions, readyCondition, allNodesReadyCondition, kubeConfigValidCondition)
+		conditions = append(conditions, readyCondition, allNodesReadyCondition, kubeConfigValidCondition, apiServerHealthyCondition)
+		if cluster.Spec.AccessMode == greenhousev1alpha1.ClusterAccessModeAgent {
+			conditions = append(conditions, agentConnectedCondition)
+		} else {
+			// Remove the agent condition if the cluster is no longer accessed via an agent
+			cluster.Status.StatusConditions.Conditions = slices.DeleteFunc(cluster.Status.StatusConditions.Conditions, func(condition greenhousev1alpha1.Condition) bool {
+				return condition.Type == greenhousev1alpha1.AgentConnected
+			})
+		}
 
 		deletionCondition := r.checkDeletionSchedule(logger, cluster)
 		if !deletionCondition.IsUnknown() {
 			conditions = append(conditions, deletionCondition)
 		}
+		if err := r.reconcileDeletionReport(ctx, cluster); err != nil {
+			logger.Error(err, "failed to reconcile the deletion report")
+		}
+		conditions = append(conditions, r.reconcilePluginsCompatibility(ctx, cluster, k8sVersion))
 		cluster.Status.KubernetesVersion = k8sVersion
 		cluster.Status.SetConditions(conditions...)
 		cluster.Status.Nodes = clusterNodeStatus
@@ -112,6 +138,34 @@ func (r *RemoteClusterReconciler) reconcileClusterSecret(
 	return
 }
 
+// reconcileAgentStatus returns the condition reflecting the health of the tunnel to the agent of a cluster with access mode agent.
+func (r *RemoteClusterReconciler) reconcileAgentStatus(ctx context.Context, cluster *greenhousev1alpha1.Cluster) greenhousev1alpha1.Condition {
+	agentConnectedCondition := greenhousev1alpha1.UnknownCondition(greenhousev1alpha1.AgentConnected, "", "")
+	if cluster.Spec.AccessMode != greenhousev1alpha1.ClusterAccessModeAgent {
+		return agentConnectedCondition
+	}
+	registry := tunnel.DefaultDialer.Registry
+	if registry == nil {
+		agentConnectedCondition.Message = "tunnel server is not running"
+		return agentConnectedCondition
+	}
+	key := client.ObjectKeyFromObject(cluster)
+	if _, connected := registry.ConnectedSince(key); !connected {
+		agentConnectedCondition.Status = metav1.ConditionFalse
+		agentConnectedCondition.Message = "agent is not connected"
+		return agentConnectedCondition
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, agentPingTimeout)
+	defer cancel()
+	if err := registry.Ping(pingCtx, key); err != nil {
+		agentConnectedCondition.Status = metav1.ConditionFalse
+		agentConnectedCondition.Message = "agent does not respond: " + err.Error()
+		return agentConnectedCondition
+	}
+	agentConnectedCondition.Status = metav1.ConditionTrue
+	return agentConnectedCondition
+}
+
 func (r *RemoteClusterReconciler) reconcileReadyStatus(conditions ...greenhousev1alpha1.Condition) (readyCondition greenhousev1alpha1.Condition) {
 	readyCondition = greenhousev1alpha1.UnknownCondition(greenhousev1alpha1.ReadyCondition, "", "")
 	for _, condition := range conditions {
@@ -135,6 +189,7 @@ func (r *RemoteClusterReconciler) reconcileNodeStatus(
 ) (
 	allNodesReadyCondition greenhousev1alpha1.Condition,
 	clusterNodeStatus map[string]greenhousev1alpha1.NodeStatus,
+	nodes []corev1.Node,
 ) {
 
 	clusterNodeStatus = make(map[string]greenhousev1alpha1.NodeStatus)
@@ -155,6 +210,7 @@ func (r *RemoteClusterReconciler) reconcileNodeStatus(
 		return
 	}
 
+	nodes = nodeList.Items
 	for _, node := range nodeList.Items {
 		greenhouseNodeStatusConditions := greenhousev1alpha1.StatusConditions{}
 		for _, condition := range node.Status.Conditions {
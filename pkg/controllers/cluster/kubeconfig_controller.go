@@ -87,16 +87,6 @@ func (r *KubeconfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 				UID:        cluster.UID,
 			},
 		}
-		kubeconfig.Spec.Kubeconfig.Contexts = []v1alpha1.ClusterKubeconfigContextItem{
-			{
-				Name: cluster.Name,
-				Context: v1alpha1.ClusterKubeconfigContext{
-					Cluster:   cluster.Name,
-					AuthInfo:  "oidc@" + cluster.Name,
-					Namespace: "default",
-				},
-			}}
-		kubeconfig.Spec.Kubeconfig.CurrentContext = cluster.Name
 	}
 
 	defer func() {
@@ -149,21 +139,8 @@ func (r *KubeconfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 					CertificateAuthorityData: clusterCfg.CertificateAuthorityData,
 				},
 			}}
-		kubeconfig.Spec.Kubeconfig.AuthInfo = []v1alpha1.ClusterKubeconfigAuthInfoItem{
-			{
-				Name: "oidc@" + cluster.Name,
-				AuthInfo: v1alpha1.ClusterKubeconfigAuthInfo{
-					AuthProvider: clientcmdapi.AuthProviderConfig{
-						Name: "oidc",
-						Config: map[string]string{
-							"client-id":      oidc.ClientID,
-							"client-secret":  oidc.ClientSecret,
-							"idp-issuer-url": oidc.IssuerURL,
-						},
-					},
-				},
-			},
-		}
+		kubeconfig.Spec.Kubeconfig.AuthInfo, kubeconfig.Spec.Kubeconfig.Contexts = kubeconfigAuthInfos(cluster.Name, oidc)
+		kubeconfig.Spec.Kubeconfig.CurrentContext = cluster.Name
 		return nil
 	})
 
@@ -178,6 +155,66 @@ type OIDCInfo struct {
 	ClientID     string
 	ClientSecret string
 	IssuerURL    string
+	Format       v1alpha1.KubeconfigFormat
+	Scopes       []string
+}
+
+// defaultKubeconfigScopes are requested by the kubelogin credential plugin if the organization does not configure any.
+var defaultKubeconfigScopes = []string{"email", "groups", "offline_access"}
+
+// kubeconfigAuthInfos returns the users and contexts of the kubeconfig for the format configured by the organization.
+// With both formats the auth provider is used by the context named after the cluster and the credential plugin by the additional exec context.
+func kubeconfigAuthInfos(clusterName string, oidc OIDCInfo) ([]v1alpha1.ClusterKubeconfigAuthInfoItem, []v1alpha1.ClusterKubeconfigContextItem) {
+	authProvider := &clientcmdapi.AuthProviderConfig{
+		Name: "oidc",
+		Config: map[string]string{
+			"client-id":      oidc.ClientID,
+			"client-secret":  oidc.ClientSecret,
+			"idp-issuer-url": oidc.IssuerURL,
+		},
+	}
+	scopes := oidc.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultKubeconfigScopes
+	}
+	args := []string{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=" + oidc.IssuerURL,
+		"--oidc-client-id=" + oidc.ClientID,
+		"--oidc-client-secret=" + oidc.ClientSecret,
+	}
+	for _, scope := range scopes {
+		args = append(args, "--oidc-extra-scope="+scope)
+	}
+	exec := &v1alpha1.ClusterKubeconfigExecConfig{
+		APIVersion:      "client.authentication.k8s.io/v1beta1",
+		Command:         "kubectl",
+		Args:            args,
+		InstallHint:     "kubelogin is required to authenticate, see https://github.com/int128/kubelogin",
+		InteractiveMode: string(clientcmdapi.IfAvailableExecInteractiveMode),
+	}
+	context := func(name, authInfo string) v1alpha1.ClusterKubeconfigContextItem {
+		return v1alpha1.ClusterKubeconfigContextItem{
+			Name:    name,
+			Context: v1alpha1.ClusterKubeconfigContext{Cluster: clusterName, AuthInfo: authInfo, Namespace: "default"},
+		}
+	}
+
+	switch oidc.Format {
+	case v1alpha1.KubeconfigFormatExec:
+		return []v1alpha1.ClusterKubeconfigAuthInfoItem{{Name: "oidc@" + clusterName, AuthInfo: v1alpha1.ClusterKubeconfigAuthInfo{Exec: exec}}},
+			[]v1alpha1.ClusterKubeconfigContextItem{context(clusterName, "oidc@"+clusterName)}
+	case v1alpha1.KubeconfigFormatBoth:
+		return []v1alpha1.ClusterKubeconfigAuthInfoItem{
+				{Name: "oidc@" + clusterName, AuthInfo: v1alpha1.ClusterKubeconfigAuthInfo{AuthProvider: authProvider}},
+				{Name: "oidc-exec@" + clusterName, AuthInfo: v1alpha1.ClusterKubeconfigAuthInfo{Exec: exec}},
+			},
+			[]v1alpha1.ClusterKubeconfigContextItem{context(clusterName, "oidc@"+clusterName), context(clusterName+"-exec", "oidc-exec@"+clusterName)}
+	default:
+		return []v1alpha1.ClusterKubeconfigAuthInfoItem{{Name: "oidc@" + clusterName, AuthInfo: v1alpha1.ClusterKubeconfigAuthInfo{AuthProvider: authProvider}}},
+			[]v1alpha1.ClusterKubeconfigContextItem{context(clusterName, "oidc@"+clusterName)}
+	}
 }
 
 func (r *KubeconfigReconciler) getOIDCInfo(ctx context.Context, orgName string) (OIDCInfo, error) {
@@ -215,6 +252,8 @@ func (r *KubeconfigReconciler) getOIDCInfo(ctx context.Context, orgName string)
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		IssuerURL:    org.Spec.Authentication.OIDCConfig.Issuer,
+		Format:       org.Spec.Authentication.OIDCConfig.KubeconfigFormat,
+		Scopes:       org.Spec.Authentication.OIDCConfig.KubeconfigScopes,
 	}
 	return oidc, nil
 }
@@ -206,6 +206,40 @@ users:
 
 	})
 
+	It("should provide exec credentials in the format selected by the organization", func() {
+		By("Selecting both formats for the migration")
+		organization := v1alpha1.Organization{}
+		Expect(test.K8sClient.Get(test.Ctx, types.NamespacedName{Name: setup.Namespace()}, &organization)).To(Succeed())
+		organization.Spec.Authentication.OIDCConfig.KubeconfigFormat = v1alpha1.KubeconfigFormatBoth
+		organization.Spec.Authentication.OIDCConfig.KubeconfigScopes = []string{"groups"}
+		Expect(test.K8sClient.Update(test.Ctx, &organization)).To(Succeed())
+
+		clusterKubeconfig := v1alpha1.ClusterKubeconfig{}
+		Eventually(func(g Gomega) {
+			g.Expect(test.K8sClient.Get(test.Ctx, types.NamespacedName{Name: cluster.Name, Namespace: setup.Namespace()}, &clusterKubeconfig)).To(Succeed(), "There should be no error getting the ClusterKubeconfig resource")
+			g.Expect(clusterKubeconfig.Spec.Kubeconfig.AuthInfo).To(HaveLen(2), "there should be a user for each format")
+			g.Expect(clusterKubeconfig.Spec.Kubeconfig.Contexts).To(HaveLen(2), "there should be a context for each format")
+		}).Should(Succeed(), "eventually the ClusterKubeconfig should provide both formats")
+		Expect(clusterKubeconfig.Spec.Kubeconfig.AuthInfo[0].AuthInfo.AuthProvider).ToNot(BeNil(), "the first user should use the auth provider")
+		exec := clusterKubeconfig.Spec.Kubeconfig.AuthInfo[1].AuthInfo.Exec
+		Expect(exec).ToNot(BeNil(), "the second user should use the credential plugin")
+		Expect(exec.Args).To(ContainElements("--oidc-issuer-url=new-issuer-url", "--oidc-client-id=new-client-id", "--oidc-extra-scope=groups"),
+			"the credential plugin should use the OIDC config of the organization")
+		Expect(clusterKubeconfig.Spec.Kubeconfig.Contexts[1].Name).To(Equal(cluster.Name+"-exec"), "the exec context should be named after the cluster")
+		Expect(clusterKubeconfig.Spec.Kubeconfig.CurrentContext).To(Equal(cluster.Name), "the current context should remain unchanged")
+
+		By("Selecting the exec format")
+		Expect(test.K8sClient.Get(test.Ctx, types.NamespacedName{Name: setup.Namespace()}, &organization)).To(Succeed())
+		organization.Spec.Authentication.OIDCConfig.KubeconfigFormat = v1alpha1.KubeconfigFormatExec
+		Expect(test.K8sClient.Update(test.Ctx, &organization)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(test.K8sClient.Get(test.Ctx, types.NamespacedName{Name: cluster.Name, Namespace: setup.Namespace()}, &clusterKubeconfig)).To(Succeed(), "There should be no error getting the ClusterKubeconfig resource")
+			g.Expect(clusterKubeconfig.Spec.Kubeconfig.AuthInfo).To(HaveLen(1), "there should be a single user")
+			g.Expect(clusterKubeconfig.Spec.Kubeconfig.AuthInfo[0].AuthInfo.AuthProvider).To(BeNil(), "the auth provider should be removed")
+			g.Expect(clusterKubeconfig.Spec.Kubeconfig.AuthInfo[0].AuthInfo.Exec).ToNot(BeNil(), "the user should use the credential plugin")
+		}).Should(Succeed(), "eventually the ClusterKubeconfig should only provide the exec format")
+	})
+
 	It("should fail with ClusterKubeconfig when organization OIDC data is not found", func() {
 		organization := v1alpha1.Organization{}
 		Expect(test.K8sClient.Get(test.Ctx, types.NamespacedName{Name: setup.Namespace(), Namespace: setup.Namespace()}, &organization)).To(Succeed())
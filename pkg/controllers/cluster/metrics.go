@@ -24,11 +24,28 @@ var (
 			Name: "greenhouse_cluster_kubeconfig_validity_seconds",
 		},
 		[]string{"cluster", "namespace"})
+
+	apiServerLatencyHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "greenhouse_cluster_api_latency_seconds",
+			Help:    "Latency of the health endpoints of the API server of a cluster",
+			Buckets: []float64{0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"cluster", "namespace", "endpoint"})
+
+	apiServerReachableGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "greenhouse_cluster_api_reachable",
+			Help: "Whether the API server of a cluster responds to health checks",
+		},
+		[]string{"cluster", "namespace"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(kubernetesVersionsGauge)
 	metrics.Registry.MustRegister(secondsToTokenExpiryGauge)
+	metrics.Registry.MustRegister(apiServerLatencyHistogram)
+	metrics.Registry.MustRegister(apiServerReachableGauge)
 }
 
 func updateMetrics(cluster *greenhousev1alpha1.Cluster) {
@@ -46,3 +63,34 @@ func updateMetrics(cluster *greenhousev1alpha1.Cluster) {
 	}
 	secondsToTokenExpiryGauge.With(secondsToExpiryLabels).Set(float64(secondsToExpiry))
 }
+
+// deleteMetrics removes all series of the cluster.
+func deleteMetrics(cluster *greenhousev1alpha1.Cluster) {
+	clusterLabels := prometheus.Labels{
+		"cluster":   cluster.Name,
+		"namespace": cluster.Namespace,
+	}
+	kubernetesVersionsGauge.DeletePartialMatch(clusterLabels)
+	secondsToTokenExpiryGauge.DeleteLabelValues(cluster.Name, cluster.Namespace)
+	apiServerLatencyHistogram.DeletePartialMatch(clusterLabels)
+	apiServerReachableGauge.DeleteLabelValues(cluster.Name, cluster.Namespace)
+}
+
+func updateAPIServerLatencyMetrics(cluster *greenhousev1alpha1.Cluster, endpoint string, latency time.Duration) {
+	apiServerLatencyHistogram.With(prometheus.Labels{
+		"cluster":   cluster.Name,
+		"namespace": cluster.Namespace,
+		"endpoint":  endpoint,
+	}).Observe(latency.Seconds())
+}
+
+func updateAPIServerReachableMetrics(cluster *greenhousev1alpha1.Cluster, reachable bool) {
+	value := float64(0)
+	if reachable {
+		value = 1
+	}
+	apiServerReachableGauge.With(prometheus.Labels{
+		"cluster":   cluster.Name,
+		"namespace": cluster.Namespace,
+	}).Set(value)
+}
@@ -50,4 +50,26 @@ var _ = Describe("Metrics controller", Ordered, func() {
 		Expect(tokenExpiry).To(BeNumerically(">=", 595))
 		Expect(tokenExpiry).To(BeNumerically("<=", 600))
 	})
+
+	It("Should delete the metrics of a deleted cluster", func() {
+		cluster := &greenhousev1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster-b",
+				Namespace: "test-org",
+			},
+			Status: greenhousev1alpha1.ClusterStatus{
+				KubernetesVersion:              "1.31.1",
+				BearerTokenExpirationTimestamp: metav1.Time{Time: time.Now().Add(600 * time.Second)},
+			},
+		}
+		updateMetrics(cluster)
+		updateAPIServerReachableMetrics(cluster, false)
+		updateAPIServerLatencyMetrics(cluster, "livez", time.Second)
+
+		deleteMetrics(cluster)
+		Expect(kubernetesVersionsGauge.DeleteLabelValues(cluster.Name, cluster.Namespace, cluster.Status.KubernetesVersion)).To(BeFalse(), "the kubernetes version series should be deleted")
+		Expect(secondsToTokenExpiryGauge.DeleteLabelValues(cluster.Name, cluster.Namespace)).To(BeFalse(), "the token expiry series should be deleted")
+		Expect(apiServerLatencyHistogram.DeleteLabelValues(cluster.Name, cluster.Namespace, "livez")).To(BeFalse(), "the latency series should be deleted")
+		Expect(apiServerReachableGauge.DeleteLabelValues(cluster.Name, cluster.Namespace)).To(BeFalse(), "the reachable series should be deleted")
+	})
 })
@@ -185,6 +185,9 @@ var _ = Describe("Cluster status", Ordered, func() {
 			g.Expect(validCluster.Status.Nodes).ToNot(BeEmpty())
 			g.Expect(validCluster.Status.Nodes["test-node"].Conditions).ToNot(BeEmpty())
 			g.Expect(validCluster.Status.Nodes["test-node"].Ready).To(BeTrue())
+			g.Expect(validCluster.Status.Inventory).ToNot(BeNil(), "The inventory should be present")
+			g.Expect(validCluster.Status.Inventory.NodeCount).To(Equal(3), "The inventory should count the remote nodes")
+			g.Expect(validCluster.Status.Inventory.APIServerEndpoint).ToNot(BeEmpty(), "The inventory should contain the API server endpoint")
 			return true
 		}).Should(BeTrue())
 
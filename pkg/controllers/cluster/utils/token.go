@@ -120,7 +120,12 @@ func (t *TokenHelper) GenerateTokenRequest(ctx context.Context, restClientGetter
 		return nil, nil
 	}
 
-	tokenRequest := &authenticationv1.TokenRequest{
+	return t.RequestToken(ctx, cluster)
+}
+
+// RequestToken requests a new service account token for the remote cluster regardless of the expiry of the current token
+func (t *TokenHelper) RequestToken(ctx context.Context, cluster *greenhousev1alpha1.Cluster) (tokenRequest *authenticationv1.TokenRequest, err error) {
+	tokenRequest = &authenticationv1.TokenRequest{
 		Spec: authenticationv1.TokenRequestSpec{
 			ExpirationSeconds: ptr.To(int64(t.RemoteClusterBearerTokenValidity / time.Second)),
 		},
@@ -168,11 +173,12 @@ func GenerateNewClientKubeConfig(restConfigGetter *clientutil.RestClientGetter,
 	}
 	// TODO: replace overwrite with https://github.com/kubernetes/kubernetes/pull/119398 after 1.30 upgrade
 	kubeConfigGenerator := &KubeConfigHelper{
-		Host:        restConfig.Host,
-		CAData:      restConfig.CAData,
-		BearerToken: bearerToken,
-		Username:    ServiceAccountName,
-		Namespace:   cluster.GetNamespace(),
+		Host:          restConfig.Host,
+		CAData:        restConfig.CAData,
+		BearerToken:   bearerToken,
+		Username:      ServiceAccountName,
+		Namespace:     cluster.GetNamespace(),
+		TLSServerName: restConfig.ServerName,
 	}
 	kubeconfigByte, err := clientcmd.Write(kubeConfigGenerator.RestConfigToAPIConfig(cluster.Name))
 	if err != nil {
@@ -120,6 +120,23 @@ func (r *PluginReconciler) setConditions() lifecycle.Conditioner {
 func (r *PluginReconciler) EnsureDeleted(ctx context.Context, resource lifecycle.RuntimeObject) (ctrl.Result, lifecycle.ReconcileResult, error) {
 	plugin := resource.(*greenhousev1alpha1.Plugin) //nolint:errcheck
 
+	// Uninstalling the release is postponed until the maintenance of the cluster ends.
+	if plugin.Spec.ClusterName != "" {
+		cluster := new(greenhousev1alpha1.Cluster)
+		err := r.Get(ctx, types.NamespacedName{Namespace: plugin.GetNamespace(), Name: plugin.Spec.ClusterName}, cluster)
+		switch {
+		case apierrors.IsNotFound(err):
+			// A deleted cluster is not in maintenance.
+		case err != nil:
+			return ctrl.Result{}, lifecycle.Failed, err
+		default:
+			if inMaintenance, requeueAfter := skipClusterInMaintenance(plugin, cluster); inMaintenance {
+				log.FromContext(ctx).Info("cluster is in maintenance, postponing the uninstallation", "cluster", cluster.GetName())
+				return ctrl.Result{RequeueAfter: requeueAfter}, lifecycle.Pending, nil
+			}
+		}
+	}
+
 	restClientGetter, err := initClientGetter(ctx, r.Client, r.kubeClientOpts, *plugin)
 	if err != nil {
 		metrics.UpdateMetrics(plugin, metrics.MetricResultError, metrics.MetricReasonClusterAccessFailed)
@@ -230,10 +247,10 @@ func (r *PluginReconciler) reconcileHelmRelease(
 	pluginDefinition *greenhousev1alpha1.PluginDefinition,
 ) error {
 
-	// Not a HelmChart pluginDefinition. Ignore it.
-	if pluginDefinition.Spec.HelmChart == nil {
+	// Neither a HelmChart nor a Manifests pluginDefinition. Ignore it.
+	if pluginDefinition.Spec.ChartReference() == nil {
 		plugin.SetCondition(greenhousev1alpha1.FalseCondition(
-			greenhousev1alpha1.HelmReconcileFailedCondition, "", "PluginDefinition is not backed by HelmChart"))
+			greenhousev1alpha1.HelmReconcileFailedCondition, "", "PluginDefinition is not backed by HelmChart or Manifests"))
 		return nil
 	}
 
@@ -320,6 +337,9 @@ func (r *PluginReconciler) reconcileStatus(ctx context.Context,
 				greenhousev1alpha1.StatusUpToDateCondition, "", "failed to get exposed services: "+err.Error()))
 		}
 
+		releaseStatus.AppVersion = helm.AppVersionFromRelease(helmRelease)
+		releaseStatus.Objects = helm.ObjectCountsFromRelease(helmRelease)
+
 		// Get the release status.
 		if latestReleaseInfo := helmRelease.Info; latestReleaseInfo != nil {
 			releaseStatus.Status = latestReleaseInfo.Status.String()
@@ -328,6 +348,7 @@ func (r *PluginReconciler) reconcileStatus(ctx context.Context,
 			if latestReleaseInfo.Status == release.StatusDeployed {
 				pluginVersion = latestReleaseInfo.Description
 			}
+			releaseStatus.Notes = helm.NotesFromRelease(helmRelease)
 			if plugin.Spec.OptionValues != nil {
 				checksum, err := helm.CalculatePluginOptionChecksum(ctx, r.Client, plugin)
 				if err == nil {
@@ -344,16 +365,24 @@ func (r *PluginReconciler) reconcileStatus(ctx context.Context,
 
 	var (
 		uiApplication      *greenhousev1alpha1.UIApplicationReference
-		helmChartReference *greenhousev1alpha1.HelmChartReference
+		helmChartReference *greenhousev1alpha1.PluginHelmChartStatus
 	)
 	// Ensure the status is always reported.
 	uiApplication = pluginDefinition.Spec.UIApplication
 	// only set the helm chart reference if the pluginVersion matches the pluginDefinition version or the release status is unknown
 	if pluginVersion == pluginDefinition.Spec.Version || releaseStatus.Status == "unknown" {
-		helmChartReference = pluginDefinition.Spec.HelmChart
+		if chartReference := pluginDefinition.Spec.ChartReference(); chartReference != nil {
+			helmChartReference = &greenhousev1alpha1.PluginHelmChartStatus{HelmChartReference: *chartReference.DeepCopy()}
+		}
 	} else {
 		helmChartReference = plugin.Status.HelmChart
 	}
+	// Report the commit a chart sourced from Git was deployed from.
+	if helmChartReference != nil && helmChartReference.Git != nil && helmRelease != nil {
+		if commit := helm.GitCommitFromRelease(helmRelease); commit != "" {
+			helmChartReference.ResolvedCommit = commit
+		}
+	}
 
 	pluginStatus.HelmReleaseStatus = releaseStatus
 	pluginStatus.Version = pluginVersion
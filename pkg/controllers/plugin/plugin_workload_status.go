@@ -94,7 +94,7 @@ func (r *PluginReconciler) reconcilePluginWorkloadStatus(
 	if reflect.DeepEqual(plugin.Status, greenhousev1alpha1.PluginStatus{}) || plugin.Status.HelmChart == nil {
 		return nil, nil
 	}
-	if pluginDefinition.Spec.HelmChart == nil {
+	if pluginDefinition.Spec.ChartReference() == nil {
 		return nil, nil
 	}
 
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"slices"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+	"github.com/cloudoperators/greenhouse/pkg/helm"
+)
+
+// PluginDefinitionReconciler reflects the compatibility of the PluginDefinition with the Kubernetes versions of all clusters.
+type PluginDefinitionReconciler struct {
+	client.Client
+}
+
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=plugindefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=plugindefinitions/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=clusters,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PluginDefinitionReconciler) SetupWithManager(name string, mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&greenhousev1alpha1.PluginDefinition{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The compatibility changes with the Kubernetes version of any cluster.
+		Watches(&greenhousev1alpha1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllPluginDefinitions),
+			builder.WithPredicates(clientutil.PredicateClusterKubernetesVersionChange())).
+		Complete(r)
+}
+
+func (r *PluginDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pluginDefinition := new(greenhousev1alpha1.PluginDefinition)
+	if err := r.Get(ctx, req.NamespacedName, pluginDefinition); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	kubeVersion, err := helm.KubeVersionConstraintForPluginDefinition(ctx, r.Client, pluginDefinition)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var incompatibleClusters []string
+	if kubeVersion != "" {
+		clusters := new(greenhousev1alpha1.ClusterList)
+		if err := r.List(ctx, clusters); err != nil {
+			return ctrl.Result{}, err
+		}
+		for _, cluster := range clusters.Items {
+			if !clientutil.IsKubeVersionCompatible(kubeVersion, cluster.Status.KubernetesVersion) {
+				incompatibleClusters = append(incompatibleClusters, cluster.GetNamespace()+"/"+cluster.GetName())
+			}
+		}
+		slices.Sort(incompatibleClusters)
+	}
+
+	_, err = clientutil.PatchStatus(ctx, r.Client, pluginDefinition, func() error {
+		pluginDefinition.Status.KubeVersion = kubeVersion
+		pluginDefinition.Status.IncompatibleClusters = incompatibleClusters
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(incompatibleClusters) > 0 {
+		log.FromContext(ctx).Info("clusters are incompatible with the PluginDefinition", "kubeVersion", kubeVersion, "clusters", incompatibleClusters)
+	}
+	return ctrl.Result{}, nil
+}
+
+// enqueueAllPluginDefinitions returns a list of reconcile requests for all PluginDefinitions.
+func (r *PluginDefinitionReconciler) enqueueAllPluginDefinitions(ctx context.Context, _ client.Object) []ctrl.Request {
+	pluginDefinitions := new(greenhousev1alpha1.PluginDefinitionList)
+	if err := r.List(ctx, pluginDefinitions); err != nil {
+		return nil
+	}
+	requests := make([]ctrl.Request, len(pluginDefinitions.Items))
+	for i, pluginDefinition := range pluginDefinitions.Items {
+		requests[i] = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&pluginDefinition)}
+	}
+	return requests
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -46,7 +47,8 @@ type PluginPresetReconciler struct {
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=pluginpresets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=pluginpresets/finalizers,verbs=update
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=plugins,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=greenhouse.sap,resources=plugindefinitions,verbs=get
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=plugins/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=plugindefinitions,verbs=get;list;watch
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *PluginPresetReconciler) SetupWithManager(name string, mgr ctrl.Manager) error {
@@ -62,7 +64,9 @@ func (r *PluginPresetReconciler) SetupWithManager(name string, mgr ctrl.Manager)
 			))).
 		// Clusters and teams are passed as values to each Helm operation. Reconcile on change.
 		Watches(&greenhousev1alpha1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllPluginPresetsInNamespace),
-			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.GenerationChangedPredicate{}, clientutil.PredicateClusterKubernetesVersionChange()))).
+		// The kubeVersion constraint of the PluginDefinition decides which clusters are compatible.
+		Watches(&greenhousev1alpha1.PluginDefinition{}, handler.EnqueueRequestsFromMapFunc(r.enqueuePluginPresetsForPluginDefinition)).
 		Complete(r)
 }
 
@@ -103,7 +107,9 @@ func (r *PluginPresetReconciler) EnsureCreated(ctx context.Context, resource lif
 		pluginPreset.SetCondition(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ClusterListEmpty, "", ""))
 	}
 
-	err = r.reconcilePluginPreset(ctx, pluginPreset, clusters)
+	maintenance := clientutil.NewMaintenanceTracker(time.Now())
+	err = r.reconcilePluginPreset(ctx, pluginPreset, clusters, maintenance)
+	maintenance.SetCondition(pluginPreset)
 	if err != nil {
 		return ctrl.Result{}, lifecycle.Failed, err
 	}
@@ -113,7 +119,8 @@ func (r *PluginPresetReconciler) EnsureCreated(ctx context.Context, resource lif
 		return ctrl.Result{}, lifecycle.Failed, err
 	}
 
-	return ctrl.Result{}, lifecycle.Success, nil
+	// Reconcile the Plugins of clusters in maintenance once their maintenance window ends.
+	return ctrl.Result{RequeueAfter: maintenance.RequeueAfter()}, lifecycle.Success, nil
 }
 
 func (r *PluginPresetReconciler) EnsureDeleted(ctx context.Context, resource lifecycle.RuntimeObject) (ctrl.Result, lifecycle.ReconcileResult, error) {
@@ -140,8 +147,8 @@ func (r *PluginPresetReconciler) EnsureDeleted(ctx context.Context, resource lif
 }
 
 // reconcilePluginPreset reconciles the PluginPreset by creating or updating the Plugins for the given clusters.
-// It skips reconciliation for Plugins that do not have the labels of the PluginPreset.
-func (r *PluginPresetReconciler) reconcilePluginPreset(ctx context.Context, preset *greenhousev1alpha1.PluginPreset, clusters *greenhousev1alpha1.ClusterList) error {
+// It skips reconciliation for Plugins that do not have the labels of the PluginPreset and for clusters in maintenance.
+func (r *PluginPresetReconciler) reconcilePluginPreset(ctx context.Context, preset *greenhousev1alpha1.PluginPreset, clusters *greenhousev1alpha1.ClusterList, maintenance *clientutil.MaintenanceTracker) error {
 	var allErrs = make([]error, 0)
 	var skippedPlugins = make([]string, 0)
 	var failedPlugins = make([]string, 0)
@@ -153,6 +160,7 @@ func (r *PluginPresetReconciler) reconcilePluginPreset(ctx context.Context, pres
 		return utilerrors.NewAggregate(allErrs)
 	}
 
+	var incompatibleClusters []string
 	for _, cluster := range clusters.Items {
 		plugin := &greenhousev1alpha1.Plugin{}
 		err := r.Get(ctx, client.ObjectKey{Namespace: preset.GetNamespace(), Name: generatePluginName(preset, &cluster)}, plugin)
@@ -160,6 +168,20 @@ func (r *PluginPresetReconciler) reconcilePluginPreset(ctx context.Context, pres
 		switch {
 		case !cluster.DeletionTimestamp.IsZero():
 			continue
+		case maintenance.Skip(&cluster):
+			continue
+		case !clientutil.IsKubeVersionCompatible(pluginDefinition.Status.KubeVersion, cluster.Status.KubernetesVersion):
+			// Plugins for incompatible clusters would fail to install or upgrade. Existing Plugins are kept, but report the incompatibility.
+			incompatibleClusters = append(incompatibleClusters, cluster.GetName())
+			if err == nil && isPluginManagedByPreset(plugin, preset.Name) {
+				condition := greenhousev1alpha1.FalseCondition(greenhousev1alpha1.KubeVersionCompatibleCondition, greenhousev1alpha1.KubeVersionIncompatibleReason,
+					fmt.Sprintf("Kubernetes version %s of cluster %s does not satisfy %s, the Plugin is not updated by PluginPreset %s",
+						cluster.Status.KubernetesVersion, cluster.GetName(), pluginDefinition.Status.KubeVersion, preset.Name))
+				if err := r.setPluginKubeVersionCondition(ctx, plugin, condition); err != nil {
+					allErrs = append(allErrs, err)
+				}
+			}
+			continue
 		case err == nil:
 			// The Plugin exists but does not contain the labels of the PluginPreset. This Plugin is not managed by the PluginPreset and must not be touched.
 			if shouldSkipPlugin(plugin, preset, pluginDefinition, cluster.Name) {
@@ -202,8 +224,14 @@ func (r *PluginPresetReconciler) reconcilePluginPreset(ctx context.Context, pres
 			}
 			failedPlugins = append(failedPlugins, plugin.Name+": "+errorMessage)
 			allErrs = append(allErrs, err)
+			continue
+		}
+		condition := greenhousev1alpha1.TrueCondition(greenhousev1alpha1.KubeVersionCompatibleCondition, "", "")
+		if err := r.setPluginKubeVersionCondition(ctx, plugin, condition); err != nil {
+			allErrs = append(allErrs, err)
 		}
 	}
+	preset.Status.IncompatibleClusters = incompatibleClusters
 	switch {
 	case len(skippedPlugins) > 0:
 		preset.SetCondition(greenhousev1alpha1.TrueCondition(greenhousev1alpha1.PluginSkippedCondition, "", "Skipped existing plugins: "+strings.Join(skippedPlugins, ", ")))
@@ -219,6 +247,20 @@ func (r *PluginPresetReconciler) reconcilePluginPreset(ctx context.Context, pres
 	return utilerrors.NewAggregate(allErrs)
 }
 
+// setPluginKubeVersionCondition updates the KubeVersionCompatible condition of a Plugin managed by the PluginPreset.
+// The condition is only added once the cluster of the Plugin became incompatible, Plugins of compatible clusters are not patched otherwise.
+func (r *PluginPresetReconciler) setPluginKubeVersionCondition(ctx context.Context, plugin *greenhousev1alpha1.Plugin, condition greenhousev1alpha1.Condition) error {
+	current := plugin.Status.GetConditionByType(greenhousev1alpha1.KubeVersionCompatibleCondition)
+	if (current == nil && condition.IsTrue()) || (current != nil && current.Equal(condition)) {
+		return nil
+	}
+	_, err := clientutil.PatchStatus(ctx, r.Client, plugin, func() error {
+		plugin.SetCondition(condition)
+		return nil
+	})
+	return err
+}
+
 // reconcilePluginStatuses updates plugin statuses in PluginPreset for every Plugin managed by the Preset.
 func (r *PluginPresetReconciler) reconcilePluginStatuses(
 	ctx context.Context, preset *greenhousev1alpha1.PluginPreset,
@@ -417,6 +459,21 @@ func (r *PluginPresetReconciler) enqueueAllPluginPresetsInNamespace(ctx context.
 	return listPluginPresetAsReconcileRequests(ctx, r.Client, client.InNamespace(obj.GetNamespace()))
 }
 
+// enqueuePluginPresetsForPluginDefinition returns a list of reconcile requests for all PluginPresets referencing the PluginDefinition.
+func (r *PluginPresetReconciler) enqueuePluginPresetsForPluginDefinition(ctx context.Context, obj client.Object) []ctrl.Request {
+	var allPluginPresets = new(greenhousev1alpha1.PluginPresetList)
+	if err := r.List(ctx, allPluginPresets); err != nil {
+		return nil
+	}
+	requests := make([]ctrl.Request, 0)
+	for _, pluginPreset := range allPluginPresets.Items {
+		if pluginPreset.Spec.Plugin.PluginDefinition == obj.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pluginPreset.DeepCopy())})
+		}
+	}
+	return requests
+}
+
 // listPluginPresetsAsReconcileRequests returns a list of reconcile requests for all PluginPresets that match the given list options.
 func listPluginPresetAsReconcileRequests(ctx context.Context, c client.Client, listOpts ...client.ListOption) []ctrl.Request {
 	var allPluginPresets = new(greenhousev1alpha1.PluginPresetList)
@@ -307,6 +307,93 @@ var _ = Describe("PluginPreset Controller Lifecycle", Ordered, func() {
 		}).Should(Succeed(), "the PluginPreset should be reconciled")
 	})
 
+	It("should skip clusters incompatible with the kubeVersion of the PluginDefinition", func() {
+		By("ensuring the Kubernetes version of the cluster is known")
+		Eventually(func(g Gomega) {
+			c := &greenhousev1alpha1.Cluster{}
+			err := test.K8sClient.Get(test.Ctx, types.NamespacedName{Name: clusterA, Namespace: test.TestNamespace}, c)
+			g.Expect(err).ShouldNot(HaveOccurred(), "unexpected error getting Cluster")
+			g.Expect(c.Status.KubernetesVersion).ShouldNot(BeEmpty(), "the Kubernetes version of the cluster should be set")
+		}).Should(Succeed(), "the Cluster should be reconciled")
+
+		By("restricting the kubeVersion of the PluginDefinition")
+		pluginDefinition := &greenhousev1alpha1.PluginDefinition{}
+		Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(pluginPresetDefinition), pluginDefinition)).To(Succeed(), "failed to get the PluginDefinition")
+		_, err := clientutil.PatchStatus(test.Ctx, test.K8sClient, pluginDefinition, func() error {
+			pluginDefinition.Status.KubeVersion = "< 1.0.0"
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred(), "failed to patch the PluginDefinition status")
+
+		By("creating a PluginPreset")
+		testPluginPreset := pluginPreset("incompatible", clusterA)
+		Expect(test.K8sClient.Create(test.Ctx, testPluginPreset)).Should(Succeed(), "failed to create test PluginPreset")
+
+		Eventually(func(g Gomega) {
+			err := test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(testPluginPreset), testPluginPreset)
+			g.Expect(err).ShouldNot(HaveOccurred(), "unexpected error getting PluginPreset")
+			g.Expect(testPluginPreset.Status.IncompatibleClusters).To(ConsistOf(clusterA), "the PluginPreset should report the incompatible cluster")
+		}).Should(Succeed(), "the PluginPreset should be reconciled")
+
+		pluginList := &greenhousev1alpha1.PluginList{}
+		err = test.K8sClient.List(test.Ctx, pluginList, client.MatchingLabels{greenhouseapis.LabelKeyPluginPreset: testPluginPreset.Name})
+		Expect(err).NotTo(HaveOccurred(), "failed to list Plugins")
+		Expect(pluginList.Items).To(BeEmpty(), "there should be no Plugin for the incompatible cluster")
+
+		By("cleaning up")
+		_, err = clientutil.PatchStatus(test.Ctx, test.K8sClient, pluginDefinition, func() error {
+			pluginDefinition.Status.KubeVersion = ""
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred(), "failed to reset the PluginDefinition status")
+		_, err = clientutil.Patch(test.Ctx, test.K8sClient, testPluginPreset, func() error {
+			delete(testPluginPreset.Annotations, preventDeletionAnnotation)
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred(), "failed to patch PluginPreset")
+		test.EventuallyDeleted(test.Ctx, test.K8sClient, testPluginPreset)
+	})
+
+	It("should report the incompatibility on existing Plugins", func() {
+		By("creating a PluginPreset")
+		testPluginPreset := pluginPreset("becomes-incompatible", clusterA)
+		Expect(test.K8sClient.Create(test.Ctx, testPluginPreset)).Should(Succeed(), "failed to create test PluginPreset")
+
+		plugin := &greenhousev1alpha1.Plugin{}
+		Eventually(func() error {
+			return test.K8sClient.Get(test.Ctx, types.NamespacedName{Name: testPluginPreset.Name + "-" + clusterA, Namespace: test.TestNamespace}, plugin)
+		}).Should(Succeed(), "the Plugin should be created")
+
+		By("restricting the kubeVersion of the PluginDefinition")
+		pluginDefinition := &greenhousev1alpha1.PluginDefinition{}
+		Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(pluginPresetDefinition), pluginDefinition)).To(Succeed(), "failed to get the PluginDefinition")
+		_, err := clientutil.PatchStatus(test.Ctx, test.K8sClient, pluginDefinition, func() error {
+			pluginDefinition.Status.KubeVersion = "< 1.0.0"
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred(), "failed to patch the PluginDefinition status")
+
+		Eventually(func(g Gomega) {
+			g.Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(plugin), plugin)).To(Succeed(), "unexpected error getting Plugin")
+			condition := plugin.Status.GetConditionByType(greenhousev1alpha1.KubeVersionCompatibleCondition)
+			g.Expect(condition).ToNot(BeNil(), "the KubeVersionCompatible condition should be set")
+			g.Expect(condition.IsFalse()).To(BeTrue(), "the KubeVersionCompatible condition should be false")
+		}).Should(Succeed(), "the Plugin should report the incompatibility")
+
+		By("cleaning up")
+		_, err = clientutil.PatchStatus(test.Ctx, test.K8sClient, pluginDefinition, func() error {
+			pluginDefinition.Status.KubeVersion = ""
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred(), "failed to reset the PluginDefinition status")
+		_, err = clientutil.Patch(test.Ctx, test.K8sClient, testPluginPreset, func() error {
+			delete(testPluginPreset.Annotations, preventDeletionAnnotation)
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred(), "failed to patch PluginPreset")
+		test.EventuallyDeleted(test.Ctx, test.K8sClient, testPluginPreset)
+	})
+
 	It("should reconcile PluginStatuses for PluginPreset", func() {
 		By("onboarding another Cluster")
 		err := test.K8sClient.Create(test.Ctx, cluster(otherTestClusterName))
@@ -4,11 +4,14 @@
 package plugin_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gstruct"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -405,6 +408,61 @@ var _ = Describe("HelmController reconciliation", Ordered, func() {
 		}).Should(BeEmpty(), "the helm release should be deleted from the remote cluster")
 	})
 
+	It("should postpone the uninstallation while the cluster is in maintenance", func() {
+		plugin := &greenhousev1alpha1.Plugin{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-plugin-maintenance", Namespace: test.TestNamespace},
+			Spec: greenhousev1alpha1.PluginSpec{
+				PluginDefinition: testPluginDefinition.GetName(),
+				ClusterName:      testCluster.GetName(),
+				ReleaseNamespace: test.TestNamespace,
+			},
+		}
+		remoteRestClientGetter := clientutil.NewRestClientGetterFromBytes(remoteKubeConfig, plugin.Spec.ReleaseNamespace, clientutil.WithPersistentConfig())
+		helmConfig, err := helm.ExportNewHelmAction(remoteRestClientGetter, plugin.Spec.ReleaseNamespace)
+		Expect(err).ShouldNot(HaveOccurred(), "there should be no error creating helm config")
+
+		By("creating a plugin referencing the cluster")
+		Expect(test.K8sClient.Create(test.Ctx, plugin)).Should(Succeed(), "there should be no error creating the plugin")
+		Eventually(func(g Gomega) {
+			_, err := action.NewGet(helmConfig).Run(plugin.GetName())
+			g.Expect(err).ShouldNot(HaveOccurred(), "there should be no error getting the helm release")
+		}).Should(Succeed(), "the helm release should be deployed to the remote cluster")
+
+		By("putting the cluster into maintenance")
+		cluster := &greenhousev1alpha1.Cluster{}
+		Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(testCluster), cluster)).To(Succeed(), "there should be no error getting the cluster")
+		_, err = clientutil.Patch(test.Ctx, test.K8sClient, cluster, func() error {
+			cluster.Spec.Maintenance = &greenhousev1alpha1.ClusterMaintenance{Enabled: true}
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred(), "there should be no error enabling the maintenance")
+
+		By("deleting the plugin")
+		Expect(test.K8sClient.Delete(test.Ctx, plugin)).Should(Succeed(), "there should be no error deleting the plugin")
+		Eventually(func(g Gomega) {
+			g.Expect(test.K8sClient.Get(test.Ctx, client.ObjectKeyFromObject(plugin), plugin)).To(Succeed(), "the plugin should not be removed yet")
+			maintenanceCondition := plugin.Status.GetConditionByType(greenhousev1alpha1.ClusterInMaintenance)
+			g.Expect(maintenanceCondition).ToNot(BeNil(), "the ClusterInMaintenance condition should be set")
+			g.Expect(maintenanceCondition.IsTrue()).To(BeTrue(), "the ClusterInMaintenance condition should be true")
+		}).Should(Succeed(), "the plugin should report the maintenance of the cluster")
+		Consistently(func(g Gomega) {
+			_, err := action.NewGet(helmConfig).Run(plugin.GetName())
+			g.Expect(err).ShouldNot(HaveOccurred(), "the helm release should not be uninstalled during the maintenance")
+		}).WithTimeout(3*time.Second).Should(Succeed(), "the helm release should remain on the remote cluster")
+
+		By("ending the maintenance")
+		_, err = clientutil.Patch(test.Ctx, test.K8sClient, cluster, func() error {
+			cluster.Spec.Maintenance = nil
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred(), "there should be no error disabling the maintenance")
+		test.EventuallyDeleted(test.Ctx, test.K8sClient, plugin)
+		Eventually(func(g Gomega) {
+			_, err := action.NewGet(helmConfig).Run(plugin.GetName())
+			g.Expect(err).Should(MatchError(driver.ErrReleaseNotFound), "the helm release should be uninstalled")
+		}).Should(Succeed(), "the helm release should be uninstalled after the maintenance")
+	})
+
 	It("should re-create CRD if CRD was deleted", func() {
 		By("creating plugin definition with CRDs")
 		Expect(test.K8sClient.Create(test.Ctx, testPluginWithHelmChartCRDs)).To(Succeed(), "should create plugin definition")
@@ -298,5 +298,21 @@ func shouldReconcileOrRequeue(ctx context.Context, c client.Client, plugin *gree
 		}, nil
 	}
 
+	if inMaintenance, requeueAfter := skipClusterInMaintenance(plugin, cluster); inMaintenance {
+		logger.Info("cluster is in maintenance, skipping reconciliation", "cluster", cluster.GetName())
+		return &reconcileResult{
+			requeueAfter: requeueAfter,
+		}, nil
+	}
+
 	return nil, nil
 }
+
+// skipClusterInMaintenance returns true and the duration until the maintenance window ends if the cluster of the Plugin is in maintenance.
+// The ClusterInMaintenance condition of the Plugin is updated accordingly.
+func skipClusterInMaintenance(plugin *greenhousev1alpha1.Plugin, cluster *greenhousev1alpha1.Cluster) (bool, time.Duration) {
+	maintenance := clientutil.NewMaintenanceTracker(time.Now())
+	inMaintenance := maintenance.Skip(cluster)
+	maintenance.SetCondition(plugin)
+	return inMaintenance, maintenance.RequeueAfter()
+}
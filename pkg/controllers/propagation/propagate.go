@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package propagation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+const (
+	kindSecret    = "Secret"
+	kindConfigMap = "ConfigMap"
+)
+
+// propagatedObjects are the Secrets and ConfigMaps selected by a ResourcePropagation.
+type propagatedObjects struct {
+	secrets    []corev1.Secret
+	configMaps []corev1.ConfigMap
+}
+
+// references returns the references to all propagated objects.
+func (p *propagatedObjects) references() []greenhousev1alpha1.PropagatedObjectReference {
+	var refs []greenhousev1alpha1.PropagatedObjectReference
+	for _, s := range p.secrets {
+		refs = append(refs, greenhousev1alpha1.PropagatedObjectReference{Kind: kindSecret, Name: s.GetName()})
+	}
+	for _, cm := range p.configMaps {
+		refs = append(refs, greenhousev1alpha1.PropagatedObjectReference{Kind: kindConfigMap, Name: cm.GetName()})
+	}
+	return refs
+}
+
+// isPropagationAllowed returns true for Secrets explicitly labeled to be propagated.
+// The Secrets of the organization may not be readable by the users creating a ResourcePropagation, e.g. the secret values of Plugins.
+// Secrets that must never leave the organization namespace, such as cluster kubeconfigs and service account tokens, are not allowed regardless of the label.
+func isPropagationAllowed(secret *corev1.Secret) bool {
+	if secret.Type == corev1.SecretTypeServiceAccountToken || strings.HasPrefix(string(secret.Type), greenhouseapis.GroupName+"/") {
+		return false
+	}
+	return secret.GetLabels()[greenhouseapis.LabelKeyAllowPropagation] == "true"
+}
+
+// listSourceObjects returns the Secrets and ConfigMaps selected by the ResourcePropagation.
+// Selected objects that do not exist are reported as error, the existing objects are returned nonetheless.
+// The returned objects are nil if listing failed.
+func listSourceObjects(ctx context.Context, c client.Client, rp *greenhousev1alpha1.ResourcePropagation) (*propagatedObjects, error) {
+	objects := &propagatedObjects{}
+	var missing []string
+	if !rp.Spec.Secrets.IsEmpty() {
+		var secrets = new(corev1.SecretList)
+		if err := c.List(ctx, secrets, client.InNamespace(rp.GetNamespace())); err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets.Items {
+			if !isPropagationAllowed(&secret) || !isSelected(rp.Spec.Secrets, &secret) {
+				continue
+			}
+			objects.secrets = append(objects.secrets, secret)
+		}
+		for _, name := range rp.Spec.Secrets.Names {
+			if !slices.ContainsFunc(objects.secrets, func(s corev1.Secret) bool { return s.GetName() == name }) {
+				missing = append(missing, kindSecret+" "+name)
+			}
+		}
+	}
+	if !rp.Spec.ConfigMaps.IsEmpty() {
+		var configMaps = new(corev1.ConfigMapList)
+		if err := c.List(ctx, configMaps, client.InNamespace(rp.GetNamespace())); err != nil {
+			return nil, err
+		}
+		for _, configMap := range configMaps.Items {
+			if !isSelected(rp.Spec.ConfigMaps, &configMap) {
+				continue
+			}
+			objects.configMaps = append(objects.configMaps, configMap)
+		}
+		for _, name := range rp.Spec.ConfigMaps.Names {
+			if !slices.ContainsFunc(objects.configMaps, func(cm corev1.ConfigMap) bool { return cm.GetName() == name }) {
+				missing = append(missing, kindConfigMap+" "+name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return objects, fmt.Errorf("selected objects not found or not allowed to be propagated: %s", strings.Join(missing, ", "))
+	}
+	return objects, nil
+}
+
+// propagateToCluster creates or updates the objects in all namespaces of the ResourcePropagation in the remote cluster.
+// Objects previously propagated but no longer selected are removed.
+func propagateToCluster(ctx context.Context, cl client.Client, rp *greenhousev1alpha1.ResourcePropagation, objects *propagatedObjects) error {
+	var errs []error
+	for _, namespace := range rp.Spec.Namespaces {
+		if rp.Spec.CreateNamespaces {
+			if err := ensureNamespace(ctx, cl, namespace); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		for _, secret := range objects.secrets {
+			if err := propagateSecret(ctx, cl, rp, &secret, namespace); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for _, configMap := range objects.configMaps {
+			if err := propagateConfigMap(ctx, cl, rp, &configMap, namespace); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := pruneCluster(ctx, cl, rp, objects); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func ensureNamespace(ctx context.Context, cl client.Client, namespace string) error {
+	err := cl.Get(ctx, types.NamespacedName{Name: namespace}, &corev1.Namespace{})
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	return clientutil.IgnoreAlreadyExists(cl.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}))
+}
+
+func propagateSecret(ctx context.Context, cl client.Client, rp *greenhousev1alpha1.ResourcePropagation, source *corev1.Secret, namespace string) error {
+	remote := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: source.GetName(), Namespace: namespace}}
+	result, err := clientutil.CreateOrPatch(ctx, cl, remote, func() error {
+		if err := ensureManagedBy(remote, rp); err != nil {
+			return err
+		}
+		if remote.ResourceVersion != "" && remote.Type != source.Type {
+			return fmt.Errorf("type of Secret %s/%s is immutable", namespace, remote.GetName())
+		}
+		remote.Labels = propagatedLabels(source, rp)
+		remote.Type = source.Type
+		remote.Data = source.Data
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logPropagation(ctx, result, kindSecret, remote)
+	return nil
+}
+
+func propagateConfigMap(ctx context.Context, cl client.Client, rp *greenhousev1alpha1.ResourcePropagation, source *corev1.ConfigMap, namespace string) error {
+	remote := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: source.GetName(), Namespace: namespace}}
+	result, err := clientutil.CreateOrPatch(ctx, cl, remote, func() error {
+		if err := ensureManagedBy(remote, rp); err != nil {
+			return err
+		}
+		remote.Labels = propagatedLabels(source, rp)
+		remote.Data = source.Data
+		remote.BinaryData = source.BinaryData
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logPropagation(ctx, result, kindConfigMap, remote)
+	return nil
+}
+
+// ensureManagedBy returns an error if the remote object already exists but was not created by the ResourcePropagation.
+// Existing objects are never taken over to prevent overwriting objects managed by someone else.
+func ensureManagedBy(remote client.Object, rp *greenhousev1alpha1.ResourcePropagation) error {
+	if remote.GetResourceVersion() == "" || remote.GetLabels()[greenhouseapis.LabelKeyResourcePropagation] == rp.GetName() {
+		return nil
+	}
+	return fmt.Errorf("%s/%s already exists and is not managed by ResourcePropagation %s", remote.GetNamespace(), remote.GetName(), rp.GetName())
+}
+
+// propagatedLabels returns the labels of the source object with the label identifying the ResourcePropagation.
+func propagatedLabels(source client.Object, rp *greenhousev1alpha1.ResourcePropagation) map[string]string {
+	labels := maps.Clone(source.GetLabels())
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[greenhouseapis.LabelKeyResourcePropagation] = rp.GetName()
+	return labels
+}
+
+// pruneCluster deletes all objects in the remote cluster propagated by the ResourcePropagation that are not desired anymore.
+// All propagated objects are deleted if objects is nil.
+func pruneCluster(ctx context.Context, cl client.Client, rp *greenhousev1alpha1.ResourcePropagation, objects *propagatedObjects) error {
+	if objects == nil {
+		objects = &propagatedObjects{}
+	}
+	isDesired := func(o client.Object, kind string) bool {
+		return slices.Contains(rp.Spec.Namespaces, o.GetNamespace()) &&
+			slices.Contains(objects.references(), greenhousev1alpha1.PropagatedObjectReference{Kind: kind, Name: o.GetName()})
+	}
+	managedBy := client.MatchingLabels{greenhouseapis.LabelKeyResourcePropagation: rp.GetName()}
+
+	var errs []error
+	var secrets = new(corev1.SecretList)
+	if err := cl.List(ctx, secrets, managedBy); err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		if isDesired(&secret, kindSecret) {
+			continue
+		}
+		if _, err := clientutil.Delete(ctx, cl, &secret); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	var configMaps = new(corev1.ConfigMapList)
+	if err := cl.List(ctx, configMaps, managedBy); err != nil {
+		return err
+	}
+	for _, configMap := range configMaps.Items {
+		if isDesired(&configMap, kindConfigMap) {
+			continue
+		}
+		if _, err := clientutil.Delete(ctx, cl, &configMap); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
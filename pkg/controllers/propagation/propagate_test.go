@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package propagation
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/test"
+)
+
+var _ = Describe("ResourcePropagation", func() {
+	const orgNamespace = "test-org"
+
+	var (
+		ctx = context.Background()
+		rp  *greenhousev1alpha1.ResourcePropagation
+
+		pullSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: orgNamespace, Labels: map[string]string{"app": "registry", greenhouseapis.LabelKeyAllowPropagation: "true"}},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte("{}")},
+		}
+		kubeconfigSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: orgNamespace, Labels: map[string]string{"app": "registry", greenhouseapis.LabelKeyAllowPropagation: "true"}},
+			Type:       greenhouseapis.SecretTypeKubeConfig,
+		}
+		pluginSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "plugin-options", Namespace: orgNamespace, Labels: map[string]string{"app": "registry"}},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"password": []byte("secret")},
+		}
+		caBundle = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: orgNamespace},
+			Data:       map[string]string{"ca.crt": "certificate"},
+		}
+	)
+
+	BeforeEach(func() {
+		rp = &greenhousev1alpha1.ResourcePropagation{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-propagation", Namespace: orgNamespace},
+			Spec: greenhousev1alpha1.ResourcePropagationSpec{
+				Secrets:    greenhousev1alpha1.PropagatedObjectSelector{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "registry"}}},
+				ConfigMaps: greenhousev1alpha1.PropagatedObjectSelector{Names: []string{"ca-bundle"}},
+				Namespaces: []string{"default", "monitoring"},
+			},
+		}
+	})
+
+	Context("listing the source objects", func() {
+		It("should select the objects and skip restricted Secrets", func() {
+			c := fake.NewClientBuilder().WithScheme(test.GreenhouseV1Alpha1Scheme()).WithObjects(pullSecret.DeepCopy(), kubeconfigSecret.DeepCopy(), caBundle.DeepCopy()).Build()
+
+			objects, err := listSourceObjects(ctx, c, rp)
+			Expect(err).ToNot(HaveOccurred(), "there should be no error listing the source objects")
+			Expect(objects.references()).To(ConsistOf(
+				greenhousev1alpha1.PropagatedObjectReference{Kind: kindSecret, Name: "pull-secret"},
+				greenhousev1alpha1.PropagatedObjectReference{Kind: kindConfigMap, Name: "ca-bundle"},
+			), "the kubeconfig Secret must not be propagated")
+		})
+
+		It("should refuse Secrets not labeled to allow the propagation", func() {
+			rp.Spec.Secrets.Names = []string{"plugin-options"}
+			c := fake.NewClientBuilder().WithScheme(test.GreenhouseV1Alpha1Scheme()).WithObjects(pullSecret.DeepCopy(), pluginSecret.DeepCopy(), caBundle.DeepCopy()).Build()
+
+			objects, err := listSourceObjects(ctx, c, rp)
+			Expect(err).To(HaveOccurred(), "there should be an error for the Secret without the label")
+			Expect(err.Error()).To(ContainSubstring("Secret plugin-options"), "the unlabeled Secret should be reported")
+			Expect(objects.references()).ToNot(ContainElement(
+				greenhousev1alpha1.PropagatedObjectReference{Kind: kindSecret, Name: "plugin-options"},
+			), "the unlabeled Opaque Secret must not be propagated")
+		})
+
+		It("should report missing objects and return the existing ones", func() {
+			rp.Spec.Secrets.Names = []string{"test-cluster"}
+			c := fake.NewClientBuilder().WithScheme(test.GreenhouseV1Alpha1Scheme()).WithObjects(pullSecret.DeepCopy(), kubeconfigSecret.DeepCopy()).Build()
+
+			objects, err := listSourceObjects(ctx, c, rp)
+			Expect(err).To(HaveOccurred(), "there should be an error for the restricted and the missing object")
+			Expect(err.Error()).To(ContainSubstring("Secret test-cluster"), "the restricted Secret should be reported")
+			Expect(err.Error()).To(ContainSubstring("ConfigMap ca-bundle"), "the missing ConfigMap should be reported")
+			Expect(objects.references()).To(ConsistOf(
+				greenhousev1alpha1.PropagatedObjectReference{Kind: kindSecret, Name: "pull-secret"},
+			), "the existing objects should be returned")
+		})
+	})
+
+	Context("propagating to a cluster", func() {
+		var (
+			remote  client.Client
+			objects *propagatedObjects
+		)
+
+		BeforeEach(func() {
+			remote = fake.NewClientBuilder().WithScheme(test.GreenhouseV1Alpha1Scheme()).Build()
+			objects = &propagatedObjects{
+				secrets:    []corev1.Secret{*pullSecret.DeepCopy()},
+				configMaps: []corev1.ConfigMap{*caBundle.DeepCopy()},
+			}
+		})
+
+		It("should create the objects in all namespaces", func() {
+			rp.Spec.CreateNamespaces = true
+			Expect(propagateToCluster(ctx, remote, rp, objects)).To(Succeed(), "there should be no error propagating the objects")
+
+			for _, namespace := range rp.Spec.Namespaces {
+				Expect(remote.Get(ctx, types.NamespacedName{Name: namespace}, &corev1.Namespace{})).To(Succeed(), "the namespace should be created")
+
+				secret := &corev1.Secret{}
+				Expect(remote.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "pull-secret"}, secret)).To(Succeed(), "the Secret should be propagated")
+				Expect(secret.Type).To(Equal(corev1.SecretTypeDockerConfigJson), "the Secret type should be propagated")
+				Expect(secret.Data).To(Equal(pullSecret.Data), "the Secret data should be propagated")
+				Expect(secret.Labels).To(HaveKeyWithValue("app", "registry"), "the Secret labels should be propagated")
+				Expect(secret.Labels).To(HaveKeyWithValue(greenhouseapis.LabelKeyResourcePropagation, rp.GetName()), "the Secret should be labeled with the ResourcePropagation")
+
+				configMap := &corev1.ConfigMap{}
+				Expect(remote.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "ca-bundle"}, configMap)).To(Succeed(), "the ConfigMap should be propagated")
+				Expect(configMap.Data).To(Equal(caBundle.Data), "the ConfigMap data should be propagated")
+			}
+		})
+
+		It("should correct drift of the propagated objects", func() {
+			Expect(propagateToCluster(ctx, remote, rp, objects)).To(Succeed(), "there should be no error propagating the objects")
+
+			configMap := &corev1.ConfigMap{}
+			Expect(remote.Get(ctx, types.NamespacedName{Namespace: "default", Name: "ca-bundle"}, configMap)).To(Succeed(), "the ConfigMap should be propagated")
+			configMap.Data = map[string]string{"ca.crt": "modified"}
+			Expect(remote.Update(ctx, configMap)).To(Succeed(), "there should be no error modifying the ConfigMap")
+
+			Expect(propagateToCluster(ctx, remote, rp, objects)).To(Succeed(), "there should be no error propagating the objects")
+			Expect(remote.Get(ctx, types.NamespacedName{Namespace: "default", Name: "ca-bundle"}, configMap)).To(Succeed(), "the ConfigMap should exist")
+			Expect(configMap.Data).To(Equal(caBundle.Data), "the ConfigMap should be reset to the source data")
+		})
+
+		It("should not overwrite objects not managed by the ResourcePropagation", func() {
+			existing := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "default"},
+				Data:       map[string]string{"ca.crt": "unmanaged"},
+			}
+			Expect(remote.Create(ctx, existing)).To(Succeed(), "there should be no error creating the unmanaged ConfigMap")
+
+			Expect(propagateToCluster(ctx, remote, rp, objects)).ToNot(Succeed(), "there should be an error propagating to an unmanaged object")
+			Expect(remote.Get(ctx, client.ObjectKeyFromObject(existing), existing)).To(Succeed(), "the unmanaged ConfigMap should exist")
+			Expect(existing.Data).To(HaveKeyWithValue("ca.crt", "unmanaged"), "the unmanaged ConfigMap should not be modified")
+			Expect(remote.Get(ctx, types.NamespacedName{Namespace: "monitoring", Name: "ca-bundle"}, &corev1.ConfigMap{})).To(Succeed(), "the other namespaces should be propagated")
+		})
+
+		It("should remove objects and namespaces no longer selected", func() {
+			Expect(propagateToCluster(ctx, remote, rp, objects)).To(Succeed(), "there should be no error propagating the objects")
+
+			rp.Spec.Namespaces = []string{"default"}
+			objects.configMaps = nil
+			Expect(propagateToCluster(ctx, remote, rp, objects)).To(Succeed(), "there should be no error propagating the objects")
+
+			err := remote.Get(ctx, types.NamespacedName{Namespace: "monitoring", Name: "pull-secret"}, &corev1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "the Secret should be removed from the deselected namespace")
+			err = remote.Get(ctx, types.NamespacedName{Namespace: "default", Name: "ca-bundle"}, &corev1.ConfigMap{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "the deselected ConfigMap should be removed")
+			Expect(remote.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pull-secret"}, &corev1.Secret{})).To(Succeed(), "the selected Secret should remain")
+		})
+
+		It("should remove all propagated objects on cleanup", func() {
+			Expect(propagateToCluster(ctx, remote, rp, objects)).To(Succeed(), "there should be no error propagating the objects")
+			Expect(pruneCluster(ctx, remote, rp, nil)).To(Succeed(), "there should be no error removing the objects")
+
+			var secrets = new(corev1.SecretList)
+			Expect(remote.List(ctx, secrets)).To(Succeed(), "there should be no error listing the Secrets")
+			Expect(secrets.Items).To(BeEmpty(), "all propagated Secrets should be removed")
+			var configMaps = new(corev1.ConfigMapList)
+			Expect(remote.List(ctx, configMaps)).To(Succeed(), "there should be no error listing the ConfigMaps")
+			Expect(configMaps.Items).To(BeEmpty(), "all propagated ConfigMaps should be removed")
+		})
+	})
+})
@@ -0,0 +1,315 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package propagation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+	"github.com/cloudoperators/greenhouse/pkg/lifecycle"
+)
+
+// requeueInterval is the interval after which the propagated objects are checked for drift.
+const requeueInterval = 10 * time.Minute
+
+var exposedConditions = []greenhousev1alpha1.ConditionType{
+	greenhousev1alpha1.ReadyCondition,
+	greenhousev1alpha1.ClusterListEmpty,
+	greenhousev1alpha1.ResourcesPropagated,
+}
+
+// ResourcePropagationReconciler reconciles a ResourcePropagation object
+type ResourcePropagationReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=resourcepropagations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=resourcepropagations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=resourcepropagations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=clusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ResourcePropagationReconciler) SetupWithManager(name string, mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	r.recorder = mgr.GetEventRecorderFor(name)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&greenhousev1alpha1.ResourcePropagation{}).
+		// Propagate changes of the selected Secrets and ConfigMaps.
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.enqueueResourcePropagationsSelecting)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.enqueueResourcePropagationsSelecting)).
+		// Reconcile ResourcePropagations for all Cluster label changes in the same namespace
+		Watches(&greenhousev1alpha1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllResourcePropagationsInNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *ResourcePropagationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return lifecycle.Reconcile(ctx, r.Client, req.NamespacedName, &greenhousev1alpha1.ResourcePropagation{}, r, r.setConditions())
+}
+
+func (r *ResourcePropagationReconciler) setConditions() lifecycle.Conditioner {
+	return func(ctx context.Context, resource lifecycle.RuntimeObject) {
+		logger := ctrl.LoggerFrom(ctx)
+		rp, ok := resource.(*greenhousev1alpha1.ResourcePropagation)
+		if !ok {
+			logger.Error(errors.New("resource is not a ResourcePropagation"), "status setup failed")
+			return
+		}
+		rp.SetCondition(computeReadyCondition(rp.Status))
+	}
+}
+
+func (r *ResourcePropagationReconciler) EnsureCreated(ctx context.Context, resource lifecycle.RuntimeObject) (ctrl.Result, lifecycle.ReconcileResult, error) {
+	rp, ok := resource.(*greenhousev1alpha1.ResourcePropagation)
+	if !ok {
+		return ctrl.Result{}, lifecycle.Failed, errors.New("RuntimeObject has incompatible type")
+	}
+
+	initResourcePropagationStatus(rp)
+
+	objects, sourceErr := listSourceObjects(ctx, r.Client, rp)
+	if objects == nil {
+		rp.SetCondition(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ResourcesPropagated, greenhousev1alpha1.SourceObjectsFailed, sourceErr.Error()))
+		return ctrl.Result{}, lifecycle.Failed, sourceErr
+	}
+	rp.Status.PropagatedObjects = objects.references()
+
+	clusters, err := r.listClusters(ctx, rp)
+	if err != nil {
+		rp.SetCondition(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ResourcesPropagated, greenhousev1alpha1.EmptyClusterList, "Failed to get clusters for ResourcePropagation"))
+		return ctrl.Result{}, lifecycle.Failed, err
+	}
+	switch len(clusters.Items) {
+	case 0:
+		rp.SetCondition(greenhousev1alpha1.TrueCondition(greenhousev1alpha1.ClusterListEmpty, "", ""))
+		r.recorder.Eventf(rp, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "No clusters found for %s", rp.GetName())
+	default:
+		rp.SetCondition(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ClusterListEmpty, "", ""))
+	}
+
+	// Remove the propagated objects from all clusters that are no longer selected.
+	if err := r.cleanupDeselectedClusters(ctx, rp, clusters); err != nil {
+		return ctrl.Result{}, lifecycle.Failed, err
+	}
+
+	var failedClusters []string
+	for _, cluster := range clusters.Items {
+		remoteClient, err := clientutil.NewK8sClientFromCluster(ctx, r.Client, &cluster)
+		if err != nil {
+			r.recorder.Eventf(rp, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "Error getting client for cluster %s to propagate %s", cluster.GetName(), rp.GetName())
+			rp.SetPropagationStatus(cluster.GetName(), metav1.ConditionFalse, greenhousev1alpha1.ClusterConnectionFailed, err.Error())
+			failedClusters = append(failedClusters, cluster.GetName())
+			continue
+		}
+		if err := propagateToCluster(ctx, remoteClient, rp, objects); err != nil {
+			r.recorder.Eventf(rp, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "Failed to propagate %s to cluster %s", rp.GetName(), cluster.GetName())
+			rp.SetPropagationStatus(cluster.GetName(), metav1.ConditionFalse, greenhousev1alpha1.PropagationFailed, err.Error())
+			failedClusters = append(failedClusters, cluster.GetName())
+			continue
+		}
+		rp.SetPropagationStatus(cluster.GetName(), metav1.ConditionTrue, greenhousev1alpha1.PropagationSucceeded, "")
+	}
+
+	switch {
+	case len(failedClusters) > 0:
+		message := "Error propagating objects to clusters: " + strings.Join(failedClusters, ", ")
+		rp.SetCondition(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ResourcesPropagated, greenhousev1alpha1.PropagationFailed, message))
+		return ctrl.Result{}, lifecycle.Failed, errors.New(message)
+	case sourceErr != nil:
+		rp.SetCondition(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ResourcesPropagated, greenhousev1alpha1.SourceObjectsFailed, sourceErr.Error()))
+		return ctrl.Result{}, lifecycle.Failed, sourceErr
+	}
+	rp.SetCondition(greenhousev1alpha1.TrueCondition(greenhousev1alpha1.ResourcesPropagated, greenhousev1alpha1.PropagationSucceeded, ""))
+	// Requeue to correct drift of the propagated objects in the remote clusters.
+	return ctrl.Result{RequeueAfter: wait.Jitter(requeueInterval, 0.1)}, lifecycle.Success, nil
+}
+
+// EnsureDeleted - removes the propagated objects from all clusters.
+func (r *ResourcePropagationReconciler) EnsureDeleted(ctx context.Context, resource lifecycle.RuntimeObject) (ctrl.Result, lifecycle.ReconcileResult, error) {
+	rp, ok := resource.(*greenhousev1alpha1.ResourcePropagation)
+	if !ok {
+		return ctrl.Result{}, lifecycle.Failed, errors.New("RuntimeObject has incompatible type")
+	}
+
+	if err := r.cleanupDeselectedClusters(ctx, rp, &greenhousev1alpha1.ClusterList{}); err != nil {
+		r.recorder.Eventf(rp, corev1.EventTypeWarning, greenhousev1alpha1.FailedDeleteEvent, "Failed to remove propagated objects of %s", rp.GetName())
+		return ctrl.Result{}, lifecycle.Failed, err
+	}
+
+	// all clusters have been processed, finalizer can be removed
+	if len(rp.Status.PropagationStatus) == 0 {
+		r.recorder.Eventf(rp, corev1.EventTypeNormal, greenhousev1alpha1.SuccessfulDeletedEvent, "Deleted propagated objects of %s from all clusters", rp.GetName())
+		return ctrl.Result{}, lifecycle.Success, nil
+	}
+	return ctrl.Result{}, lifecycle.Pending, nil
+}
+
+// cleanupDeselectedClusters removes the propagated objects from all clusters in the status that are not contained in the given ClusterList.
+// If the Cluster is not ready, the status is updated accordingly but no objects are removed.
+func (r *ResourcePropagationReconciler) cleanupDeselectedClusters(ctx context.Context, rp *greenhousev1alpha1.ResourcePropagation, clusters *greenhousev1alpha1.ClusterList) error {
+	for _, s := range slices.Clone(rp.Status.PropagationStatus) {
+		if slices.ContainsFunc(clusters.Items, func(c greenhousev1alpha1.Cluster) bool { return c.GetName() == s.ClusterName }) {
+			continue
+		}
+		cluster := &greenhousev1alpha1.Cluster{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: rp.GetNamespace(), Name: s.ClusterName}, cluster)
+		if apierrors.IsNotFound(err) {
+			// cluster has been removed, nothing to be done
+			rp.RemovePropagationStatus(s.ClusterName)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !cluster.Status.StatusConditions.IsReadyTrue() {
+			rp.SetPropagationStatus(s.ClusterName, metav1.ConditionFalse, greenhousev1alpha1.ClusterConnectionFailed, "Cluster is not ready")
+			continue
+		}
+		remoteClient, err := clientutil.NewK8sClientFromCluster(ctx, r.Client, cluster)
+		if err != nil {
+			return err
+		}
+		if err := pruneCluster(ctx, remoteClient, rp, nil); err != nil {
+			return err
+		}
+		rp.RemovePropagationStatus(s.ClusterName)
+	}
+	return nil
+}
+
+// listClusters returns the list of ready Clusters that match the ResourcePropagation's ClusterSelector.
+// Clusters which are not ready are removed from the list and the PropagationStatus updated.
+func (r *ResourcePropagationReconciler) listClusters(ctx context.Context, rp *greenhousev1alpha1.ResourcePropagation) (*greenhousev1alpha1.ClusterList, error) {
+	clusterSelector, err := metav1.LabelSelectorAsSelector(&rp.Spec.ClusterSelector)
+	if err != nil {
+		return nil, err
+	}
+	var clusters = new(greenhousev1alpha1.ClusterList)
+	if err := r.List(ctx, clusters, client.InNamespace(rp.GetNamespace()), client.MatchingLabelsSelector{Selector: clusterSelector}); err != nil {
+		return nil, err
+	}
+	clusters.Items = slices.DeleteFunc(clusters.Items, func(c greenhousev1alpha1.Cluster) bool {
+		if !c.Status.StatusConditions.IsReadyTrue() {
+			rp.SetPropagationStatus(c.GetName(), metav1.ConditionFalse, greenhousev1alpha1.ClusterConnectionFailed, "Cluster is not ready")
+			return true
+		}
+		return false
+	})
+	return clusters, nil
+}
+
+// enqueueResourcePropagationsSelecting enqueues all ResourcePropagations in the namespace of the Secret or ConfigMap selecting it.
+func (r *ResourcePropagationReconciler) enqueueResourcePropagationsSelecting(ctx context.Context, o client.Object) []ctrl.Request {
+	var resourcePropagations = new(greenhousev1alpha1.ResourcePropagationList)
+	if err := r.List(ctx, resourcePropagations, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []ctrl.Request
+	for _, rp := range resourcePropagations.Items {
+		selector := rp.Spec.Secrets
+		if _, ok := o.(*corev1.ConfigMap); ok {
+			selector = rp.Spec.ConfigMaps
+		}
+		// Also enqueue if the object was previously propagated, e.g. its labels changed.
+		wasPropagated := slices.ContainsFunc(rp.Status.PropagatedObjects, func(ref greenhousev1alpha1.PropagatedObjectReference) bool {
+			return ref.Name == o.GetName()
+		})
+		if wasPropagated || isSelected(selector, o) {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&rp)})
+		}
+	}
+	return requests
+}
+
+// enqueueAllResourcePropagationsInNamespace returns a list of reconcile requests for all ResourcePropagations in the same namespace as obj.
+func (r *ResourcePropagationReconciler) enqueueAllResourcePropagationsInNamespace(ctx context.Context, obj client.Object) []ctrl.Request {
+	var resourcePropagations = new(greenhousev1alpha1.ResourcePropagationList)
+	if err := r.List(ctx, resourcePropagations, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := make([]ctrl.Request, len(resourcePropagations.Items))
+	for i, rp := range resourcePropagations.Items {
+		requests[i] = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(rp.DeepCopy())}
+	}
+	return requests
+}
+
+// isSelected returns true if the object is selected by name or labels.
+func isSelected(selector greenhousev1alpha1.PropagatedObjectSelector, o client.Object) bool {
+	if slices.Contains(selector.Names, o.GetName()) {
+		return true
+	}
+	if selector.LabelSelector == nil {
+		return false
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+	if err != nil {
+		return false
+	}
+	return labelSelector.Matches(labels.Set(o.GetLabels()))
+}
+
+// initResourcePropagationStatus ensures that all required conditions are present in the ResourcePropagation's Status
+func initResourcePropagationStatus(rp *greenhousev1alpha1.ResourcePropagation) {
+	for _, ct := range exposedConditions {
+		if rp.Status.GetConditionByType(ct) == nil {
+			rp.SetCondition(greenhousev1alpha1.UnknownCondition(ct, "", ""))
+		}
+	}
+}
+
+// computeReadyCondition computes the ReadyCondition based on the ResourcePropagation's StatusConditions
+func computeReadyCondition(status greenhousev1alpha1.ResourcePropagationStatus) greenhousev1alpha1.Condition {
+	readyCondition := *status.GetConditionByType(greenhousev1alpha1.ReadyCondition)
+
+	if propagated := status.GetConditionByType(greenhousev1alpha1.ResourcesPropagated); propagated != nil && propagated.IsFalse() {
+		readyCondition.Status = metav1.ConditionFalse
+		readyCondition.Message = propagated.Message
+		return readyCondition
+	}
+	for _, condition := range status.PropagationStatus {
+		if condition.IsTrue() {
+			continue
+		}
+		readyCondition.Status = metav1.ConditionFalse
+		readyCondition.Message = fmt.Sprintf("Propagation to cluster %s failed", condition.ClusterName)
+		return readyCondition
+	}
+
+	readyCondition.Status = metav1.ConditionTrue
+	readyCondition.Message = "ready"
+	return readyCondition
+}
+
+// logPropagation logs the result of propagating an object to a cluster.
+func logPropagation(ctx context.Context, result clientutil.OperationResult, kind string, o client.Object) {
+	if result == clientutil.OperationResultNone {
+		return
+	}
+	log.FromContext(ctx).Info(fmt.Sprintf("%s %s", result, kind), "namespace", o.GetNamespace(), "name", o.GetName())
+}
@@ -6,6 +6,7 @@ package teammembership
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/pkg/errors"
@@ -127,14 +128,14 @@ func (r *TeamMembershipUpdaterController) EnsureCreated(ctx context.Context, obj
 
 	teamMembershipExists := !apierrors.IsNotFound(err)
 
-	if team.Spec.MappedIDPGroup == "" {
+	if team.Spec.MappedIDPGroup == "" && len(team.Spec.Members) == 0 {
 		if teamMembershipExists {
 			membersCountMetric.With(prometheus.Labels{
 				"namespace": team.Namespace,
 				"team":      team.Name,
 			}).Set(float64(0))
 
-			log.FromContext(ctx).Info("deleting TeamMembership, Team does not have MappedIdpGroup set", "team-membership", teamMembership.Name)
+			log.FromContext(ctx).Info("deleting TeamMembership, Team does not have MappedIdpGroup or members set", "team-membership", teamMembership.Name)
 			err = r.Delete(ctx, teamMembership, &client.DeleteOptions{})
 			if err != nil {
 				return ctrl.Result{}, lifecycle.Failed, err
@@ -143,7 +144,7 @@ func (r *TeamMembershipUpdaterController) EnsureCreated(ctx context.Context, obj
 			return ctrl.Result{}, lifecycle.Success, nil
 		}
 
-		log.FromContext(ctx).Info("Team does not have MappedIdpGroup set", "team", team.Name)
+		log.FromContext(ctx).Info("Team does not have MappedIdpGroup or members set", "team", team.Name)
 		return ctrl.Result{}, lifecycle.Success, nil
 	}
 
@@ -157,39 +158,24 @@ func (r *TeamMembershipUpdaterController) EnsureCreated(ctx context.Context, obj
 		}
 	}()
 
-	orgSCIMAPIAvailableCondition := organization.Status.GetConditionByType(greenhousev1alpha1.SCIMAPIAvailableCondition)
-	if orgSCIMAPIAvailableCondition == nil || !orgSCIMAPIAvailableCondition.IsTrue() {
-		if teamMembershipExists {
-			teamMembershipStatus.SetConditions(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.SCIMAccessReadyCondition,
-				greenhousev1alpha1.SCIMAPIUnavailableReason, "SCIM API in Organization is unavailable"))
+	var scimUsers []greenhousev1alpha1.User
+	if team.Spec.MappedIDPGroup != "" {
+		var synced bool
+		scimUsers, synced, err = r.syncSCIMUsers(ctx, organization, team, &teamMembershipStatus)
+		if err != nil {
+			return ctrl.Result{}, lifecycle.Failed, err
+		}
+		switch {
+		case !synced && len(team.Spec.Members) == 0:
+			return ctrl.Result{}, lifecycle.Success, nil
+		case !synced:
+			// Keep the members retrieved from SCIM before, while updating the manually managed members.
+			scimUsers = membersFromSource(teamMembership.Spec.Members, greenhousev1alpha1.TeamMemberSourceSCIM)
 		}
-		return ctrl.Result{}, lifecycle.Success, nil
-	}
-
-	// Ignore organizations without SCIM configuration.
-	if organization.Spec.Authentication == nil || organization.Spec.Authentication.SCIMConfig == nil {
-		log.FromContext(ctx).Info("SCIM config is missing from org", "Name", teamNamespacedName)
-
-		c := greenhousev1alpha1.FalseCondition(greenhousev1alpha1.SCIMAccessReadyCondition, greenhousev1alpha1.SecretNotFoundReason, "SCIM config is missing from organization")
-		teamMembershipStatus.SetConditions(c)
-
-		return ctrl.Result{}, lifecycle.Success, nil
-	}
-
-	scimClient, err := r.createSCIMClient(ctx, team.Namespace, &teamMembershipStatus, organization.Spec.Authentication.SCIMConfig)
-	if err != nil {
-		return ctrl.Result{}, lifecycle.Failed, err
-	}
-
-	users, membersValidCondition, err := r.getUsersFromSCIM(ctx, scimClient, team.Spec.MappedIDPGroup)
-	if err != nil {
-		log.FromContext(ctx).Info("failed processing team-membership for team", "error", err)
-		teamMembershipStatus.SetConditions(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.SCIMAccessReadyCondition, greenhousev1alpha1.SCIMRequestFailedReason, ""))
-		return ctrl.Result{}, lifecycle.Failed, err
 	}
+	users := mergeMembers(scimUsers, team.Spec.Members)
 
 	team.Status.Members = users
-	teamMembershipStatus.SetConditions(membersValidCondition, greenhousev1alpha1.TrueCondition(greenhousev1alpha1.SCIMAccessReadyCondition, "", ""))
 
 	membersCountMetric.With(prometheus.Labels{
 		"namespace": team.Namespace,
@@ -217,13 +203,89 @@ func (r *TeamMembershipUpdaterController) EnsureCreated(ctx context.Context, obj
 
 	now := metav1.NewTime(time.Now())
 	teamMembershipStatus.LastChangedTime = &now
-	teamMembershipStatus.SetConditions(greenhousev1alpha1.TrueCondition(greenhousev1alpha1.SCIMAccessReadyCondition, "", ""))
 	return ctrl.Result{
 			RequeueAfter: wait.Jitter(RequeueInterval, 0.1),
 		},
 		lifecycle.Success, nil
 }
 
+// syncSCIMUsers returns the members of the MappedIDPGroup of the Team retrieved from SCIM.
+// It returns false if SCIM is not available or configured for the Organization.
+func (r *TeamMembershipUpdaterController) syncSCIMUsers(
+	ctx context.Context,
+	organization *greenhousev1alpha1.Organization,
+	team *greenhousev1alpha1.Team,
+	teamMembershipStatus *greenhousev1alpha1.TeamMembershipStatus,
+) ([]greenhousev1alpha1.User, bool, error) {
+
+	orgSCIMAPIAvailableCondition := organization.Status.GetConditionByType(greenhousev1alpha1.SCIMAPIAvailableCondition)
+	if orgSCIMAPIAvailableCondition == nil || !orgSCIMAPIAvailableCondition.IsTrue() {
+		teamMembershipStatus.SetConditions(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.SCIMAccessReadyCondition,
+			greenhousev1alpha1.SCIMAPIUnavailableReason, "SCIM API in Organization is unavailable"))
+		return nil, false, nil
+	}
+
+	// Ignore organizations without SCIM configuration.
+	if organization.Spec.Authentication == nil || organization.Spec.Authentication.SCIMConfig == nil {
+		log.FromContext(ctx).Info("SCIM config is missing from org", "Name", client.ObjectKeyFromObject(team))
+
+		c := greenhousev1alpha1.FalseCondition(greenhousev1alpha1.SCIMAccessReadyCondition, greenhousev1alpha1.SecretNotFoundReason, "SCIM config is missing from organization")
+		teamMembershipStatus.SetConditions(c)
+
+		return nil, false, nil
+	}
+
+	scimClient, err := r.createSCIMClient(ctx, team.Namespace, teamMembershipStatus, organization.Spec.Authentication.SCIMConfig)
+	if err != nil {
+		return nil, false, err
+	}
+
+	users, membersValidCondition, err := r.getUsersFromSCIM(ctx, scimClient, team.Spec.MappedIDPGroup)
+	if err != nil {
+		log.FromContext(ctx).Info("failed processing team-membership for team", "error", err)
+		teamMembershipStatus.SetConditions(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.SCIMAccessReadyCondition, greenhousev1alpha1.SCIMRequestFailedReason, ""))
+		return nil, false, err
+	}
+	teamMembershipStatus.SetConditions(membersValidCondition, greenhousev1alpha1.TrueCondition(greenhousev1alpha1.SCIMAccessReadyCondition, "", ""))
+	return users, true, nil
+}
+
+// mergeMembers returns the members retrieved from SCIM and the manually managed members with their sources.
+// A user listed by both is merged by ID, preferring the details retrieved from SCIM.
+func mergeMembers(scimUsers []greenhousev1alpha1.User, manualMembers []greenhousev1alpha1.TeamMember) []greenhousev1alpha1.User {
+	users := make([]greenhousev1alpha1.User, 0, len(scimUsers)+len(manualMembers))
+	for _, user := range scimUsers {
+		user.Sources = []greenhousev1alpha1.TeamMemberSource{greenhousev1alpha1.TeamMemberSourceSCIM}
+		users = append(users, user)
+	}
+	for _, member := range manualMembers {
+		if idx := slices.IndexFunc(users, func(u greenhousev1alpha1.User) bool { return u.ID == member.ID }); idx >= 0 {
+			users[idx].Sources = append(users[idx].Sources, greenhousev1alpha1.TeamMemberSourceManual)
+			continue
+		}
+		users = append(users, greenhousev1alpha1.User{
+			ID:        member.ID,
+			FirstName: member.FirstName,
+			LastName:  member.LastName,
+			Email:     member.Email,
+			Sources:   []greenhousev1alpha1.TeamMemberSource{greenhousev1alpha1.TeamMemberSourceManual},
+		})
+	}
+	return users
+}
+
+// membersFromSource returns the members of the TeamMembership originating from the source.
+// Members without sources were retrieved from SCIM before the sources were recorded.
+func membersFromSource(members []greenhousev1alpha1.User, source greenhousev1alpha1.TeamMemberSource) []greenhousev1alpha1.User {
+	var users []greenhousev1alpha1.User
+	for _, member := range members {
+		if slices.Contains(member.Sources, source) || (len(member.Sources) == 0 && source == greenhousev1alpha1.TeamMemberSourceSCIM) {
+			users = append(users, member)
+		}
+	}
+	return users
+}
+
 func (r *TeamMembershipUpdaterController) createSCIMClient(
 	ctx context.Context,
 	namespace string,
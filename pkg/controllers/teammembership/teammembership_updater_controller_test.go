@@ -409,12 +409,14 @@ var _ = Describe("TeammembershipUpdaterController", Ordered, func() {
 				FirstName: "John",
 				LastName:  "Doe",
 				Email:     "john.doe@example.com",
+				Sources:   []greenhousev1alpha1.TeamMemberSource{greenhousev1alpha1.TeamMemberSourceSCIM},
 			}
 			expectedUser2 := greenhousev1alpha1.User{
 				ID:        "I23456",
 				FirstName: "Jane",
 				LastName:  "Doe",
 				Email:     "jane.doe@example.com",
+				Sources:   []greenhousev1alpha1.TeamMemberSource{greenhousev1alpha1.TeamMemberSourceSCIM},
 			}
 
 			Eventually(func(g Gomega) {
@@ -437,6 +439,29 @@ var _ = Describe("TeammembershipUpdaterController", Ordered, func() {
 			}).Should(Succeed(), "the team status should be updated")
 		})
 
+		It("should merge the members from SCIM with the manually managed members", func() {
+			By("creating test Team with valid idp group and manual members")
+			team := setup.CreateTeam(test.Ctx, firstTeamName, test.WithMappedIDPGroup(validIdpGroupName), test.WithMembers(
+				greenhousev1alpha1.TeamMember{ID: "I12345"},
+				greenhousev1alpha1.TeamMember{ID: "I77777", FirstName: "Manual", LastName: "User", Email: "manual.user@example.com"},
+			))
+
+			Eventually(func(g Gomega) {
+				teamMembership := &greenhousev1alpha1.TeamMembership{}
+				g.Expect(setup.Get(test.Ctx, client.ObjectKeyFromObject(team), teamMembership)).To(Succeed(), "there should be no error getting the TeamMembership")
+				g.Expect(teamMembership.Spec.Members).To(HaveLen(3), "the TeamMembership should contain the SCIM and manual members")
+				sourcesByID := make(map[string][]greenhousev1alpha1.TeamMemberSource)
+				for _, member := range teamMembership.Spec.Members {
+					sourcesByID[member.ID] = member.Sources
+				}
+				g.Expect(sourcesByID).To(HaveKeyWithValue("I12345", ConsistOf(greenhousev1alpha1.TeamMemberSourceSCIM, greenhousev1alpha1.TeamMemberSourceManual)), "the user listed by both should have both sources")
+				g.Expect(sourcesByID).To(HaveKeyWithValue("I23456", ConsistOf(greenhousev1alpha1.TeamMemberSourceSCIM)), "the SCIM user should have the SCIM source")
+				g.Expect(sourcesByID).To(HaveKeyWithValue("I77777", ConsistOf(greenhousev1alpha1.TeamMemberSourceManual)), "the manual user should have the manual source")
+			}).Should(Succeed(), "the TeamMembership should be reconciled")
+
+			test.EventuallyDeleted(test.Ctx, setup.Client, team)
+		})
+
 		It("should delete the team after all", func() {
 			team := &greenhousev1alpha1.Team{}
 			Eventually(func(g Gomega) {
@@ -472,6 +497,37 @@ var _ = Describe("TeammembershipUpdaterController", Ordered, func() {
 			createTestOrgWithoutSCIMConfig(setup.Namespace())
 		})
 
+		It("should create a TeamMembership for a Team with manual members only", func() {
+			team := setup.CreateTeam(test.Ctx, firstTeamName, test.WithMembers(
+				greenhousev1alpha1.TeamMember{ID: "I77777", FirstName: "Manual", LastName: "User", Email: "manual.user@example.com"},
+			))
+
+			Eventually(func(g Gomega) {
+				teamMembership := &greenhousev1alpha1.TeamMembership{}
+				g.Expect(setup.Get(test.Ctx, client.ObjectKeyFromObject(team), teamMembership)).To(Succeed(), "there should be no error getting the TeamMembership")
+				g.Expect(teamMembership.Spec.Members).To(ConsistOf(greenhousev1alpha1.User{
+					ID: "I77777", FirstName: "Manual", LastName: "User", Email: "manual.user@example.com",
+					Sources: []greenhousev1alpha1.TeamMemberSource{greenhousev1alpha1.TeamMemberSourceManual},
+				}), "the TeamMembership should contain the manual member")
+				readyCondition := teamMembership.Status.GetConditionByType(greenhousev1alpha1.ReadyCondition)
+				g.Expect(readyCondition).ToNot(BeNil(), "the Ready condition should be set")
+				g.Expect(readyCondition.Status).To(Equal(metav1.ConditionTrue), "the TeamMembership should be ready without SCIM")
+			}).Should(Succeed(), "the TeamMembership should be created")
+
+			By("removing the manual members")
+			_, err := clientutil.Patch(test.Ctx, setup.Client, team, func() error {
+				team.Spec.Members = nil
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error updating the Team")
+			Eventually(func(g Gomega) {
+				err := setup.Get(test.Ctx, client.ObjectKeyFromObject(team), &greenhousev1alpha1.TeamMembership{})
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), "the TeamMembership should be deleted")
+			}).Should(Succeed(), "the TeamMembership should be deleted")
+
+			test.EventuallyDeleted(test.Ctx, setup.Client, team)
+		})
+
 		It("should reconcile Teams when Org's SCIM config changes", func() {
 			By("creating second Team")
 			setup.CreateTeam(test.Ctx, secondTeamName, test.WithMappedIDPGroup(otherValidIdpGroupName))
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package teamrbac
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+// driftCheckInterval is the interval after which the rbacv1 resources on the remote clusters are checked for drift.
+const driftCheckInterval = 10 * time.Minute
+
+// clusterRoleContent returns the part of the ClusterRole managed by Greenhouse.
+// The rules of an aggregated ClusterRole are managed by Kubernetes and therefore not part of it.
+func clusterRoleContent(cr *rbacv1.ClusterRole) any {
+	content := struct {
+		Labels          map[string]string
+		Rules           []rbacv1.PolicyRule
+		AggregationRule *rbacv1.AggregationRule
+	}{Labels: cr.Labels, AggregationRule: cr.AggregationRule}
+	if cr.AggregationRule == nil {
+		content.Rules = cr.Rules
+	}
+	return content
+}
+
+// bindingContent returns the part of a ClusterRoleBinding or RoleBinding managed by Greenhouse.
+func bindingContent(labels map[string]string, roleRef rbacv1.RoleRef, subjects []rbacv1.Subject) any {
+	return struct {
+		Labels   map[string]string
+		RoleRef  rbacv1.RoleRef
+		Subjects []rbacv1.Subject
+	}{labels, roleRef, subjects}
+}
+
+// hashContent returns the hex encoded sha256 digest of the JSON representation of the content.
+func hashContent(content any) string {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hasDrifted returns true if the remote object was modified or deleted since Greenhouse applied it.
+// The actual content of the remote object is compared with the desired content. A difference is not a drift if it is explained by a change
+// of the desired state, which is the case if the actual content still matches the hash annotated when it was last applied.
+// A deletion is only detected if the object is expected to exist.
+func hasDrifted(remote client.Object, actual, desired any, expected bool) bool {
+	if remote.GetResourceVersion() == "" {
+		return expected
+	}
+	if equality.Semantic.DeepEqual(actual, desired) {
+		return false
+	}
+	appliedHash, ok := remote.GetAnnotations()[greenhouseapis.RBACAppliedHashAnnotation]
+	return !ok || appliedHash != hashContent(actual)
+}
+
+// setAppliedHash annotates the remote object with the hash of the content applied by Greenhouse.
+func setAppliedHash(remote client.Object, content any) {
+	annotations := remote.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[greenhouseapis.RBACAppliedHashAnnotation] = hashContent(content)
+	remote.SetAnnotations(annotations)
+}
+
+// isPropagatedTo returns true if the TeamRoleBinding's rbacv1 resources were successfully reconciled on the cluster.
+func isPropagatedTo(trb *greenhousev1alpha1.TeamRoleBinding, cluster string) bool {
+	return slices.ContainsFunc(trb.Status.PropagationStatus, func(ps greenhousev1alpha1.TeamRoleBindingPropagationStatus) bool {
+		return ps.ClusterName == cluster && ps.Status == metav1.ConditionTrue
+	})
+}
+
+// isPropagatedToNamespace returns true if the TeamRoleBinding's RoleBinding was successfully reconciled in the namespace of the cluster.
+func isPropagatedToNamespace(trb *greenhousev1alpha1.TeamRoleBinding, cluster, namespace string) bool {
+	return slices.ContainsFunc(trb.Status.PropagationStatus, func(ps greenhousev1alpha1.TeamRoleBindingPropagationStatus) bool {
+		return ps.ClusterName == cluster && slices.ContainsFunc(ps.Namespaces, func(ns greenhousev1alpha1.NamespacePropagationStatus) bool {
+			return ns.Namespace == namespace && ns.Status == metav1.ConditionTrue
+		})
+	})
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package teamrbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+// namespaceWatcher watches the namespaces of remote clusters on behalf of TeamRoleBindings with a NamespaceSelector.
+// A generic event for the Cluster is emitted whenever a namespace is added, removed or relabeled.
+type namespaceWatcher struct {
+	mu sync.Mutex
+	// ctx is the context of the manager, the contexts of the watches are derived from it.
+	ctx context.Context
+	// watches contains the running watch per cluster.
+	watches map[types.NamespacedName]*clusterNamespaceWatch
+	events  chan event.GenericEvent
+}
+
+// clusterNamespaceWatch is the watch of the namespaces of a single cluster, stopped once no TeamRoleBinding uses it.
+type clusterNamespaceWatch struct {
+	cancel context.CancelFunc
+	// credentials identify the rest.Config the watch was started with, it is restarted if they are rotated.
+	credentials      string
+	teamRoleBindings map[types.NamespacedName]struct{}
+}
+
+func newNamespaceWatcher() *namespaceWatcher {
+	return &namespaceWatcher{
+		watches: make(map[types.NamespacedName]*clusterNamespaceWatch),
+		events:  make(chan event.GenericEvent),
+	}
+}
+
+// Start records the context of the manager and stops all watches once it is done.
+func (w *namespaceWatcher) Start(ctx context.Context) error {
+	w.mu.Lock()
+	w.ctx = ctx
+	w.mu.Unlock()
+	<-ctx.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for clusterKey, cw := range w.watches {
+		cw.cancel()
+		delete(w.watches, clusterKey)
+	}
+	return nil
+}
+
+// NeedLeaderElection returns false, so the watcher is started before the controllers using it.
+func (w *namespaceWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// watch ensures the namespaces of the clusters are watched for the TeamRoleBinding and stops the watches of other clusters no longer used.
+func (w *namespaceWatcher) watch(ctx context.Context, c client.Client, trb *greenhousev1alpha1.TeamRoleBinding, clusters []greenhousev1alpha1.Cluster) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.ctx == nil {
+		return errors.New("the namespace watcher is not started")
+	}
+	trbKey := client.ObjectKeyFromObject(trb)
+	wanted := make(map[types.NamespacedName]struct{}, len(clusters))
+	var errs []error
+	for _, cluster := range clusters {
+		clusterKey := client.ObjectKeyFromObject(&cluster)
+		wanted[clusterKey] = struct{}{}
+		cfg, err := clientutil.NewRestConfigFromCluster(ctx, c, &cluster)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.GetName(), err))
+			continue
+		}
+		credentials := restConfigCredentials(cfg)
+		teamRoleBindings := map[types.NamespacedName]struct{}{trbKey: {}}
+		if cw, ok := w.watches[clusterKey]; ok {
+			cw.teamRoleBindings[trbKey] = struct{}{}
+			if cw.credentials == credentials {
+				continue
+			}
+			// the credentials of the cluster were rotated, restart the watch
+			cw.cancel()
+			teamRoleBindings = cw.teamRoleBindings
+		}
+		clientset, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", cluster.GetName(), err))
+			continue
+		}
+		watchCtx, cancel := context.WithCancel(w.ctx)
+		w.watches[clusterKey] = &clusterNamespaceWatch{cancel: cancel, credentials: credentials, teamRoleBindings: teamRoleBindings}
+		go w.run(watchCtx, clientset, cluster.DeepCopy())
+		log.FromContext(ctx).Info("started watching namespaces", "cluster", cluster.GetName())
+	}
+	w.releaseExcept(ctx, trbKey, wanted)
+	return errors.Join(errs...)
+}
+
+// release stops the watches no longer used after the TeamRoleBinding stopped selecting namespaces or was deleted.
+func (w *namespaceWatcher) release(ctx context.Context, trb *greenhousev1alpha1.TeamRoleBinding) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.releaseExcept(ctx, client.ObjectKeyFromObject(trb), nil)
+}
+
+// releaseExcept removes the TeamRoleBinding from the watches of all clusters not contained in keep. The lock must be held.
+func (w *namespaceWatcher) releaseExcept(ctx context.Context, trbKey types.NamespacedName, keep map[types.NamespacedName]struct{}) {
+	for clusterKey, cw := range w.watches {
+		if _, ok := keep[clusterKey]; ok {
+			continue
+		}
+		delete(cw.teamRoleBindings, trbKey)
+		if len(cw.teamRoleBindings) == 0 {
+			cw.cancel()
+			delete(w.watches, clusterKey)
+			log.FromContext(ctx).Info("stopped watching namespaces", "cluster", clusterKey.Name)
+		}
+	}
+}
+
+// run informs about namespace changes on the cluster until the context is cancelled.
+func (w *namespaceWatcher) run(ctx context.Context, clientset kubernetes.Interface, cluster *greenhousev1alpha1.Cluster) {
+	informer := toolscache.NewSharedIndexInformer(&toolscache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return clientset.CoreV1().Namespaces().List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return clientset.CoreV1().Namespaces().Watch(ctx, opts)
+		},
+	}, &corev1.Namespace{}, 0, toolscache.Indexers{})
+
+	notify := func() {
+		select {
+		case w.events <- event.GenericEvent{Object: cluster}:
+		case <-ctx.Done():
+		}
+	}
+	_, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(_ any) { notify() },
+		UpdateFunc: func(oldObj, newObj any) {
+			oldNamespace, okOld := oldObj.(*corev1.Namespace)
+			newNamespace, okNew := newObj.(*corev1.Namespace)
+			if okOld && okNew && maps.Equal(oldNamespace.GetLabels(), newNamespace.GetLabels()) {
+				return
+			}
+			notify()
+		},
+		DeleteFunc: func(_ any) { notify() },
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to watch namespaces", "cluster", cluster.GetName())
+		return
+	}
+	informer.Run(ctx.Done())
+}
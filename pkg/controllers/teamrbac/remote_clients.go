@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package teamrbac
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+)
+
+// remoteClients caches the clients of the remote clusters. A client is recreated once the credentials of its cluster are rotated.
+type remoteClients struct {
+	mu      sync.Mutex
+	clients map[types.NamespacedName]*remoteClient
+}
+
+type remoteClient struct {
+	// credentials identify the rest.Config the client was created with.
+	credentials string
+	client      client.Client
+}
+
+func newRemoteClients() *remoteClients {
+	return &remoteClients{clients: make(map[types.NamespacedName]*remoteClient)}
+}
+
+// get returns the client of the cluster, the cached one is reused while the credentials of the cluster are unchanged.
+func (rc *remoteClients) get(ctx context.Context, c client.Client, cluster *greenhousev1alpha1.Cluster) (client.Client, error) {
+	cfg, err := clientutil.NewRestConfigFromCluster(ctx, c, cluster)
+	if err != nil {
+		return nil, err
+	}
+	key := client.ObjectKeyFromObject(cluster)
+	credentials := restConfigCredentials(cfg)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if cached, ok := rc.clients[key]; ok && cached.credentials == credentials {
+		return cached.client, nil
+	}
+	cl, err := clientutil.NewK8sClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	rc.clients[key] = &remoteClient{credentials: credentials, client: cl}
+	return cl, nil
+}
+
+// forget removes the client of a cluster which no longer exists.
+func (rc *remoteClients) forget(key types.NamespacedName) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.clients, key)
+}
+
+// restConfigCredentials identifies the endpoint and credentials of the rest.Config.
+func restConfigCredentials(cfg *rest.Config) string {
+	return cfg.Host + cfg.BearerToken + string(cfg.CertData) + string(cfg.KeyData)
+}
@@ -42,6 +42,7 @@ func TestRBACController(t *testing.T) {
 
 var _ = BeforeSuite(func() {
 	test.RegisterController("roleBindingController", (&TeamRoleBindingReconciler{}).SetupWithManager)
+	test.RegisterController("teamRoleController", (&TeamRoleReconciler{}).SetupWithManager)
 	test.RegisterWebhook("clusterWebhook", admission.SetupClusterWebhookWithManager)
 	test.RegisterWebhook("teamsWebhook", admission.SetupTeamWebhookWithManager)
 	test.RegisterWebhook("teamRoleBindingWebhook", admission.SetupTeamRoleBindingWebhookWithManager)
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package teamrbac
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/clientutil"
+	"github.com/cloudoperators/greenhouse/pkg/rbac"
+)
+
+// outdatedRequeueInterval is the interval after which a TeamRole with outdated ClusterRoles is checked again.
+const outdatedRequeueInterval = 30 * time.Second
+
+// TeamRoleReconciler reflects the usage of a TeamRole and the propagation of its rules to the clusters.
+type TeamRoleReconciler struct {
+	client.Client
+	remoteClients *remoteClients
+}
+
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=teamroles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=teamroles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=teamrolebindings,verbs=get;list;watch
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=clusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TeamRoleReconciler) SetupWithManager(name string, mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	r.remoteClients = newRemoteClients()
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&greenhousev1alpha1.TeamRole{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The aggregation relationships change with the Labels and AggregationRule of any TeamRole in the namespace.
+		Watches(&greenhousev1alpha1.TeamRole{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllTeamRolesInNamespace),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The usage and propagation change with the spec and status of the TeamRoleBindings.
+		Watches(&greenhousev1alpha1.TeamRoleBinding{}, handler.EnqueueRequestsFromMapFunc(r.enqueueReferencedTeamRole)).
+		Complete(r)
+}
+
+func (r *TeamRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	teamRole := new(greenhousev1alpha1.TeamRole)
+	if err := r.Get(ctx, req.NamespacedName, teamRole); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	teamRoles := new(greenhousev1alpha1.TeamRoleList)
+	if err := r.List(ctx, teamRoles, client.InNamespace(teamRole.GetNamespace())); err != nil {
+		return ctrl.Result{}, err
+	}
+	aggregatedTeamRoles, aggregatedBy, err := aggregationRelationships(teamRole, teamRoles.Items)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	teamRoleBindings := new(greenhousev1alpha1.TeamRoleBindingList)
+	if err := r.List(ctx, teamRoleBindings, client.InNamespace(teamRole.GetNamespace())); err != nil {
+		return ctrl.Result{}, err
+	}
+	var teamRoleBindingNames, boundClusters []string
+	for _, trb := range teamRoleBindings.Items {
+		if trb.Spec.TeamRoleRef != teamRole.GetName() {
+			continue
+		}
+		teamRoleBindingNames = append(teamRoleBindingNames, trb.GetName())
+		for _, ps := range trb.Status.PropagationStatus {
+			// clusters not ready, in maintenance or failing are reported by the TeamRoleBinding and would be requeued endlessly
+			if ps.Status != metav1.ConditionTrue {
+				continue
+			}
+			if !slices.Contains(boundClusters, ps.ClusterName) {
+				boundClusters = append(boundClusters, ps.ClusterName)
+			}
+		}
+	}
+	slices.Sort(teamRoleBindingNames)
+	slices.Sort(boundClusters)
+
+	clusters, outdatedClusters := r.checkClusterRoles(ctx, teamRole, boundClusters)
+	rulesPropagated := greenhousev1alpha1.TrueCondition(greenhousev1alpha1.RulesPropagated, "", "")
+	if len(outdatedClusters) > 0 {
+		rulesPropagated = greenhousev1alpha1.FalseCondition(greenhousev1alpha1.RulesPropagated, greenhousev1alpha1.RulesOutdated,
+			"ClusterRole is missing or outdated on clusters: "+strings.Join(outdatedClusters, ", "))
+	}
+
+	_, err = clientutil.PatchStatus(ctx, r.Client, teamRole, func() error {
+		teamRole.Status.TeamRoleBindings = teamRoleBindingNames
+		teamRole.Status.Clusters = clusters
+		teamRole.Status.AggregatedTeamRoles = aggregatedTeamRoles
+		teamRole.Status.AggregatedBy = aggregatedBy
+		teamRole.SetCondition(rulesPropagated)
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(outdatedClusters) > 0 {
+		return ctrl.Result{RequeueAfter: outdatedRequeueInterval}, nil
+	}
+	// The ClusterRoles on the remote clusters are not watched.
+	return ctrl.Result{RequeueAfter: wait.Jitter(driftCheckInterval, 0.1)}, nil
+}
+
+// checkClusterRoles returns the clusters the ClusterRole of the TeamRole exists on and the clusters it is missing on or does not contain the latest rules.
+func (r *TeamRoleReconciler) checkClusterRoles(ctx context.Context, teamRole *greenhousev1alpha1.TeamRole, clusterNames []string) (clusters, outdatedClusters []string) {
+	expectedHash := hashContent(clusterRoleContent(initRBACClusterRole(teamRole.DeepCopy())))
+	for _, clusterName := range clusterNames {
+		remoteCR, err := r.getRemoteClusterRole(ctx, teamRole, clusterName)
+		switch {
+		case apierrors.IsNotFound(err):
+			outdatedClusters = append(outdatedClusters, clusterName)
+			continue
+		case err != nil:
+			log.FromContext(ctx).Error(err, "failed to get ClusterRole", "cluster", clusterName)
+			outdatedClusters = append(outdatedClusters, clusterName)
+			continue
+		}
+		clusters = append(clusters, clusterName)
+		if remoteCR.GetAnnotations()[greenhouseapis.RBACAppliedHashAnnotation] != expectedHash {
+			outdatedClusters = append(outdatedClusters, clusterName)
+		}
+	}
+	return clusters, outdatedClusters
+}
+
+// getRemoteClusterRole returns the ClusterRole rendered from the TeamRole on the cluster.
+func (r *TeamRoleReconciler) getRemoteClusterRole(ctx context.Context, teamRole *greenhousev1alpha1.TeamRole, clusterName string) (*rbacv1.ClusterRole, error) {
+	cluster := new(greenhousev1alpha1.Cluster)
+	clusterKey := types.NamespacedName{Name: clusterName, Namespace: teamRole.GetNamespace()}
+	if err := r.Get(ctx, clusterKey, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.remoteClients.forget(clusterKey)
+		}
+		return nil, err
+	}
+	cl, err := r.remoteClients.get(ctx, r.Client, cluster)
+	if err != nil {
+		return nil, err
+	}
+	remoteCR := new(rbacv1.ClusterRole)
+	if err := cl.Get(ctx, types.NamespacedName{Name: teamRole.GetRBACName()}, remoteCR); err != nil {
+		return nil, err
+	}
+	return remoteCR, nil
+}
+
+// aggregationRelationships returns the TeamRoles aggregated into the TeamRole and the TeamRoles aggregating it.
+// The relationships are determined by matching the AggregationRule against the labels of the rendered ClusterRoles.
+func aggregationRelationships(teamRole *greenhousev1alpha1.TeamRole, teamRoles []greenhousev1alpha1.TeamRole) (aggregatedTeamRoles, aggregatedBy []string, err error) {
+	for _, other := range teamRoles {
+		if other.GetName() == teamRole.GetName() {
+			continue
+		}
+		selected, err := rbac.AggregationRuleSelects(teamRole.Spec.AggregationRule, rbac.TeamRoleLabels(&other))
+		if err != nil {
+			return nil, nil, err
+		}
+		if selected {
+			aggregatedTeamRoles = append(aggregatedTeamRoles, other.GetName())
+		}
+		selected, err = rbac.AggregationRuleSelects(other.Spec.AggregationRule, rbac.TeamRoleLabels(teamRole))
+		if err != nil {
+			return nil, nil, err
+		}
+		if selected {
+			aggregatedBy = append(aggregatedBy, other.GetName())
+		}
+	}
+	slices.Sort(aggregatedTeamRoles)
+	slices.Sort(aggregatedBy)
+	return aggregatedTeamRoles, aggregatedBy, nil
+}
+
+// enqueueAllTeamRolesInNamespace returns a list of reconcile requests for all TeamRoles in the namespace of the object.
+func (r *TeamRoleReconciler) enqueueAllTeamRolesInNamespace(ctx context.Context, o client.Object) []ctrl.Request {
+	teamRoles := new(greenhousev1alpha1.TeamRoleList)
+	if err := r.List(ctx, teamRoles, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := make([]ctrl.Request, len(teamRoles.Items))
+	for i, teamRole := range teamRoles.Items {
+		requests[i] = ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&teamRole)}
+	}
+	return requests
+}
+
+// enqueueReferencedTeamRole returns a reconcile request for the TeamRole referenced by the TeamRoleBinding.
+func (r *TeamRoleReconciler) enqueueReferencedTeamRole(_ context.Context, o client.Object) []ctrl.Request {
+	trb, ok := o.(*greenhousev1alpha1.TeamRoleBinding)
+	if !ok || trb.Spec.TeamRoleRef == "" {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: trb.Spec.TeamRoleRef, Namespace: trb.GetNamespace()}}}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package teamrbac
+
+import (
+	"time"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+// computeActiveCondition returns the Active condition of the TeamRoleBinding at the given time.
+// The returned duration is the time until the condition changes next, or zero if it does not change by itself.
+func computeActiveCondition(trb *greenhousev1alpha1.TeamRoleBinding, teamRole *greenhousev1alpha1.TeamRole, now time.Time) (greenhousev1alpha1.Condition, time.Duration) {
+	if trb.Spec.ExpiresAt != nil && !now.Before(trb.Spec.ExpiresAt.Time) {
+		return greenhousev1alpha1.FalseCondition(greenhousev1alpha1.Active, greenhousev1alpha1.Expired,
+			"Expired at "+trb.Spec.ExpiresAt.UTC().Format(time.RFC3339)), 0
+	}
+	if !trb.IsApproved(teamRole) {
+		message := "Waiting for the approval by another member of team " + trb.Spec.TeamRef
+		if approvedBy := trb.GetAnnotations()[greenhouseapis.TeamRoleBindingApprovedByAnnotation]; approvedBy != "" {
+			message = "The spec changed since the approval by " + approvedBy + ". " + message
+		}
+		return greenhousev1alpha1.FalseCondition(greenhousev1alpha1.Active, greenhousev1alpha1.ApprovalPending, message), untilExpiry(trb, now)
+	}
+	if trb.Spec.NotBefore != nil && now.Before(trb.Spec.NotBefore.Time) {
+		return greenhousev1alpha1.FalseCondition(greenhousev1alpha1.Active, greenhousev1alpha1.NotYetValid,
+			"Active from "+trb.Spec.NotBefore.UTC().Format(time.RFC3339)), trb.Spec.NotBefore.Sub(now)
+	}
+	if trb.Spec.ExpiresAt != nil {
+		return greenhousev1alpha1.TrueCondition(greenhousev1alpha1.Active, "",
+			"Expires at "+trb.Spec.ExpiresAt.UTC().Format(time.RFC3339)), untilExpiry(trb, now)
+	}
+	return greenhousev1alpha1.TrueCondition(greenhousev1alpha1.Active, "", ""), 0
+}
+
+// untilExpiry returns the time until the TeamRoleBinding expires, or zero if it does not expire.
+func untilExpiry(trb *greenhousev1alpha1.TeamRoleBinding, now time.Time) time.Duration {
+	if trb.Spec.ExpiresAt == nil {
+		return 0
+	}
+	return trb.Spec.ExpiresAt.Sub(now)
+}
+
+// earliestRequeue returns the shorter of the non-zero durations.
+func earliestRequeue(a, b time.Duration) time.Duration {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -17,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -24,11 +26,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
 	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
 	"github.com/cloudoperators/greenhouse/pkg/clientutil"
 	"github.com/cloudoperators/greenhouse/pkg/lifecycle"
+	"github.com/cloudoperators/greenhouse/pkg/rbac"
 )
 
 var exposedConditions = []greenhousev1alpha1.ConditionType{
@@ -40,11 +44,13 @@ var exposedConditions = []greenhousev1alpha1.ConditionType{
 // TeamRoleBindingReconciler reconciles a TeamRole object
 type TeamRoleBindingReconciler struct {
 	client.Client
-	recorder record.EventRecorder
+	recorder   record.EventRecorder
+	namespaces *namespaceWatcher
 }
 
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=teamrolebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=teamroles,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=greenhouse.sap,resources=teammemberships,verbs=get;list;watch
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=teamrolebindings/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=greenhouse.sap,resources=teamrolebindings/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
@@ -53,6 +59,10 @@ type TeamRoleBindingReconciler struct {
 func (r *TeamRoleBindingReconciler) SetupWithManager(name string, mgr ctrl.Manager) error {
 	r.Client = mgr.GetClient()
 	r.recorder = mgr.GetEventRecorderFor(name)
+	r.namespaces = newNamespaceWatcher()
+	if err := mgr.Add(r.namespaces); err != nil {
+		return err
+	}
 
 	// index RoleBindings by the RoleRef field for faster lookups
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &greenhousev1alpha1.TeamRoleBinding{}, greenhouseapis.RolebindingRoleRefField, func(rawObj client.Object) []string {
@@ -84,9 +94,14 @@ func (r *TeamRoleBindingReconciler) SetupWithManager(name string, mgr ctrl.Manag
 			handler.EnqueueRequestsFromMapFunc(r.enqueueTeamRoleBindingsFor)).
 		Watches(&greenhousev1alpha1.Team{},
 			handler.EnqueueRequestsFromMapFunc(r.enqueueTeamRoleBindingsFor)).
-		// Reconcile TeamRoleBindings for all Cluster label changes in the same namespace
+		// The members of the Teams are subjects of TeamRoleBindings including the team members
+		Watches(&greenhousev1alpha1.TeamMembership{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueTeamRoleBindingsForTeamMembership)).
+		// Reconcile TeamRoleBindings for all Cluster label and spec changes in the same namespace
 		Watches(&greenhousev1alpha1.Cluster{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllTeamRoleBindingsInNamespace),
-			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.GenerationChangedPredicate{}))).
+		// Reconcile TeamRoleBindings with a NamespaceSelector for namespace changes on the remote clusters
+		WatchesRawSource(source.Channel(r.namespaces.events, handler.EnqueueRequestsFromMapFunc(r.enqueueTeamRoleBindingsSelectingNamespaces))).
 		Complete(r)
 }
 
@@ -137,10 +152,41 @@ func (r *TeamRoleBindingReconciler) EnsureCreated(ctx context.Context, resource
 		trb.SetCondition(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.ClusterListEmpty, "", ""))
 	}
 
-	err = r.cleanupResources(ctx, trb, clusters)
+	now := time.Now()
+	activeCondition, untilActiveChanges := computeActiveCondition(trb, teamRole, now)
+	trb.SetCondition(activeCondition)
+	if !activeCondition.IsTrue() {
+		// the rbacv1 resources are removed from all clusters while the TeamRoleBinding is not active
+		clusters.Items = nil
+	}
+
+	// changes to clusters in maintenance are postponed until the maintenance ends
+	maintenance := clientutil.NewMaintenanceTracker(now)
+	clusters.Items = slices.DeleteFunc(clusters.Items, func(c greenhousev1alpha1.Cluster) bool {
+		return skipClusterInMaintenance(trb, &c, maintenance)
+	})
+
+	err = r.cleanupResources(ctx, trb, clusters, maintenance)
+	maintenance.SetCondition(trb)
 	if err != nil {
 		return ctrl.Result{}, lifecycle.Failed, err
 	}
+	requeueAfter := earliestRequeue(maintenance.RequeueAfter(), untilActiveChanges)
+
+	if !activeCondition.IsTrue() {
+		r.namespaces.release(ctx, trb)
+		trb.SetCondition(greenhousev1alpha1.FalseCondition(greenhousev1alpha1.RBACReady, activeCondition.Reason, activeCondition.Message))
+		return ctrl.Result{RequeueAfter: requeueAfter}, lifecycle.Success, nil
+	}
+
+	// RoleBindings are created and removed as namespaces matching the NamespaceSelector appear and disappear
+	if trb.Spec.NamespaceSelector != nil {
+		if err := r.namespaces.watch(ctx, r.Client, trb, clusters.Items); err != nil {
+			log.FromContext(ctx).Error(err, "failed to watch the namespaces of the clusters")
+		}
+	} else {
+		r.namespaces.release(ctx, trb)
+	}
 
 	team, err := getTeam(ctx, r.Client, trb)
 	if err != nil {
@@ -152,11 +198,17 @@ func (r *TeamRoleBindingReconciler) EnsureCreated(ctx context.Context, resource
 		return ctrl.Result{}, lifecycle.Failed, err
 	}
 
-	err = r.doReconcile(ctx, teamRole, clusters, trb, team)
+	subjects, err := r.subjects(ctx, trb, team)
+	if err != nil {
+		return ctrl.Result{}, lifecycle.Failed, err
+	}
+
+	err = r.doReconcile(ctx, teamRole, clusters, trb, subjects)
 	if err != nil {
 		return ctrl.Result{}, lifecycle.Failed, err
 	}
-	return ctrl.Result{}, lifecycle.Success, err
+	// Requeue to correct drift of the rbacv1 resources in the remote clusters.
+	return ctrl.Result{RequeueAfter: earliestRequeue(requeueAfter, wait.Jitter(driftCheckInterval, 0.1))}, lifecycle.Success, err
 }
 
 // EnsureDeleted - removes the TeamRoleBinding's rbacv1 resources from all clusters.
@@ -178,7 +230,16 @@ func (r *TeamRoleBindingReconciler) EnsureDeleted(ctx context.Context, resource
 		return ctrl.Result{}, lifecycle.Failed, err
 	}
 
+	r.namespaces.release(ctx, trb)
+	maintenance := clientutil.NewMaintenanceTracker(time.Now())
 	for _, cluster := range clusters.Items {
+		if cluster.SkipsRemoteCleanup() {
+			trb.RemovePropagationStatus(cluster.GetName())
+			continue
+		}
+		if skipClusterInMaintenance(trb, &cluster, maintenance) {
+			continue
+		}
 		if err := r.cleanupCluster(ctx, trb, &cluster); err != nil {
 			r.recorder.Eventf(trb, corev1.EventTypeWarning, greenhousev1alpha1.FailedDeleteEvent, "Failed to remove resources for %s from cluster %s", trb.GetName(), cluster.GetName())
 			continue
@@ -191,11 +252,12 @@ func (r *TeamRoleBindingReconciler) EnsureDeleted(ctx context.Context, resource
 		r.recorder.Eventf(trb, corev1.EventTypeNormal, greenhousev1alpha1.SuccessfulDeletedEvent, "Deleted TeamRoleBinding %s from all clusters", trb.GetName())
 		return ctrl.Result{}, lifecycle.Success, nil
 	}
-	return ctrl.Result{}, lifecycle.Pending, nil
+	maintenance.SetCondition(trb)
+	return ctrl.Result{RequeueAfter: maintenance.RequeueAfter()}, lifecycle.Pending, nil
 }
 
 // doReconcile reconciles the TeamRoleBinding's rbacv1 resources on all relevant clusters
-func (r *TeamRoleBindingReconciler) doReconcile(ctx context.Context, teamRole *greenhousev1alpha1.TeamRole, clusters *greenhousev1alpha1.ClusterList, trb *greenhousev1alpha1.TeamRoleBinding, team *greenhousev1alpha1.Team) error {
+func (r *TeamRoleBindingReconciler) doReconcile(ctx context.Context, teamRole *greenhousev1alpha1.TeamRole, clusters *greenhousev1alpha1.ClusterList, trb *greenhousev1alpha1.TeamRoleBinding, subjects []rbacv1.Subject) error {
 	failedClusters := []string{}
 	cr := initRBACClusterRole(teamRole)
 
@@ -210,7 +272,11 @@ func (r *TeamRoleBindingReconciler) doReconcile(ctx context.Context, teamRole *g
 			continue
 		}
 
-		if err := reconcileClusterRole(ctx, remoteRestClient, &cluster, cr); err != nil {
+		// rbacv1 resources which were successfully reconciled before are expected to exist, otherwise they were deleted on the cluster
+		wasPropagated := isPropagatedTo(trb, cluster.GetName())
+		var driftedObjects []string
+		drifted, err := reconcileClusterRole(ctx, remoteRestClient, &cluster, cr, wasPropagated)
+		if err != nil {
 			r.recorder.Eventf(trb, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "Failed to reconcile ClusterRole %s in cluster %s", cr.GetName(), cluster.GetName())
 			trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionFalse, greenhousev1alpha1.ClusterRoleFailed, err.Error())
 			if !slices.Contains(failedClusters, cluster.GetName()) {
@@ -218,11 +284,16 @@ func (r *TeamRoleBindingReconciler) doReconcile(ctx context.Context, teamRole *g
 			}
 			continue
 		}
+		if drifted {
+			driftedObjects = append(driftedObjects, "ClusterRole "+cr.GetName())
+		}
 
+		var namespaceStatus []greenhousev1alpha1.NamespacePropagationStatus
 		switch isClusterScoped(trb) {
 		case true:
-			crb := rbacClusterRoleBinding(trb, cr, team)
-			if err := reconcileClusterRoleBinding(ctx, remoteRestClient, &cluster, crb); err != nil {
+			crb := rbacClusterRoleBinding(trb, cr, subjects)
+			drifted, err := reconcileClusterRoleBinding(ctx, remoteRestClient, &cluster, crb, wasPropagated)
+			if err != nil {
 				r.recorder.Eventf(trb, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "Failed to reconcile ClusterRoleBinding %s in cluster %s", crb.GetName(), cluster.GetName())
 				trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionFalse, greenhousev1alpha1.RoleBindingFailed, err.Error())
 				if !slices.Contains(failedClusters, cluster.GetName()) {
@@ -230,27 +301,56 @@ func (r *TeamRoleBindingReconciler) doReconcile(ctx context.Context, teamRole *g
 				}
 				continue
 			}
-			trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionTrue, greenhousev1alpha1.RBACReconciled, "")
+			if drifted {
+				driftedObjects = append(driftedObjects, "ClusterRoleBinding "+crb.GetName())
+			}
 		default:
+			namespaces, err := targetNamespaces(ctx, remoteRestClient, trb)
+			if err != nil {
+				r.recorder.Eventf(trb, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "Failed to list the namespaces in cluster %s", cluster.GetName())
+				trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionFalse, greenhousev1alpha1.RoleBindingFailed, err.Error())
+				if !slices.Contains(failedClusters, cluster.GetName()) {
+					failedClusters = append(failedClusters, cluster.GetName())
+				}
+				continue
+			}
 			errorMesages := []string{}
-			for _, namespace := range trb.Spec.Namespaces {
-				rbacRoleBinding := rbacRoleBinding(trb, cr, team, namespace)
+			namespaceStatus = make([]greenhousev1alpha1.NamespacePropagationStatus, 0, len(namespaces))
+			for _, namespace := range namespaces {
+				rbacRoleBinding := rbacRoleBinding(trb, cr, subjects, namespace)
 
-				if err := reconcileRoleBinding(ctx, remoteRestClient, &cluster, rbacRoleBinding, trb.Spec.CreateNamespaces); err != nil {
+				drifted, err := reconcileRoleBinding(ctx, remoteRestClient, &cluster, rbacRoleBinding, trb.Spec.CreateNamespaces, isPropagatedToNamespace(trb, cluster.GetName(), namespace))
+				if err != nil {
 					r.recorder.Eventf(trb, corev1.EventTypeWarning, greenhousev1alpha1.FailedEvent, "Failed to reconcile RoleBinding %s in cluster/namespace %s/%s: ", rbacRoleBinding.GetName(), cluster.GetName(), namespace)
 					if !slices.Contains(failedClusters, cluster.GetName()) {
 						failedClusters = append(failedClusters, cluster.GetName())
 					}
 					errorMesages = append(errorMesages, err.Error())
+					namespaceStatus = append(namespaceStatus, greenhousev1alpha1.NamespacePropagationStatus{Namespace: namespace,
+						Condition: greenhousev1alpha1.FalseCondition(greenhousev1alpha1.RBACReady, greenhousev1alpha1.RoleBindingFailed, err.Error())})
+					continue
+				}
+				if drifted {
+					driftedObjects = append(driftedObjects, "RoleBinding "+namespace+"/"+rbacRoleBinding.GetName())
 				}
+				namespaceStatus = append(namespaceStatus, greenhousev1alpha1.NamespacePropagationStatus{Namespace: namespace,
+					Condition: greenhousev1alpha1.TrueCondition(greenhousev1alpha1.RBACReady, greenhousev1alpha1.RBACReconciled, "")})
 			}
 			if len(errorMesages) > 0 {
 				trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionFalse, greenhousev1alpha1.RoleBindingFailed, "Failed to reconcile RoleBindings: "+strings.Join(errorMesages, ", "))
+				trb.SetNamespacePropagationStatus(cluster.GetName(), namespaceStatus)
 				continue
 			}
 		}
 
-		trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionTrue, greenhousev1alpha1.RBACReconciled, "")
+		if len(driftedObjects) > 0 {
+			message := "Restored rbacv1 resources modified or deleted on the cluster: " + strings.Join(driftedObjects, ", ")
+			r.recorder.Eventf(trb, corev1.EventTypeWarning, greenhousev1alpha1.RBACDriftDetectedEvent, "%s in cluster %s", message, cluster.GetName())
+			trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionTrue, greenhousev1alpha1.RBACDriftCorrected, message)
+		} else {
+			trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionTrue, greenhousev1alpha1.RBACReconciled, "")
+		}
+		trb.SetNamespacePropagationStatus(cluster.GetName(), namespaceStatus)
 	}
 
 	if len(failedClusters) > 0 {
@@ -286,8 +386,8 @@ func (r *TeamRoleBindingReconciler) combineClusterLists(ctx context.Context, nam
 }
 
 // cleanupResources removes rbacv1 resources from all clusters that are no longer matching the TeamRoleBinding's clusterSelector/clusterName
-// if the Cluster is not ready, the TeamRoleBinding's status will be updated accordingly but no resources will be removed
-func (r *TeamRoleBindingReconciler) cleanupResources(ctx context.Context, trb *greenhousev1alpha1.TeamRoleBinding, clusters *greenhousev1alpha1.ClusterList) error {
+// if the Cluster is not ready or in maintenance, the TeamRoleBinding's status will be updated accordingly but no resources will be removed
+func (r *TeamRoleBindingReconciler) cleanupResources(ctx context.Context, trb *greenhousev1alpha1.TeamRoleBinding, clusters *greenhousev1alpha1.ClusterList, maintenance *clientutil.MaintenanceTracker) error {
 	for _, s := range trb.Status.PropagationStatus {
 		// remove rbac for all clusters no longer matching the clusterSelector
 		if !slices.ContainsFunc(clusters.Items, func(c greenhousev1alpha1.Cluster) bool { return c.GetName() == s.ClusterName }) {
@@ -301,10 +401,18 @@ func (r *TeamRoleBindingReconciler) cleanupResources(ctx context.Context, trb *g
 			if err != nil {
 				return err
 			}
+			if cluster.SkipsRemoteCleanup() {
+				// cluster is deleted without removing the RBAC from it
+				trb.RemovePropagationStatus(s.ClusterName)
+				continue
+			}
 			if !cluster.Status.StatusConditions.IsReadyTrue() {
 				trb.SetPropagationStatus(s.ClusterName, metav1.ConditionFalse, greenhousev1alpha1.ClusterConnectionFailed, "Cluster is not ready")
 				continue
 			}
+			if skipClusterInMaintenance(trb, cluster, maintenance) {
+				continue
+			}
 			if err = r.cleanupCluster(ctx, trb, cluster); err != nil {
 				return err
 			}
@@ -347,10 +455,12 @@ func (r *TeamRoleBindingReconciler) cleanupClusterNamespaces(ctx context.Context
 		return err
 	}
 
+	namespaces, err := targetNamespaces(ctx, cl, trb)
+	if err != nil {
+		return err
+	}
 	roleBindingsToDelete := slices.DeleteFunc(roleBindings.Items, func(roleBinding rbacv1.RoleBinding) bool {
-		return slices.ContainsFunc(trb.Spec.Namespaces, func(namespace string) bool {
-			return roleBinding.Namespace == namespace
-		})
+		return slices.Contains(namespaces, roleBinding.Namespace)
 	})
 	if len(roleBindingsToDelete) == 0 {
 		return nil
@@ -427,8 +537,8 @@ func initRBACClusterRole(teamRole *greenhousev1alpha1.TeamRole) *rbacv1.ClusterR
 	return clusterRole
 }
 
-// rbacRoleBinding creates a rbacv1.RoleBinding for a rbacv1.ClusterRole, Team and Namespace
-func rbacRoleBinding(trb *greenhousev1alpha1.TeamRoleBinding, clusterRole *rbacv1.ClusterRole, team *greenhousev1alpha1.Team, namespace string) *rbacv1.RoleBinding {
+// rbacRoleBinding creates a rbacv1.RoleBinding for a rbacv1.ClusterRole, Subjects and Namespace
+func rbacRoleBinding(trb *greenhousev1alpha1.TeamRoleBinding, clusterRole *rbacv1.ClusterRole, subjects []rbacv1.Subject, namespace string) *rbacv1.RoleBinding {
 	return &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      trb.GetRBACName(),
@@ -440,12 +550,12 @@ func rbacRoleBinding(trb *greenhousev1alpha1.TeamRoleBinding, clusterRole *rbacv
 			Kind:     clusterRole.Kind,
 			Name:     clusterRole.GetName(),
 		},
-		Subjects: generateSubjects(trb.Spec.Usernames, team.Spec.MappedIDPGroup),
+		Subjects: subjects,
 	}
 }
 
-// rbacClusterRoleBinding creates a rbacv1.ClusterRoleBinding for a rbacv1.ClusterRole, Team and Namespace
-func rbacClusterRoleBinding(trb *greenhousev1alpha1.TeamRoleBinding, clusterRole *rbacv1.ClusterRole, team *greenhousev1alpha1.Team) *rbacv1.ClusterRoleBinding {
+// rbacClusterRoleBinding creates a rbacv1.ClusterRoleBinding for a rbacv1.ClusterRole and Subjects
+func rbacClusterRoleBinding(trb *greenhousev1alpha1.TeamRoleBinding, clusterRole *rbacv1.ClusterRole, subjects []rbacv1.Subject) *rbacv1.ClusterRoleBinding {
 	return &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: trb.GetRBACName(),
@@ -459,12 +569,12 @@ func rbacClusterRoleBinding(trb *greenhousev1alpha1.TeamRoleBinding, clusterRole
 			Kind:     clusterRole.Kind,
 			Name:     clusterRole.GetName(),
 		},
-		Subjects: generateSubjects(trb.Spec.Usernames, team.Spec.MappedIDPGroup),
+		Subjects: subjects,
 	}
 }
 
-// generateSubjects returns a list of subjects with mappedIDPGroup as a rbacv1.GroupKind, and any usernames as rbacv1.UserKind
-func generateSubjects(usernames []string, mappedIDPGroup string) []rbacv1.Subject {
+// generateSubjects returns a list of subjects with the mappedIDPGroups as rbacv1.GroupKind, and any usernames as rbacv1.UserKind
+func generateSubjects(usernames, mappedIDPGroups []string) []rbacv1.Subject {
 	var subjects []rbacv1.Subject
 	for _, username := range usernames {
 		subjects = append(subjects, rbacv1.Subject{
@@ -474,11 +584,57 @@ func generateSubjects(usernames []string, mappedIDPGroup string) []rbacv1.Subjec
 		})
 	}
 
-	return append(subjects, rbacv1.Subject{
-		APIGroup: rbacv1.GroupName,
-		Kind:     rbacv1.GroupKind,
-		Name:     mappedIDPGroup,
-	})
+	for _, mappedIDPGroup := range mappedIDPGroups {
+		subjects = append(subjects, rbacv1.Subject{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     mappedIDPGroup,
+		})
+	}
+	return subjects
+}
+
+// subjects returns the subjects of the rbacv1 bindings for the TeamRoleBinding.
+// These are the usernames and the IdP group of the Team, if included the IdP groups of all descendant Teams,
+// and if included the IDs of the members of these Teams as usernames.
+func (r *TeamRoleBindingReconciler) subjects(ctx context.Context, trb *greenhousev1alpha1.TeamRoleBinding, team *greenhousev1alpha1.Team) ([]rbacv1.Subject, error) {
+	teamNames := []string{team.GetName()}
+	mappedIDPGroups := []string{team.Spec.MappedIDPGroup}
+	if trb.Spec.IncludeChildTeams {
+		teams := new(greenhousev1alpha1.TeamList)
+		if err := r.List(ctx, teams, client.InNamespace(trb.GetNamespace())); err != nil {
+			return nil, err
+		}
+		descendants := rbac.DescendantTeams(team.GetName(), teams.Items)
+		teamNames = append(teamNames, descendants...)
+		for _, child := range teams.Items {
+			if !slices.Contains(descendants, child.GetName()) || child.Spec.MappedIDPGroup == "" || slices.Contains(mappedIDPGroups, child.Spec.MappedIDPGroup) {
+				continue
+			}
+			mappedIDPGroups = append(mappedIDPGroups, child.Spec.MappedIDPGroup)
+		}
+	}
+
+	usernames := slices.Clone(trb.Spec.Usernames)
+	if trb.Spec.IncludeTeamMembers {
+		for _, teamName := range teamNames {
+			// The TeamMembership is named after the Team.
+			teamMembership := new(greenhousev1alpha1.TeamMembership)
+			err := r.Get(ctx, types.NamespacedName{Name: teamName, Namespace: trb.GetNamespace()}, teamMembership)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			for _, member := range teamMembership.Spec.Members {
+				if !slices.Contains(usernames, member.ID) {
+					usernames = append(usernames, member.ID)
+				}
+			}
+		}
+	}
+	return generateSubjects(usernames, mappedIDPGroups), nil
 }
 
 // getTeamRole retrieves the Role referenced by the given RoleBinding in the RoleBinding's Namespace
@@ -509,41 +665,49 @@ func getTeam(ctx context.Context, c client.Client, teamRoleBinding *greenhousev1
 }
 
 // reconcileClusterRole creates or updates a ClusterRole in the Cluster the given client.Client is created for
-func reconcileClusterRole(ctx context.Context, cl client.Client, c *greenhousev1alpha1.Cluster, cr *rbacv1.ClusterRole) error {
+// It returns true if the ClusterRole was modified or deleted on the cluster, while it was expected to exist in the desired state.
+func reconcileClusterRole(ctx context.Context, cl client.Client, c *greenhousev1alpha1.Cluster, cr *rbacv1.ClusterRole, expected bool) (bool, error) {
 	remoteCR := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: cr.Name,
 		},
 	}
+	var drifted bool
 	result, err := clientutil.CreateOrPatch(ctx, cl, remoteCR, func() error {
+		drifted = hasDrifted(remoteCR, clusterRoleContent(remoteCR), clusterRoleContent(cr), expected)
 		remoteCR.Labels = cr.Labels
 		remoteCR.Rules = cr.Rules
 		remoteCR.AggregationRule = cr.AggregationRule
+		setAppliedHash(remoteCR, clusterRoleContent(cr))
 		return nil
 	})
 
 	if err != nil {
-		return err
+		return false, err
 	}
 	log.FromContext(ctx).Info(fmt.Sprintf("%s ClusterRoleBinding", result), "clusterRole", cr.GetName(), "cluster", c.GetName())
-	return nil
+	return drifted, nil
 }
 
 // reconcileClusterRoleBinding creates or updates a ClusterRoleBinding in the Cluster the given client.Client is created for
-func reconcileClusterRoleBinding(ctx context.Context, cl client.Client, c *greenhousev1alpha1.Cluster, crb *rbacv1.ClusterRoleBinding) error {
+// It returns true if the ClusterRoleBinding was modified or deleted on the cluster, while it was expected to exist in the desired state.
+func reconcileClusterRoleBinding(ctx context.Context, cl client.Client, c *greenhousev1alpha1.Cluster, crb *rbacv1.ClusterRoleBinding, expected bool) (bool, error) {
 	remoteCRB := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: crb.Name,
 		},
 	}
+	var drifted bool
 	result, err := clientutil.CreateOrPatch(ctx, cl, remoteCRB, func() error {
+		drifted = hasDrifted(remoteCRB, bindingContent(remoteCRB.Labels, remoteCRB.RoleRef, remoteCRB.Subjects), bindingContent(crb.Labels, crb.RoleRef, crb.Subjects), expected)
 		remoteCRB.Labels = crb.Labels
 		remoteCRB.RoleRef = crb.RoleRef
 		remoteCRB.Subjects = crb.Subjects
+		setAppliedHash(remoteCRB, bindingContent(crb.Labels, crb.RoleRef, crb.Subjects))
 		return nil
 	})
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	switch result {
@@ -554,11 +718,12 @@ func reconcileClusterRoleBinding(ctx context.Context, cl client.Client, c *green
 	case clientutil.OperationResultUpdated:
 		log.FromContext(ctx).Info("updated ClusterRoleBinding", "clusterRoleBinding", crb.GetName(), "cluster", c.GetName())
 	}
-	return nil
+	return drifted, nil
 }
 
 // reconcileRoleBinding creates or updates a RoleBinding in the Cluster the given client.Client is created for
-func reconcileRoleBinding(ctx context.Context, cl client.Client, c *greenhousev1alpha1.Cluster, rb *rbacv1.RoleBinding, createNamespaces bool) error {
+// It returns true if the RoleBinding was modified or deleted on the cluster, while it was expected to exist in the desired state.
+func reconcileRoleBinding(ctx context.Context, cl client.Client, c *greenhousev1alpha1.Cluster, rb *rbacv1.RoleBinding, createNamespaces, expected bool) (bool, error) {
 	remoteRB := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      rb.Name,
@@ -566,21 +731,24 @@ func reconcileRoleBinding(ctx context.Context, cl client.Client, c *greenhousev1
 		},
 	}
 
+	var drifted bool
 	result, err := clientutil.CreateOrPatch(ctx, cl, remoteRB, func() error {
+		drifted = hasDrifted(remoteRB, bindingContent(remoteRB.Labels, remoteRB.RoleRef, remoteRB.Subjects), bindingContent(rb.Labels, rb.RoleRef, rb.Subjects), expected)
 		remoteRB.Labels = rb.Labels
 		remoteRB.RoleRef = rb.RoleRef
 		remoteRB.Subjects = rb.Subjects
+		setAppliedHash(remoteRB, bindingContent(rb.Labels, rb.RoleRef, rb.Subjects))
 		return nil
 	})
 	if err != nil {
 		if createNamespaces && apierrors.IsNotFound(err) {
 			err := cl.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: rb.Namespace}})
 			if err != nil {
-				return err
+				return false, err
 			}
-			return errors.New("failed to create RoleBinding, created missing namespace")
+			return false, errors.New("failed to create RoleBinding, created missing namespace")
 		} else {
-			return err
+			return false, err
 		}
 	}
 
@@ -592,7 +760,7 @@ func reconcileRoleBinding(ctx context.Context, cl client.Client, c *greenhousev1
 	case clientutil.OperationResultUpdated:
 		log.FromContext(ctx).Info("updated RoleBinding", "roleBinding", rb.GetName(), "cluster", c.GetName(), "namespace", rb.GetNamespace())
 	}
-	return nil
+	return drifted, nil
 }
 
 // deleteAllDeployedRoleBindings deletes all RoleBindings deployed to a remote cluster.
@@ -657,36 +825,78 @@ func (r TeamRoleBindingReconciler) deleteClusterRole(ctx context.Context, cl cli
 	return nil
 }
 
-// enqueueTeamRoleBindingsFor enqueues all TeamRoleBindings that are referenced by the given TeamRole or Team
+// enqueueTeamRoleBindingsFor enqueues all TeamRoleBindings that are referenced by the given TeamRole or Team.
+// For a Team, the TeamRoleBindings referencing its ancestor Teams are enqueued as well, as they may include the Team as a child team.
 func (r *TeamRoleBindingReconciler) enqueueTeamRoleBindingsFor(ctx context.Context, o client.Object) []ctrl.Request {
 	fieldRef := ""
+	names := []string{o.GetName()}
 	// determine the field to select TeamRoleBindings by
-	switch o.(type) {
+	switch obj := o.(type) {
 	case *greenhousev1alpha1.TeamRole:
 		fieldRef = greenhouseapis.RolebindingRoleRefField
 	case *greenhousev1alpha1.Team:
 		fieldRef = greenhouseapis.RolebindingTeamRefField
+		// the parent is taken from the object, as it differs from the cache for the old object of an update
+		if obj.Spec.ParentTeamRef != "" {
+			teams := &greenhousev1alpha1.TeamList{}
+			if err := r.Client.List(ctx, teams, client.InNamespace(o.GetNamespace())); err != nil {
+				return []ctrl.Request{}
+			}
+			names = append(names, obj.Spec.ParentTeamRef)
+			names = append(names, rbac.AncestorTeams(obj.Spec.ParentTeamRef, teams.Items)...)
+		}
 	default:
 		return []ctrl.Request{}
 	}
-	listOpts := &client.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector(fieldRef, o.GetName()),
-		Namespace:     o.GetNamespace(),
+
+	var requests []ctrl.Request
+	for _, name := range names {
+		listOpts := &client.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector(fieldRef, name),
+			Namespace:     o.GetNamespace(),
+		}
+		// list all referenced TeamRoleBindings
+		teamRoleBindings := &greenhousev1alpha1.TeamRoleBindingList{}
+		if err := r.Client.List(ctx, teamRoleBindings, listOpts); err != nil {
+			return []ctrl.Request{}
+		}
+
+		// return a list of reconcile.Requests for the list of referenced TeamRoleBindings
+		for _, trb := range teamRoleBindings.Items {
+			if name != o.GetName() && !trb.Spec.IncludeChildTeams {
+				continue
+			}
+			requests = append(requests, ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      trb.GetName(),
+					Namespace: trb.GetNamespace(),
+				},
+			})
+		}
 	}
-	// list all referenced TeamRoleBindings
-	teamRoleBindings := &greenhousev1alpha1.TeamRoleBindingList{}
-	if err := r.Client.List(ctx, teamRoleBindings, listOpts); err != nil {
+	return requests
+}
+
+// enqueueTeamRoleBindingsForTeamMembership enqueues all TeamRoleBindings referencing the Team of the TeamMembership or its ancestors.
+func (r *TeamRoleBindingReconciler) enqueueTeamRoleBindingsForTeamMembership(ctx context.Context, o client.Object) []ctrl.Request {
+	// The TeamMembership is named after the Team.
+	team := &greenhousev1alpha1.Team{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: o.GetName(), Namespace: o.GetNamespace()}, team); err != nil {
 		return []ctrl.Request{}
 	}
+	return r.enqueueTeamRoleBindingsFor(ctx, team)
+}
 
-	// return a list of reconcile.Requests for the list of referenced TeamRoleBindings
-	requests := make([]ctrl.Request, len(teamRoleBindings.Items))
-	for i, trb := range teamRoleBindings.Items {
-		requests[i] = ctrl.Request{
-			NamespacedName: types.NamespacedName{
-				Name:      trb.GetName(),
-				Namespace: trb.GetNamespace(),
-			},
+// enqueueTeamRoleBindingsSelectingNamespaces returns a list of reconcile requests for all TeamRoleBindings with a NamespaceSelector in the namespace of the given Cluster.
+func (r *TeamRoleBindingReconciler) enqueueTeamRoleBindingsSelectingNamespaces(ctx context.Context, cluster client.Object) []ctrl.Request {
+	var teamRoleBindings = new(greenhousev1alpha1.TeamRoleBindingList)
+	if err := r.List(ctx, teamRoleBindings, client.InNamespace(cluster.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []ctrl.Request
+	for _, trb := range teamRoleBindings.Items {
+		if trb.Spec.NamespaceSelector != nil {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&trb)})
 		}
 	}
 	return requests
@@ -712,7 +922,32 @@ func listTeamRoleBindingsAsReconcileRequests(ctx context.Context, c client.Clien
 
 // isClusterScoped returns true if the TeamRoleBinding will create ClusterRoleBindings
 func isClusterScoped(trb *greenhousev1alpha1.TeamRoleBinding) bool {
-	return len(trb.Spec.Namespaces) == 0
+	return len(trb.Spec.Namespaces) == 0 && trb.Spec.NamespaceSelector == nil
+}
+
+// targetNamespaces returns the namespaces on the remote cluster the TeamRoleBinding's RoleBindings are created in.
+// These are either the namespaces from the spec or the existing namespaces matching the NamespaceSelector.
+func targetNamespaces(ctx context.Context, cl client.Client, trb *greenhousev1alpha1.TeamRoleBinding) ([]string, error) {
+	if trb.Spec.NamespaceSelector == nil {
+		return trb.Spec.Namespaces, nil
+	}
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(trb.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	var namespaceList = new(corev1.NamespaceList)
+	if err := cl.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+		return nil, err
+	}
+	namespaces := make([]string, 0, len(namespaceList.Items))
+	for _, namespace := range namespaceList.Items {
+		if namespace.DeletionTimestamp != nil {
+			continue
+		}
+		namespaces = append(namespaces, namespace.GetName())
+	}
+	slices.Sort(namespaces)
+	return namespaces, nil
 }
 
 // isRoleReferenced checks if the given TeamRoleBinding's TeamRole is still referenced by any Role or ClusterRole
@@ -743,6 +978,7 @@ func isRoleReferenced(ctx context.Context, c client.Client, teamRoleBinding *gre
 // listClusters returns the list of Clusters that match the TeamRoleBinding's ClusterSelector or ClusterName
 // If the ClusterName or ClusterSelector does not return any cluster, an empty ClusterList is returned without error
 // If a cluster in the list is not ready, then it is removed from the list and the PropagationStatus updated
+// Clusters being deleted are removed from the list, so the RBAC is cleaned up from them
 func (r *TeamRoleBindingReconciler) listClusters(ctx context.Context, trb *greenhousev1alpha1.TeamRoleBinding) (*greenhousev1alpha1.ClusterList, error) {
 	if trb.Spec.ClusterName != "" {
 		cluster := new(greenhousev1alpha1.Cluster)
@@ -753,6 +989,10 @@ func (r *TeamRoleBindingReconciler) listClusters(ctx context.Context, trb *green
 			}
 			return nil, err
 		}
+		if cluster.DeletionTimestamp != nil {
+			// RBAC is removed from clusters being deleted
+			return &greenhousev1alpha1.ClusterList{}, nil
+		}
 		return &greenhousev1alpha1.ClusterList{Items: []greenhousev1alpha1.Cluster{*cluster}}, nil
 	}
 
@@ -764,8 +1004,11 @@ func (r *TeamRoleBindingReconciler) listClusters(ctx context.Context, trb *green
 	if err := r.List(ctx, clusters, client.InNamespace(trb.GetNamespace()), client.MatchingLabelsSelector{Selector: clusterSelector}); err != nil {
 		return nil, err
 	}
-	// remove clusters which are not ready
+	// remove clusters which are being deleted or not ready
 	clusters.Items = slices.DeleteFunc(clusters.Items, func(c greenhousev1alpha1.Cluster) bool {
+		if c.DeletionTimestamp != nil {
+			return true
+		}
 		if !c.Status.StatusConditions.IsReadyTrue() {
 			trb.SetPropagationStatus(c.GetName(), metav1.ConditionFalse, greenhousev1alpha1.ClusterConnectionFailed, "Cluster is not ready")
 			return true
@@ -775,6 +1018,15 @@ func (r *TeamRoleBindingReconciler) listClusters(ctx context.Context, trb *green
 	return clusters, nil
 }
 
+// skipClusterInMaintenance returns true if the cluster is in maintenance and updates the TeamRoleBinding's PropagationStatus accordingly
+func skipClusterInMaintenance(trb *greenhousev1alpha1.TeamRoleBinding, cluster *greenhousev1alpha1.Cluster, maintenance *clientutil.MaintenanceTracker) bool {
+	if !maintenance.Skip(cluster) {
+		return false
+	}
+	trb.SetPropagationStatus(cluster.GetName(), metav1.ConditionFalse, greenhousev1alpha1.ClusterInMaintenanceReason, "Cluster is in maintenance")
+	return true
+}
+
 // initTeamRoleBindingStatus ensures that all required conditions are present in the TeamRoleBinding's Status
 func initTeamRoleBindingStatus(trb *greenhousev1alpha1.TeamRoleBinding) {
 	for _, ct := range exposedConditions {
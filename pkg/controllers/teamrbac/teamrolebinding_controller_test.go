@@ -6,6 +6,7 @@ package teamrbac
 import (
 	"context"
 	"slices"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -425,6 +426,56 @@ var _ = Describe("Validate ClusterRole & RoleBinding on Remote Cluster", Ordered
 		})
 	})
 
+	Context("When creating a Greenhouse TeamRoleBinding with a namespaceSelector on the central cluster", func() {
+		It("Should create and remove RoleBindings as matching namespaces appear and disappear on the remote cluster", func() {
+			By("creating a labeled namespace on the remote cluster")
+			selectedNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "selected-a", Labels: map[string]string{"rbac-selector": "true"}}}
+			Expect(clusterAKubeClient.Create(test.Ctx, selectedNamespace)).To(Succeed(), "there should be no error creating the namespace on the remote cluster")
+
+			By("creating a TeamRoleBinding on the central cluster")
+			trb := setup.CreateTeamRoleBinding(test.Ctx, "test-rolebinding",
+				test.WithTeamRoleRef(teamRoleUT.Name),
+				test.WithTeamRef(teamUT.Name),
+				test.WithClusterName(clusterA.Name),
+				test.WithNamespaceSelector(metav1.LabelSelector{MatchLabels: map[string]string{"rbac-selector": "true"}}))
+			trbKey := types.NamespacedName{Name: trb.Name, Namespace: trb.Namespace}
+
+			By("validating the RoleBinding is created in the matching namespace")
+			remoteRoleBinding := &rbacv1.RoleBinding{}
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, types.NamespacedName{Name: trb.GetRBACName(), Namespace: selectedNamespace.Name}, remoteRoleBinding)).To(Succeed(), "there should be no error getting the RoleBinding from the remote cluster")
+			}).Should(Succeed(), "the RoleBinding should be created in the matching namespace")
+
+			By("creating another labeled namespace on the remote cluster")
+			otherNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "selected-b", Labels: map[string]string{"rbac-selector": "true"}}}
+			Expect(clusterAKubeClient.Create(test.Ctx, otherNamespace)).To(Succeed(), "there should be no error creating the namespace on the remote cluster")
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, types.NamespacedName{Name: trb.GetRBACName(), Namespace: otherNamespace.Name}, remoteRoleBinding)).To(Succeed(), "there should be no error getting the RoleBinding from the remote cluster")
+			}).Should(Succeed(), "the RoleBinding should be created in the new matching namespace")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(test.Ctx, trbKey, trb)).To(Succeed(), "there should be no error getting the TeamRoleBinding")
+				g.Expect(trb.Status.PropagationStatus).To(ContainElement(And(
+					HaveField("ClusterName", clusterA.Name),
+					HaveField("Namespaces", ConsistOf(HaveField("Namespace", selectedNamespace.Name), HaveField("Namespace", otherNamespace.Name))),
+				)), "the TeamRoleBinding should report the status per namespace")
+			}).Should(Succeed(), "the TeamRoleBindings status should reflect the current status")
+
+			By("removing the label from the namespace on the remote cluster")
+			_, err := clientutil.Patch(test.Ctx, clusterAKubeClient, otherNamespace, func() error {
+				delete(otherNamespace.Labels, "rbac-selector")
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred(), "there should be no error removing the label from the namespace")
+			Eventually(func() bool {
+				err := clusterAKubeClient.Get(test.Ctx, types.NamespacedName{Name: trb.GetRBACName(), Namespace: otherNamespace.Name}, remoteRoleBinding)
+				return apierrors.IsNotFound(err)
+			}).Should(BeTrue(), "the RoleBinding should be removed from the namespace no longer matching")
+
+			By("cleaning up the test")
+			test.EventuallyDeleted(test.Ctx, test.K8sClient, trb)
+		})
+	})
+
 	Context("When creating a Greenhouse TeamRoleBinding with non-existing namespaces on the central cluster", func() {
 		It("Should fail to create ClusterRole and RoleBinding on the remote cluster", func() {
 			By("creating a TeamRoleBinding on the central cluster")
@@ -516,6 +567,103 @@ var _ = Describe("Validate ClusterRole & RoleBinding on Remote Cluster", Ordered
 		})
 	})
 
+	Context("When creating a Greenhouse TeamRoleBinding including child teams", func() {
+		It("Should add the IdP groups of the descendant teams to the ClusterRoleBinding on the remote cluster", func() {
+			By("creating a child and grandchild of the Team")
+			childTeam := setup.CreateTeam(test.Ctx, "test-child-team", test.WithMappedIDPGroup("test-child-idp-group"), test.WithParentTeamRef(teamUT.Name))
+			grandchildTeam := setup.CreateTeam(test.Ctx, "test-grandchild-team", test.WithMappedIDPGroup("test-grandchild-idp-group"), test.WithParentTeamRef(childTeam.Name))
+
+			By("creating a TeamRoleBinding including child teams")
+			trb := setup.CreateTeamRoleBinding(test.Ctx, "test-teamrolebinding",
+				test.WithTeamRoleRef(teamRoleUT.Name),
+				test.WithTeamRef(teamUT.Name),
+				test.WithClusterName(clusterA.Name),
+				test.WithIncludeChildTeams(true))
+
+			By("validating the subjects of the ClusterRoleBinding on the remote cluster")
+			remoteClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+			remoteRoleBindingName := types.NamespacedName{Name: greenhouseapis.RBACPrefix + trb.Name}
+			groupSubjects := func() []string {
+				var groups []string
+				for _, subject := range remoteClusterRoleBinding.Subjects {
+					if subject.Kind == rbacv1.GroupKind {
+						groups = append(groups, subject.Name)
+					}
+				}
+				return groups
+			}
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, remoteRoleBindingName, remoteClusterRoleBinding)).To(Succeed(), "there should be no error getting the ClusterRoleBinding from the Remote Cluster")
+				g.Expect(groupSubjects()).To(ConsistOf(testTeamIDPGroup, "test-child-idp-group", "test-grandchild-idp-group"), "the IdP groups of the descendant teams should be subjects")
+			}).Should(Succeed(), "the ClusterRoleBinding should include the child teams")
+
+			By("removing the grandchild from the hierarchy")
+			_, err := clientutil.Patch(test.Ctx, test.K8sClient, grandchildTeam, func() error {
+				grandchildTeam.Spec.ParentTeamRef = ""
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error updating the grandchild team")
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, remoteRoleBindingName, remoteClusterRoleBinding)).To(Succeed(), "there should be no error getting the ClusterRoleBinding from the Remote Cluster")
+				g.Expect(groupSubjects()).To(ConsistOf(testTeamIDPGroup, "test-child-idp-group"), "the IdP group of the grandchild team should be removed")
+			}).Should(Succeed(), "the ClusterRoleBinding should be updated")
+
+			By("cleaning up the test")
+			test.EventuallyDeleted(test.Ctx, test.K8sClient, trb)
+			test.EventuallyDeleted(test.Ctx, test.K8sClient, grandchildTeam)
+			test.EventuallyDeleted(test.Ctx, test.K8sClient, childTeam)
+		})
+	})
+
+	Context("When creating a Greenhouse TeamRoleBinding including team members", func() {
+		It("Should add the members of the Team as users to the ClusterRoleBinding on the remote cluster", func() {
+			By("creating the TeamMembership of the Team")
+			teamMembership := &greenhousev1alpha1.TeamMembership{
+				ObjectMeta: metav1.ObjectMeta{Name: teamUT.Name, Namespace: setup.Namespace()},
+				Spec: greenhousev1alpha1.TeamMembershipSpec{
+					Members: []greenhousev1alpha1.User{{ID: "test-member", FirstName: "Test", LastName: "Member", Email: "test.member@example.com",
+						Sources: []greenhousev1alpha1.TeamMemberSource{greenhousev1alpha1.TeamMemberSourceManual}}},
+				},
+			}
+			Expect(test.K8sClient.Create(test.Ctx, teamMembership)).To(Succeed(), "there should be no error creating the TeamMembership")
+
+			By("creating a TeamRoleBinding including team members")
+			trb := setup.CreateTeamRoleBinding(test.Ctx, "test-teamrolebinding",
+				test.WithTeamRoleRef(teamRoleUT.Name),
+				test.WithTeamRef(teamUT.Name),
+				test.WithClusterName(clusterA.Name),
+				test.WithUsernames([]string{"test-user"}),
+				test.WithIncludeTeamMembers(true))
+
+			By("validating the subjects of the ClusterRoleBinding on the remote cluster")
+			remoteClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+			remoteRoleBindingName := types.NamespacedName{Name: greenhouseapis.RBACPrefix + trb.Name}
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, remoteRoleBindingName, remoteClusterRoleBinding)).To(Succeed(), "there should be no error getting the ClusterRoleBinding from the Remote Cluster")
+				g.Expect(remoteClusterRoleBinding.Subjects).To(ContainElements(
+					rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "test-user"},
+					rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "test-member"},
+					rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: testTeamIDPGroup},
+				), "the members of the Team should be subjects")
+			}).Should(Succeed(), "the ClusterRoleBinding should include the team members")
+
+			By("removing the member from the TeamMembership")
+			_, err := clientutil.Patch(test.Ctx, test.K8sClient, teamMembership, func() error {
+				teamMembership.Spec.Members = nil
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error updating the TeamMembership")
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, remoteRoleBindingName, remoteClusterRoleBinding)).To(Succeed(), "there should be no error getting the ClusterRoleBinding from the Remote Cluster")
+				g.Expect(remoteClusterRoleBinding.Subjects).ToNot(ContainElement(HaveField("Name", "test-member")), "the removed member should not be a subject")
+			}).Should(Succeed(), "the ClusterRoleBinding should be updated")
+
+			By("cleaning up the test")
+			test.EventuallyDeleted(test.Ctx, test.K8sClient, trb)
+			test.EventuallyDeleted(test.Ctx, test.K8sClient, teamMembership)
+		})
+	})
+
 	Context("When creating Greenhouse TeamRoleBindings with and without namespaces on the central cluster", func() {
 		It("Should create a ClusterRole, ClusterRoleBinding and TeamRoleBinding on the remote cluster", func() {
 			By("creating a TeamRoleBinding without Namespaces on the central cluster")
@@ -707,6 +855,40 @@ var _ = Describe("Validate ClusterRole & RoleBinding on Remote Cluster", Ordered
 				g.Expect(aggregateClusterRole.AggregationRule).To(Equal(trAggregate.Spec.AggregationRule), "the Aggregate ClusterRole should have the same AggregationRule as the Base ClusterRole")
 				return true
 			}).Should(BeTrue(), "the ClusterRole should exists and have the correct rules")
+
+			By("validating the status of the Base TeamRole")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(test.Ctx, client.ObjectKeyFromObject(teamRoleUT), teamRoleUT)).To(Succeed(), "there should be no error getting the Base TeamRole")
+				g.Expect(teamRoleUT.Status.TeamRoleBindings).To(ConsistOf(trbBase.Name), "the Base TeamRole should list the TeamRoleBinding referencing it")
+				g.Expect(teamRoleUT.Status.Clusters).To(ConsistOf(clusterA.Name), "the Base TeamRole should list the cluster its ClusterRole exists on")
+				g.Expect(teamRoleUT.Status.AggregatedBy).To(ContainElement(trAggregate.Name), "the Base TeamRole should list the TeamRole aggregating it")
+				rulesPropagated := teamRoleUT.Status.GetConditionByType(greenhousev1alpha1.RulesPropagated)
+				g.Expect(rulesPropagated).ToNot(BeNil(), "RulesPropagated condition on TeamRole should not be nil")
+				g.Expect(rulesPropagated.Status).To(Equal(metav1.ConditionTrue), "RulesPropagated condition on TeamRole should be True")
+			}).Should(Succeed(), "the Base TeamRole status should reflect its usage")
+
+			By("validating the status of the Aggregate TeamRole")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(test.Ctx, client.ObjectKeyFromObject(trAggregate), trAggregate)).To(Succeed(), "there should be no error getting the Aggregate TeamRole")
+				g.Expect(trAggregate.Status.TeamRoleBindings).To(ConsistOf(trbAggregate.Name), "the Aggregate TeamRole should list the TeamRoleBinding referencing it")
+				g.Expect(trAggregate.Status.AggregatedTeamRoles).To(ContainElement(teamRoleUT.Name), "the Aggregate TeamRole should list the TeamRole aggregated into it")
+			}).Should(Succeed(), "the Aggregate TeamRole status should reflect the aggregation")
+
+			By("updating the rules of the Base TeamRole")
+			_, err := clientutil.Patch(test.Ctx, k8sClient, teamRoleUT, func() error {
+				teamRoleUT.Spec.Rules[0].Verbs = append(teamRoleUT.Spec.Rules[0].Verbs, "watch")
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error updating the Base TeamRole")
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, baseClusterRoleName, baseClusterRole)).To(Succeed(), "there should be no error getting the ClusterRole from the Remote Cluster")
+				g.Expect(baseClusterRole.Rules).To(Equal(teamRoleUT.Spec.Rules), "the Base ClusterRole should contain the updated rules")
+				g.Expect(k8sClient.Get(test.Ctx, client.ObjectKeyFromObject(teamRoleUT), teamRoleUT)).To(Succeed(), "there should be no error getting the Base TeamRole")
+				rulesPropagated := teamRoleUT.Status.GetConditionByType(greenhousev1alpha1.RulesPropagated)
+				g.Expect(rulesPropagated).ToNot(BeNil(), "RulesPropagated condition on TeamRole should not be nil")
+				g.Expect(rulesPropagated.Status).To(Equal(metav1.ConditionTrue), "RulesPropagated condition on TeamRole should be True")
+			}).Should(Succeed(), "the updated rules should be propagated to the remote cluster")
+
 			By("cleaning up the test")
 			test.EventuallyDeleted(test.Ctx, test.K8sClient, trbBase)
 			test.EventuallyDeleted(test.Ctx, test.K8sClient, trbAggregate)
@@ -766,6 +948,27 @@ var _ = Describe("Validate ClusterRole & RoleBinding on Remote Cluster", Ordered
 				return g.Expect(remoteClusterRoleBinding.Subjects).To(Equal(expected))
 			}).Should(BeTrue(), "the remote RoleBinding should eventually be reconciled")
 
+			By("validating the drift is reported in the TeamRoleBinding's status")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(test.Ctx, types.NamespacedName{Name: trb.Name, Namespace: trb.Namespace}, trb)).To(Succeed(), "there should be no error getting the TeamRoleBinding")
+				g.Expect(trb.Status.PropagationStatus).To(ContainElement(And(
+					HaveField("ClusterName", clusterA.Name),
+					HaveField("Condition.Reason", greenhousev1alpha1.RBACDriftCorrected),
+					HaveField("Condition.Message", ContainSubstring("ClusterRoleBinding "+trb.GetRBACName())),
+				)), "the restored ClusterRoleBinding should be reported")
+			}).Should(Succeed(), "the TeamRoleBindings status should reflect the drift")
+
+			By("deleting the ClusterRoleBinding on the remote cluster")
+			Expect(clusterAKubeClient.Delete(test.Ctx, remoteClusterRoleBinding)).To(Succeed(), "there should be no error deleting the ClusterRoleBinding on the remote cluster")
+			_, err := clientutil.Patch(test.Ctx, k8sClient, trb, func() error {
+				trb.SetLabels(map[string]string{"foo": "baz"})
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error updating the TeamRoleBinding on the central cluster")
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, remoteClusterRoleBindingName, remoteClusterRoleBinding)).To(Succeed(), "there should be no error getting the ClusterRoleBinding from the remote cluster")
+			}).Should(Succeed(), "the deleted ClusterRoleBinding should be restored")
+
 			By("cleaning up the test")
 			test.EventuallyDeleted(test.Ctx, test.K8sClient, trb)
 		})
@@ -1013,4 +1216,40 @@ var _ = Describe("Validate ClusterRole & RoleBinding on Remote Cluster", Ordered
 			test.EventuallyDeleted(test.Ctx, test.K8sClient, trb)
 		})
 	})
+
+	Context("When creating a time-bound TeamRoleBinding", func() {
+		It("should remove the ClusterRoleBinding from the remote cluster once it expired", func() {
+			By("creating a TeamRoleBinding expiring in a few seconds")
+			trb := setup.CreateTeamRoleBinding(test.Ctx, "test-rolebinding",
+				test.WithTeamRoleRef(teamRoleUT.Name),
+				test.WithTeamRef(teamUT.Name),
+				test.WithClusterName(clusterA.Name),
+				test.WithExpiresAt(time.Now().Add(5*time.Second)))
+			trbKey := types.NamespacedName{Name: trb.Name, Namespace: trb.Namespace}
+
+			By("validating the ClusterRoleBinding is created on the remote cluster")
+			remoteRoleBinding := &rbacv1.ClusterRoleBinding{}
+			remoteRoleBindingName := types.NamespacedName{Name: trb.GetRBACName()}
+			Eventually(func(g Gomega) {
+				g.Expect(clusterAKubeClient.Get(test.Ctx, remoteRoleBindingName, remoteRoleBinding)).To(Succeed(), "there should be no error getting the ClusterRoleBinding from the remote cluster")
+			}).Should(Succeed(), "the ClusterRoleBinding should be created on the remote cluster")
+
+			By("validating the ClusterRoleBinding is removed from the remote cluster after the expiry")
+			Eventually(func() bool {
+				err := clusterAKubeClient.Get(test.Ctx, remoteRoleBindingName, remoteRoleBinding)
+				return apierrors.IsNotFound(err)
+			}).WithTimeout(30*time.Second).Should(BeTrue(), "the ClusterRoleBinding should be removed from the remote cluster")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(test.Ctx, trbKey, trb)).To(Succeed(), "there should be no error getting the TeamRoleBinding")
+				g.Expect(trb.Status.PropagationStatus).To(BeEmpty(), "the TeamRoleBinding should not be propagated to any cluster")
+				activeCondition := trb.Status.GetConditionByType(greenhousev1alpha1.Active)
+				g.Expect(activeCondition).ToNot(BeNil(), "Active condition on TeamRoleBinding should not be nil")
+				g.Expect(activeCondition.Status).To(Equal(metav1.ConditionFalse), "Active condition on TeamRoleBinding should be False")
+				g.Expect(activeCondition.Reason).To(Equal(greenhousev1alpha1.Expired), "Active condition on TeamRoleBinding should have the reason Expired")
+			}).Should(Succeed(), "the TeamRoleBindings status should reflect the expiry")
+
+			By("cleaning up the test")
+			test.EventuallyDeleted(test.Ctx, test.K8sClient, trb)
+		})
+	})
 })
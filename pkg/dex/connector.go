@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/dexidp/dex/connector"
 	"github.com/dexidp/dex/connector/oidc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -127,6 +129,18 @@ func (c *oidcConnector) Refresh(ctx context.Context, s connector.Scopes, identit
 	return identity, err
 }
 
+// teamClaims returns the group claims of the members of the team, team:<name> and <category>:<name> for every greenhouse.sap/<category> label.
+func teamClaims(team greenhousesapv1alpha1.Team) []string {
+	claims := []string{"team:" + team.Name}
+	for labelKey := range team.Labels {
+		if strings.HasPrefix(labelKey, greenhouseLabelKeyPrefix) {
+			teamCategoryName := strings.TrimPrefix(labelKey, greenhouseLabelKeyPrefix)
+			claims = append(claims, fmt.Sprintf("%s:%s", teamCategoryName, team.Name))
+		}
+	}
+	return claims
+}
+
 func (c *oidcConnector) getGroups(organization string, upstreamGroups []string, ctx context.Context) ([]string, error) {
 	var groups []string
 	groups = append(groups, rbac.OrganizationRoleName(c.id))
@@ -142,12 +156,14 @@ func (c *oidcConnector) getGroups(organization string, upstreamGroups []string,
 		return nil, err
 	}
 	for _, team := range teamList.Items {
-		teamNamesByIDPGroups[team.Spec.MappedIDPGroup] = append(teamNamesByIDPGroups[team.Spec.MappedIDPGroup], "team:"+team.Name)
-		for labelKey := range team.Labels {
-			if strings.HasPrefix(labelKey, greenhouseLabelKeyPrefix) {
-				teamCategoryName := strings.TrimPrefix(labelKey, greenhouseLabelKeyPrefix)
-				teamNamesByIDPGroups[team.Spec.MappedIDPGroup] = append(teamNamesByIDPGroups[team.Spec.MappedIDPGroup], fmt.Sprintf("%s:%s", teamCategoryName, team.Name))
+		teamNamesByIDPGroups[team.Spec.MappedIDPGroup] = append(teamNamesByIDPGroups[team.Spec.MappedIDPGroup], teamClaims(team)...)
+		// members of a team are members of its parent teams
+		for _, ancestor := range rbac.AncestorTeams(team.Name, teamList.Items) {
+			ancestorTeam := greenhousesapv1alpha1.Team{ObjectMeta: metav1.ObjectMeta{Name: ancestor}}
+			if idx := slices.IndexFunc(teamList.Items, func(t greenhousesapv1alpha1.Team) bool { return t.Name == ancestor }); idx >= 0 {
+				ancestorTeam = teamList.Items[idx]
 			}
+			teamNamesByIDPGroups[team.Spec.MappedIDPGroup] = append(teamNamesByIDPGroups[team.Spec.MappedIDPGroup], teamClaims(ancestorTeam)...)
 		}
 	}
 
@@ -162,7 +178,11 @@ func (c *oidcConnector) getGroups(organization string, upstreamGroups []string,
 	for _, group := range upstreamGroups {
 		teamNameGroup, ok := teamNamesByIDPGroups[group]
 		if ok {
-			groups = append(groups, teamNameGroup...)
+			for _, teamName := range teamNameGroup {
+				if !slices.Contains(groups, teamName) {
+					groups = append(groups, teamName)
+				}
+			}
 		}
 		roleName, ok := roleNamesByIDPGroups[group]
 		if ok {
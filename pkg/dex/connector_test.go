@@ -103,6 +103,9 @@ var _ = BeforeSuite(func() {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-team-3",
 			Namespace: test.TestNamespace,
+			Labels: map[string]string{
+				"greenhouse.sap/test-team-category-3": "true",
+			},
 		},
 		Spec: greenhousesapv1alpha1.TeamSpec{
 			Description:    "Test Team 3",
@@ -127,6 +130,24 @@ var _ = BeforeSuite(func() {
 		},
 	}, &client.CreateOptions{})
 	Expect(err).ToNot(HaveOccurred(), "There should be no error when creating a team")
+
+	err = test.K8sClient.Create(context.TODO(), &greenhousesapv1alpha1.Team{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: greenhousesapv1alpha1.GroupVersion.Group,
+			Kind:       "Team",
+		},
+
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-team-5",
+			Namespace: test.TestNamespace,
+		},
+		Spec: greenhousesapv1alpha1.TeamSpec{
+			Description:    "Test Team 5",
+			MappedIDPGroup: "IDP_GROUP_NAME_MATCHING_TEAM_5",
+			ParentTeamRef:  "test-team-3",
+		},
+	}, &client.CreateOptions{})
+	Expect(err).ToNot(HaveOccurred(), "There should be no error when creating a team")
 })
 
 var _ = AfterSuite(func() {
@@ -139,6 +160,14 @@ var _ = Describe("Getting groups for token", func() {
 		connectorOIDC := oidcConnector{conn: new(connector.Connector), logger: logger, client: test.K8sClient, id: test.TestNamespace}
 		groups, err := connectorOIDC.getGroups(test.TestNamespace, groupMock, context.TODO())
 		Expect(err).ToNot(HaveOccurred(), "There should be no error when getting groups")
-		Expect(groups).To(Equal([]string{"organization:" + test.TestNamespace, "team:test-team-1", "test-team-category-1:test-team-1", "role:" + test.TestNamespace + ":admin", "team:test-team-2", "test-team-category-2:test-team-2", "team:test-team-3", "team:test-team-4"}), "The groups should be correct")
+		Expect(groups).To(Equal([]string{"organization:" + test.TestNamespace, "team:test-team-1", "test-team-category-1:test-team-1", "role:" + test.TestNamespace + ":admin", "team:test-team-2", "test-team-category-2:test-team-2", "team:test-team-3", "test-team-category-3:test-team-3", "team:test-team-4"}), "The groups should be correct")
+	})
+
+	It("Should return the groups of the parent teams", func() {
+		logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+		connectorOIDC := oidcConnector{conn: new(connector.Connector), logger: logger, client: test.K8sClient, id: test.TestNamespace}
+		groups, err := connectorOIDC.getGroups(test.TestNamespace, []string{"IDP_GROUP_NAME_MATCHING_TEAM_5"}, context.TODO())
+		Expect(err).ToNot(HaveOccurred(), "There should be no error when getting groups")
+		Expect(groups).To(Equal([]string{"organization:" + test.TestNamespace, "team:test-team-5", "team:test-team-3", "test-team-category-3:test-team-3"}), "The groups should contain the parent team and its categories")
 	})
 })
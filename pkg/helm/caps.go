@@ -4,12 +4,17 @@
 package helm
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chartutil"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
 )
 
 // Override the default capabilities with the detected ones of the current cluster.
@@ -35,6 +40,23 @@ func verifyKubeVersionIsCompatible(helmChart *chart.Chart, caps *chartutil.Capab
 	return nil
 }
 
+// KubeVersionConstraintForPluginDefinition loads the Helm chart of the PluginDefinition and returns its kubeVersion constraint.
+// PluginDefinitions with manifests and charts requiring Git credentials, which are only available in the namespace of a Plugin, have no constraint.
+func KubeVersionConstraintForPluginDefinition(ctx context.Context, local client.Client, pluginDefinition *greenhousev1alpha1.PluginDefinition) (string, error) {
+	reference := pluginDefinition.Spec.HelmChart
+	if reference == nil || (reference.Git != nil && reference.Git.SecretRef != nil) {
+		return "", nil
+	}
+	helmChart, err := loadHelmChart(ctx, local, new(action.ChartPathOptions), reference, "", settings)
+	if err != nil {
+		return "", err
+	}
+	if helmChart.Metadata == nil {
+		return "", nil
+	}
+	return helmChart.Metadata.KubeVersion, nil
+}
+
 func getCapabilities(cfg *action.Configuration) (*chartutil.Capabilities, error) {
 	if cfg.Capabilities != nil {
 		return cfg.Capabilities, nil
@@ -0,0 +1,471 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+// defaultChartCacheMaxBytes is the default size limit of the chart cache.
+const defaultChartCacheMaxBytes int64 = 512 << 20
+
+// ChartCacheMaxBytes is configured via a flag and limits the size of all chart archives kept in the chart cache.
+// A value of zero or less disables the cache.
+var ChartCacheMaxBytes = defaultChartCacheMaxBytes
+
+var (
+	sharedChartCache     *chartCache
+	sharedChartCacheOnce sync.Once
+)
+
+// getChartCache returns the chart cache shared by all template, diff, install and upgrade operations.
+// It is initialized lazily as ChartCacheMaxBytes is only known after flags were parsed.
+func getChartCache() *chartCache {
+	sharedChartCacheOnce.Do(func() {
+		sharedChartCache = newChartCache(filepath.Join(settings.RepositoryCache, "greenhouse-charts"), ChartCacheMaxBytes)
+	})
+	return sharedChartCache
+}
+
+// chartCacheIndexFile is the file in the cache directory persisting the index across restarts.
+const chartCacheIndexFile = "index.json"
+
+// chartCache is a content-addressed cache of chart archives.
+// Archives are stored by their sha256 digest and indexed by repository, name and version of the chart.
+// The cache only holds the archives. Charts are loaded from the archive on every use as Helm actions modify the loaded chart.
+type chartCache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	size     int64
+	// index maps the repository/name:version of a chart to the digest of its archive.
+	index map[string]string
+	blobs map[string]*chartCacheBlob
+	group singleflight.Group
+}
+
+// chartCacheBlob is a chart archive stored in the chart cache.
+type chartCacheBlob struct {
+	path     string
+	size     int64
+	lastUsed time.Time
+	keys     map[string]struct{}
+	// refs counts the callers currently reading the archive. Archives in use are not evicted.
+	refs int
+	// removed marks an archive removed from the cache while in use. Its file is deleted once it is released.
+	removed bool
+}
+
+// newChartCache returns a chart cache in the given directory. Archives stored in the directory before are reused.
+func newChartCache(dir string, maxBytes int64) *chartCache {
+	c := &chartCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		index:    make(map[string]string),
+		blobs:    make(map[string]*chartCacheBlob),
+	}
+	if maxBytes > 0 {
+		c.load()
+	}
+	return c
+}
+
+// load rebuilds the cache from the archives and the persisted index in the cache directory.
+// Archives not referenced by the index are accounted for and evicted first. Leftover temporary files are removed.
+func (c *chartCache) load() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case strings.Contains(name, ".tmp-"):
+			_ = os.Remove(filepath.Join(c.dir, name))
+		case strings.HasSuffix(name, ".tgz"):
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			c.blobs[strings.TrimSuffix(name, ".tgz")] = &chartCacheBlob{
+				path:     filepath.Join(c.dir, name),
+				size:     info.Size(),
+				lastUsed: info.ModTime(),
+				keys:     make(map[string]struct{}),
+			}
+			c.size += info.Size()
+		}
+	}
+	index := make(map[string]string)
+	if data, err := os.ReadFile(filepath.Join(c.dir, chartCacheIndexFile)); err == nil {
+		_ = json.Unmarshal(data, &index)
+	}
+	for key, digest := range index {
+		blob, ok := c.blobs[digest]
+		if !ok {
+			continue
+		}
+		c.index[key] = digest
+		blob.keys[key] = struct{}{}
+	}
+	// Archives without keys cannot be looked up anymore and are evicted first.
+	for _, blob := range c.blobs {
+		if len(blob.keys) == 0 {
+			blob.lastUsed = time.Time{}
+		}
+	}
+	c.evict("")
+	chartMetricCacheSize.Set(float64(c.size))
+}
+
+// locate returns the path to the chart archive for the given reference and a function releasing it.
+// The archive is not removed from the cache until it is released, so callers must release it once the chart was loaded.
+// On a miss the chart is located, pulled if necessary, verified against the digest published by its repository and added to the cache.
+// Cached archives are checked for corruption against the digest they were stored under before use.
+// Charts without a version and charts located in a local directory are not cached.
+func (c *chartCache) locate(cpo *action.ChartPathOptions, reference *greenhousev1alpha1.HelmChartReference, settings *cli.EnvSettings) (string, func(), error) {
+	noop := func() {}
+	if c.maxBytes <= 0 || reference.Version == "" {
+		chartMetricCacheRequests.WithLabelValues(chartCacheResultBypass).Inc()
+		path, err := locateChart(cpo, reference, settings)
+		return path, noop, err
+	}
+	key := reference.String()
+	if path, release, ok := c.get(key); ok {
+		chartMetricCacheRequests.WithLabelValues(chartCacheResultHit).Inc()
+		return path, release, nil
+	}
+	chartMetricCacheRequests.WithLabelValues(chartCacheResultMiss).Inc()
+	// The archive added by a concurrent caller may be evicted before it is acquired. Locating it again is rare and cheap.
+	for range 2 {
+		result, err, _ := c.group.Do(key, func() (any, error) {
+			expectedDigest, err := repositoryChartDigest(cpo, reference, settings)
+			if err != nil {
+				return nil, err
+			}
+			chartPath, err := locateChart(cpo, reference, settings)
+			if err != nil {
+				return nil, err
+			}
+			fi, err := os.Stat(chartPath)
+			if err != nil {
+				return nil, err
+			}
+			// Charts in a local directory are neither pulled nor packaged. There is nothing to cache.
+			if fi.IsDir() {
+				return chartCacheLocation{path: chartPath}, nil
+			}
+			digest, err := c.add(key, chartPath, expectedDigest)
+			if errors.Is(err, errChartDigestMismatch) {
+				// Remove the archive from the Helm repository cache, so it is downloaded again on the next attempt.
+				_ = os.Remove(chartPath)
+			}
+			return chartCacheLocation{digest: digest}, err
+		})
+		if err != nil {
+			return "", noop, err
+		}
+		location := result.(chartCacheLocation) //nolint:errcheck
+		if location.digest == "" {
+			return location.path, noop, nil
+		}
+		if path, release, ok := c.acquire(location.digest); ok {
+			return path, release, nil
+		}
+	}
+	return "", noop, fmt.Errorf("chart %s was evicted from the cache before it could be used", key)
+}
+
+// chartCacheLocation is the result of locating a chart. It is either the digest of the cached archive or the path of an uncached chart.
+type chartCacheLocation struct {
+	digest string
+	path   string
+}
+
+// get returns the path of the cached archive for the given key if it exists and is not corrupted.
+// The archive is hashed again without holding the lock, while it is acquired and therefore not evicted.
+func (c *chartCache) get(key string) (string, func(), bool) {
+	c.mu.Lock()
+	digest, ok := c.index[key]
+	if !ok {
+		c.mu.Unlock()
+		return "", nil, false
+	}
+	c.mu.Unlock()
+	path, release, ok := c.acquire(digest)
+	if !ok {
+		return "", nil, false
+	}
+	actualDigest, _, err := digestFile(path)
+	if err != nil || actualDigest != digest {
+		release()
+		c.mu.Lock()
+		c.removeBlob(digest)
+		c.mu.Unlock()
+		return "", nil, false
+	}
+	now := time.Now()
+	// The modification time persists the last use across restarts.
+	_ = os.Chtimes(path, now, now)
+	return path, release, true
+}
+
+// acquire marks the archive with the given digest as in use and returns its path and a function releasing it.
+func (c *chartCache) acquire(digest string) (string, func(), bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	blob, ok := c.blobs[digest]
+	if !ok {
+		return "", nil, false
+	}
+	blob.refs++
+	blob.lastUsed = time.Now()
+	var once sync.Once
+	return blob.path, func() { once.Do(func() { c.release(digest, blob) }) }, true
+}
+
+// release marks the archive as no longer used by the caller. Archives removed while in use are deleted by the last caller.
+func (c *chartCache) release(digest string, blob *chartCacheBlob) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	blob.refs--
+	if blob.refs > 0 {
+		return
+	}
+	if blob.removed {
+		// The archive might have been added again in the meantime under the same path.
+		if _, ok := c.blobs[digest]; !ok {
+			_ = os.Remove(blob.path)
+		}
+		return
+	}
+	c.evict("")
+	chartMetricCacheSize.Set(float64(c.size))
+}
+
+// add copies the archive at chartPath to the cache, indexes it under the given key and returns its digest.
+// An archive not matching the expected digest is refused. An empty expected digest skips the verification.
+// The digest of the archive is computed before acquiring the lock.
+func (c *chartCache) add(key, chartPath, expectedDigest string) (string, error) {
+	digest, size, err := digestFile(chartPath)
+	if err != nil {
+		return "", err
+	}
+	if expectedDigest != "" && digest != expectedDigest {
+		return "", fmt.Errorf("%w: chart %s has digest %s, but the repository published %s", errChartDigestMismatch, key, digest, expectedDigest)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	blob, ok := c.blobs[digest]
+	if !ok {
+		blobPath := filepath.Join(c.dir, digest+".tgz")
+		if err := copyFileAtomic(chartPath, blobPath); err != nil {
+			return "", err
+		}
+		blob = &chartCacheBlob{path: blobPath, size: size, keys: make(map[string]struct{})}
+		c.blobs[digest] = blob
+		c.size += size
+	}
+	// A new version of the chart might have been published under the same key.
+	if oldDigest, ok := c.index[key]; ok && oldDigest != digest {
+		if oldBlob, ok := c.blobs[oldDigest]; ok {
+			delete(oldBlob.keys, key)
+		}
+	}
+	c.index[key] = digest
+	blob.keys[key] = struct{}{}
+	blob.lastUsed = time.Now()
+	c.evict(digest)
+	c.saveIndex()
+	chartMetricCacheSize.Set(float64(c.size))
+	return digest, nil
+}
+
+// evict removes the least recently used archives not in use until the cache fits into maxBytes.
+// The archive with the given digest is kept as it was just requested. The caller must hold the lock.
+func (c *chartCache) evict(keepDigest string) {
+	evicted := false
+	for c.size > c.maxBytes {
+		var (
+			lruDigest string
+			lruBlob   *chartCacheBlob
+		)
+		for digest, blob := range c.blobs {
+			if digest == keepDigest || blob.refs > 0 {
+				continue
+			}
+			if lruBlob == nil || blob.lastUsed.Before(lruBlob.lastUsed) {
+				lruDigest, lruBlob = digest, blob
+			}
+		}
+		if lruBlob == nil {
+			break
+		}
+		c.removeBlob(lruDigest)
+		chartMetricCacheEvictions.Inc()
+		evicted = true
+	}
+	if evicted {
+		c.saveIndex()
+	}
+}
+
+// removeBlob removes the archive with the given digest and all keys referencing it. The caller must hold the lock.
+// The file of an archive in use is deleted once the archive is released.
+func (c *chartCache) removeBlob(digest string) {
+	blob, ok := c.blobs[digest]
+	if !ok {
+		return
+	}
+	for key := range blob.keys {
+		if c.index[key] == digest {
+			delete(c.index, key)
+		}
+	}
+	delete(c.blobs, digest)
+	c.size -= blob.size
+	if blob.refs > 0 {
+		blob.removed = true
+	} else {
+		_ = os.Remove(blob.path)
+	}
+	chartMetricCacheSize.Set(float64(c.size))
+}
+
+// saveIndex persists the index to the cache directory. Failures are ignored, as the archives are accounted for on load without it.
+// The caller must hold the lock.
+func (c *chartCache) saveIndex() {
+	data, err := json.Marshal(c.index)
+	if err != nil {
+		return
+	}
+	_ = writeFileAtomic(filepath.Join(c.dir, chartCacheIndexFile), bytes.NewReader(data))
+}
+
+// locateChart returns the path to the chart for the given reference, downloading it if necessary.
+// A chart already present in the Helm repository cache is used directly.
+func locateChart(cpo *action.ChartPathOptions, reference *greenhousev1alpha1.HelmChartReference, settings *cli.EnvSettings) (string, error) {
+	name := filepath.Base(reference.Name)
+	chartPath := settings.RepositoryCache + "/" + name + "-" + reference.Version + ".tgz"
+	if _, err := os.Stat(chartPath); err == nil {
+		return chartPath, nil
+	}
+	chartName := configureChartPathOptions(cpo, reference)
+	return cpo.LocateChart(chartName, settings)
+}
+
+// errChartDigestMismatch is returned if a chart archive does not match the digest published by its repository.
+var errChartDigestMismatch = errors.New("chart digest mismatch")
+
+// repositoryChartDigest returns the digest of the chart archive published in the index of its HTTP repository.
+// Helm does not verify downloaded archives against the index, so the cache does before storing them.
+// OCI charts are verified against the manifest while being pulled and charts available locally have no published digest.
+// An empty digest is returned in these cases and for repositories not publishing digests.
+func repositoryChartDigest(cpo *action.ChartPathOptions, reference *greenhousev1alpha1.HelmChartReference, settings *cli.EnvSettings) (string, error) {
+	if !strings.HasPrefix(reference.Repository, "http://") && !strings.HasPrefix(reference.Repository, "https://") {
+		return "", nil
+	}
+	if _, err := os.Stat(reference.Name); err == nil {
+		return "", nil
+	}
+	r, err := repo.NewChartRepository(&repo.Entry{
+		URL:                   reference.Repository,
+		Username:              cpo.Username,
+		Password:              cpo.Password,
+		PassCredentialsAll:    cpo.PassCredentialsAll,
+		CertFile:              cpo.CertFile,
+		KeyFile:               cpo.KeyFile,
+		CAFile:                cpo.CaFile,
+		InsecureSkipTLSverify: cpo.InsecureSkipTLSverify,
+	}, getter.All(settings))
+	if err != nil {
+		return "", err
+	}
+	r.CachePath, err = os.MkdirTemp("", "greenhouse-index-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(r.CachePath)
+	indexPath, err := r.DownloadIndexFile()
+	if err != nil {
+		return "", fmt.Errorf("failed to download the index of repository %s: %w", reference.Repository, err)
+	}
+	index, err := repo.LoadIndexFile(indexPath)
+	if err != nil {
+		return "", err
+	}
+	chartVersion, err := index.Get(reference.Name, reference.Version)
+	if err != nil {
+		return "", fmt.Errorf("chart %s not found in repository %s: %w", reference.String(), reference.Repository, err)
+	}
+	return strings.TrimPrefix(chartVersion.Digest, "sha256:"), nil
+}
+
+// digestFile returns the hex encoded sha256 digest and the size of the file.
+func digestFile(path string) (digest string, size int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err = io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// copyFileAtomic copies src to dst via a temporary file, so readers never observe a partially written archive.
+func copyFileAtomic(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := writeFileAtomic(dst, in); err != nil {
+		return fmt.Errorf("failed to store chart archive in cache: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes the content to dst via a temporary file, so readers never observe a partially written file.
+func writeFileAtomic(dst string, content io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, content); err != nil {
+		return errors.Join(err, tmp.Close(), os.Remove(tmp.Name()))
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/repo"
+
+	greenhousesapv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/helm"
+)
+
+var _ = Describe("chart cache", func() {
+	var (
+		cacheDir       string
+		myChartRef     *greenhousesapv1alpha1.HelmChartReference
+		myChartV2Ref   *greenhousesapv1alpha1.HelmChartReference
+		packageFixture = func(name, dir string) string {
+			c, err := loader.Load(filepath.Join("./../test/fixtures", name))
+			Expect(err).ToNot(HaveOccurred(), "there should be no error loading the chart fixture")
+			path, err := chartutil.Save(c, dir)
+			Expect(err).ToNot(HaveOccurred(), "there should be no error packaging the chart fixture")
+			return path
+		}
+	)
+
+	BeforeEach(func() {
+		cacheDir = GinkgoT().TempDir()
+		archiveDir := GinkgoT().TempDir()
+		myChartRef = &greenhousesapv1alpha1.HelmChartReference{
+			Name:       packageFixture("myChart", archiveDir),
+			Repository: "dummy",
+			Version:    "1.0.0",
+		}
+		myChartV2Ref = &greenhousesapv1alpha1.HelmChartReference{
+			Name:       packageFixture("myChartV2", archiveDir),
+			Repository: "dummy",
+			Version:    "2.0.0",
+		}
+	})
+
+	It("should return the cached archive on subsequent lookups", func() {
+		cache := helm.ExportNewChartCache(cacheDir, 1<<20)
+		path, release, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartRef, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error locating the chart")
+		Expect(filepath.Dir(path)).To(Equal(cacheDir), "the chart archive should be stored in the cache directory")
+		release()
+
+		Expect(os.Remove(myChartRef.Name)).To(Succeed(), "there should be no error removing the original archive")
+		cachedPath, release, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartRef, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "the cached chart should be returned without locating the chart again")
+		Expect(cachedPath).To(Equal(path), "the same archive should be returned")
+		release()
+	})
+
+	It("should not return a cached archive that does not match its digest", func() {
+		cache := helm.ExportNewChartCache(cacheDir, 1<<20)
+		path, release, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartRef, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error locating the chart")
+		release()
+
+		Expect(os.WriteFile(path, []byte("tampered"), 0o600)).To(Succeed(), "there should be no error modifying the cached archive")
+		Expect(os.Remove(myChartRef.Name)).To(Succeed(), "there should be no error removing the original archive")
+		_, _, err = helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartRef, helm.ExportSettings)
+		Expect(err).To(HaveOccurred(), "the tampered archive should be discarded and the chart located again")
+	})
+
+	It("should evict the least recently used archive if the cache is full", func() {
+		fi, err := os.Stat(myChartRef.Name)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error reading the archive size")
+		cache := helm.ExportNewChartCache(cacheDir, fi.Size())
+
+		firstPath, release, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartRef, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error locating the first chart")
+		release()
+		secondPath, release, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartV2Ref, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error locating the second chart")
+		release()
+
+		Expect(firstPath).ToNot(BeAnExistingFile(), "the least recently used archive should be evicted")
+		Expect(secondPath).To(BeAnExistingFile(), "the most recently used archive should be kept")
+	})
+
+	It("should not evict an archive while it is in use", func() {
+		fi, err := os.Stat(myChartRef.Name)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error reading the archive size")
+		cache := helm.ExportNewChartCache(cacheDir, fi.Size())
+
+		firstPath, releaseFirst, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartRef, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error locating the first chart")
+		_, releaseSecond, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartV2Ref, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error locating the second chart")
+		Expect(firstPath).To(BeAnExistingFile(), "the archive in use should not be evicted")
+
+		releaseFirst()
+		Expect(firstPath).ToNot(BeAnExistingFile(), "the archive should be evicted once it was released")
+		releaseSecond()
+	})
+
+	It("should reuse the archives in the cache directory after a restart", func() {
+		cache := helm.ExportNewChartCache(cacheDir, 1<<20)
+		path, release, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, myChartRef, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error locating the chart")
+		release()
+		Expect(os.WriteFile(filepath.Join(cacheDir, "orphan.tgz"), []byte("orphan"), 0o600)).To(Succeed(), "there should be no error writing an orphaned archive")
+
+		fi, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error reading the archive size")
+		Expect(os.Remove(myChartRef.Name)).To(Succeed(), "there should be no error removing the original archive")
+		restarted := helm.ExportNewChartCache(cacheDir, fi.Size())
+		Expect(filepath.Join(cacheDir, "orphan.tgz")).ToNot(BeAnExistingFile(), "the orphaned archive should be evicted first")
+		cachedPath, release, err := helm.ExportChartCacheLocate(restarted, &action.ChartPathOptions{}, myChartRef, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "the archive should be found in the cache directory after a restart")
+		Expect(cachedPath).To(Equal(path), "the same archive should be returned")
+		release()
+	})
+
+	It("should not cache charts located in a directory", func() {
+		cache := helm.ExportNewChartCache(cacheDir, 1<<20)
+		path, release, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, &greenhousesapv1alpha1.HelmChartReference{
+			Name:       "./../test/fixtures/myChart",
+			Repository: "dummy",
+			Version:    "1.0.0",
+		}, helm.ExportSettings)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error locating the chart")
+		Expect(path).To(BeADirectory(), "the chart directory should be returned as is")
+		release()
+		entries, err := os.ReadDir(cacheDir)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error reading the cache directory")
+		Expect(entries).To(BeEmpty(), "the cache directory should be empty")
+	})
+
+	Context("charts of an HTTP repository", func() {
+		var (
+			repositorySettings *cli.EnvSettings
+			serveRepository    = func(digest string) *greenhousesapv1alpha1.HelmChartReference {
+				archiveDir := GinkgoT().TempDir()
+				archivePath := packageFixture("myChart", archiveDir)
+				c, err := loader.Load(archivePath)
+				Expect(err).ToNot(HaveOccurred(), "there should be no error loading the chart archive")
+				if digest == "" {
+					digest, err = provenance.DigestFile(archivePath)
+					Expect(err).ToNot(HaveOccurred(), "there should be no error computing the digest of the chart archive")
+				}
+				server := httptest.NewServer(http.FileServer(http.Dir(archiveDir)))
+				DeferCleanup(server.Close)
+				index := repo.NewIndexFile()
+				Expect(index.MustAdd(c.Metadata, filepath.Base(archivePath), server.URL, digest)).To(Succeed(), "there should be no error adding the chart to the index")
+				Expect(index.WriteFile(filepath.Join(archiveDir, "index.yaml"), 0o600)).To(Succeed(), "there should be no error writing the index")
+				return &greenhousesapv1alpha1.HelmChartReference{Name: c.Name(), Repository: server.URL, Version: c.Metadata.Version}
+			}
+		)
+
+		BeforeEach(func() {
+			repositorySettings = cli.New()
+			repositorySettings.RepositoryCache = GinkgoT().TempDir()
+			repositorySettings.RepositoryConfig = filepath.Join(GinkgoT().TempDir(), "repositories.yaml")
+		})
+
+		It("should cache an archive matching the digest published by the repository", func() {
+			reference := serveRepository("")
+			cache := helm.ExportNewChartCache(cacheDir, 1<<20)
+			path, release, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, reference, repositorySettings)
+			Expect(err).ToNot(HaveOccurred(), "there should be no error locating the chart")
+			Expect(filepath.Dir(path)).To(Equal(cacheDir), "the chart archive should be stored in the cache directory")
+			release()
+		})
+
+		It("should refuse an archive not matching the digest published by the repository", func() {
+			reference := serveRepository("0000000000000000000000000000000000000000000000000000000000000000")
+			cache := helm.ExportNewChartCache(cacheDir, 1<<20)
+			_, _, err := helm.ExportChartCacheLocate(cache, &action.ChartPathOptions{}, reference, repositorySettings)
+			Expect(err).To(HaveOccurred(), "the archive should be refused")
+			Expect(err.Error()).To(ContainSubstring("digest mismatch"), "the error should report the digest mismatch")
+			entries, err := os.ReadDir(cacheDir)
+			Expect(err).ToNot(HaveOccurred(), "there should be no error reading the cache directory")
+			Expect(entries).To(BeEmpty(), "the refused archive should not be cached")
+			Expect(filepath.Join(repositorySettings.RepositoryCache, "myChart-1.0.0.tgz")).ToNot(BeAnExistingFile(), "the refused archive should be removed from the Helm repository cache")
+		})
+	})
+})
@@ -0,0 +1,376 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+const (
+	// gitRefResolveInterval is the interval after which a branch or tag is resolved to a commit again.
+	gitRefResolveInterval = 5 * time.Minute
+	// maxGitCheckoutsPerRepository is the number of checked out commits kept per repository.
+	maxGitCheckoutsPerRepository = 5
+
+	// Keys in the Secret referenced by GitChartSource.SecretRef.
+	gitSecretUsernameKey   = "username"
+	gitSecretPasswordKey   = "password"
+	gitSecretIdentityKey   = "identity"
+	gitSecretKnownHostsKey = "known_hosts"
+
+	// HelmChartGitCommitAnnotation is set on charts loaded from Git and carries the commit the chart was checked out from.
+	HelmChartGitCommitAnnotation = "greenhouse.sap/git-commit"
+)
+
+var (
+	sharedGitChartCache     *gitChartCache
+	sharedGitChartCacheOnce sync.Once
+)
+
+// getGitChartCache returns the cache of Git checkouts shared by all template, diff, install and upgrade operations.
+func getGitChartCache() *gitChartCache {
+	sharedGitChartCacheOnce.Do(func() {
+		sharedGitChartCache = newGitChartCache(filepath.Join(settings.RepositoryCache, "greenhouse-git"))
+	})
+	return sharedGitChartCache
+}
+
+// gitChartCache keeps checkouts of Git repositories containing charts.
+// Checkouts are stored per repository, credentials and commit and are immutable once created.
+// They are not shared between credentials, so a Plugin only uses checkouts its own credentials granted access to.
+type gitChartCache struct {
+	mu        sync.Mutex
+	dir       string
+	resolved  map[string]gitResolvedRef
+	checkouts map[string]map[string]*gitCheckout
+	repoLocks map[string]*sync.Mutex
+}
+
+// gitCheckout is a checkout of a commit of a repository.
+type gitCheckout struct {
+	lastUsed time.Time
+	// refs counts the callers currently reading the checkout. Checkouts in use are not removed.
+	refs int
+}
+
+// gitResolvedRef is a Git reference resolved to a commit.
+type gitResolvedRef struct {
+	commit     string
+	resolvedAt time.Time
+}
+
+func newGitChartCache(dir string) *gitChartCache {
+	return &gitChartCache{
+		dir:       dir,
+		resolved:  make(map[string]gitResolvedRef),
+		checkouts: make(map[string]map[string]*gitCheckout),
+		repoLocks: make(map[string]*sync.Mutex),
+	}
+}
+
+// checkout returns the path to the chart referenced by the GitChartSource, the commit it was checked out from and a function releasing the checkout.
+// The checkout is not removed until it is released, so callers must release it once the chart was read.
+// Credentials are read from the Secret referenced by the source in the given namespace of the Plugin.
+func (c *gitChartCache) checkout(ctx context.Context, k8sClient client.Client, source *greenhousev1alpha1.GitChartSource, namespace string) (chartPath, commit string, release func(), err error) {
+	auth, err := gitAuthFromSecret(ctx, k8sClient, source, namespace)
+	if err != nil {
+		return "", "", nil, err
+	}
+	repoKey := repositoryKey(source, namespace)
+	repoLock := c.lockRepository(repoKey)
+	defer repoLock.Unlock()
+
+	commit, err = c.resolve(ctx, repoKey, source, auth)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to resolve %s: %w", source.String(), err)
+	}
+	checkoutDir := filepath.Join(c.repositoryDir(repoKey), commit)
+	if _, err := os.Stat(checkoutDir); errors.Is(err, os.ErrNotExist) {
+		log.FromContext(ctx).Info("checking out chart from git", "url", source.URL, "ref", source.Ref.String(), "commit", commit)
+		if err := cloneCommit(ctx, source, auth, commit, checkoutDir); err != nil {
+			// The branch or tag might have moved since it was resolved. Resolve it again next time.
+			c.mu.Lock()
+			delete(c.resolved, repoKey+"@"+source.Ref.String())
+			c.mu.Unlock()
+			return "", "", nil, fmt.Errorf("failed to check out %s: %w", source.String(), err)
+		}
+	} else if err != nil {
+		return "", "", nil, err
+	}
+
+	chartPath, err = securePathInCheckout(checkoutDir, source.Path)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return chartPath, commit, c.acquire(repoKey, commit), nil
+}
+
+// resolve returns the commit the reference of the source points to.
+// Commits are returned as is. Branches and tags are resolved against the remote at most once per gitRefResolveInterval.
+func (c *gitChartCache) resolve(ctx context.Context, repoKey string, source *greenhousev1alpha1.GitChartSource, auth transport.AuthMethod) (string, error) {
+	if source.Ref.Commit != "" {
+		return source.Ref.Commit, nil
+	}
+	key := repoKey + "@" + source.Ref.String()
+	c.mu.Lock()
+	r, ok := c.resolved[key]
+	c.mu.Unlock()
+	if ok && time.Since(r.resolvedAt) < gitRefResolveInterval {
+		return r.commit, nil
+	}
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{source.URL}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth, PeelingOption: git.AppendPeeled})
+	if err != nil {
+		return "", err
+	}
+	commit, err := findCommitForRef(refs, source.Ref)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.resolved[key] = gitResolvedRef{commit: commit, resolvedAt: time.Now()}
+	c.mu.Unlock()
+	return commit, nil
+}
+
+// findCommitForRef returns the commit for the branch, tag or HEAD in the list of remote references.
+func findCommitForRef(refs []*plumbing.Reference, ref greenhousev1alpha1.GitReference) (string, error) {
+	byName := make(map[plumbing.ReferenceName]*plumbing.Reference, len(refs))
+	for _, r := range refs {
+		byName[r.Name()] = r
+	}
+	var name plumbing.ReferenceName
+	switch {
+	case ref.Tag != "":
+		// Prefer the peeled reference of an annotated tag, which points to the commit instead of the tag object.
+		if peeled, ok := byName[plumbing.ReferenceName(plumbing.NewTagReferenceName(ref.Tag).String()+"^{}")]; ok {
+			return peeled.Hash().String(), nil
+		}
+		name = plumbing.NewTagReferenceName(ref.Tag)
+	case ref.Branch != "":
+		name = plumbing.NewBranchReferenceName(ref.Branch)
+	default:
+		name = plumbing.HEAD
+	}
+	r, ok := byName[name]
+	if !ok {
+		return "", fmt.Errorf("reference %s not found", name)
+	}
+	if r.Type() == plumbing.SymbolicReference {
+		target, ok := byName[r.Target()]
+		if !ok {
+			return "", fmt.Errorf("reference %s not found", r.Target())
+		}
+		r = target
+	}
+	return r.Hash().String(), nil
+}
+
+// cloneCommit clones the repository at the given commit into dir.
+// The clone is done in a temporary directory, which is renamed once complete, so a partial checkout is never used.
+func cloneCommit(ctx context.Context, source *greenhousev1alpha1.GitChartSource, auth transport.AuthMethod, commit, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), commit+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	opts := &git.CloneOptions{URL: source.URL, Auth: auth}
+	// Branches and tags are cloned shallowly. A specific commit requires the history to be available.
+	switch {
+	case source.Ref.Commit != "":
+		opts.NoCheckout = true
+	case source.Ref.Tag != "":
+		opts.ReferenceName = plumbing.NewTagReferenceName(source.Ref.Tag)
+		opts.SingleBranch = true
+		opts.Depth = 1
+	case source.Ref.Branch != "":
+		opts.ReferenceName = plumbing.NewBranchReferenceName(source.Ref.Branch)
+		opts.SingleBranch = true
+		opts.Depth = 1
+	default:
+		opts.SingleBranch = true
+		opts.Depth = 1
+	}
+	repo, err := git.PlainCloneContext(ctx, tmpDir, false, opts)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(commit), Force: true}); err != nil {
+		return err
+	}
+	// The history is not needed to render the chart.
+	if err := os.RemoveAll(filepath.Join(tmpDir, git.GitDirName)); err != nil {
+		return err
+	}
+	return os.Rename(tmpDir, dir)
+}
+
+// acquire records the usage of a checkout, removes the least recently used checkouts of the repository not in use
+// and returns a function releasing the checkout.
+func (c *gitChartCache) acquire(repoKey, commit string) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	commits, ok := c.checkouts[repoKey]
+	if !ok {
+		commits = make(map[string]*gitCheckout)
+		c.checkouts[repoKey] = commits
+	}
+	co, ok := commits[commit]
+	if !ok {
+		co = &gitCheckout{}
+		commits[commit] = co
+	}
+	co.refs++
+	co.lastUsed = time.Now()
+	c.evict(repoKey)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			co.refs--
+			c.evict(repoKey)
+		})
+	}
+}
+
+// evict removes the least recently used checkouts of the repository not in use until at most maxGitCheckoutsPerRepository remain.
+// The caller must hold the lock.
+func (c *gitChartCache) evict(repoKey string) {
+	commits := c.checkouts[repoKey]
+	for len(commits) > maxGitCheckoutsPerRepository {
+		var lruCommit string
+		for k, co := range commits {
+			if co.refs > 0 {
+				continue
+			}
+			if lruCommit == "" || co.lastUsed.Before(commits[lruCommit].lastUsed) {
+				lruCommit = k
+			}
+		}
+		if lruCommit == "" {
+			return
+		}
+		delete(commits, lruCommit)
+		_ = os.RemoveAll(filepath.Join(c.repositoryDir(repoKey), lruCommit))
+	}
+}
+
+// lockRepository serializes operations on the same repository and returns the held lock.
+func (c *gitChartCache) lockRepository(repoKey string) *sync.Mutex {
+	c.mu.Lock()
+	l, ok := c.repoLocks[repoKey]
+	if !ok {
+		l = &sync.Mutex{}
+		c.repoLocks[repoKey] = l
+	}
+	c.mu.Unlock()
+	l.Lock()
+	return l
+}
+
+// repositoryDir returns the directory containing the checkouts of the repository.
+func (c *gitChartCache) repositoryDir(repoKey string) string {
+	return filepath.Join(c.dir, repoKey)
+}
+
+// repositoryKey identifies the repository of the source together with the credentials used to access it.
+// The credentials are identified by the Secret referenced in the namespace of the Plugin.
+func repositoryKey(source *greenhousev1alpha1.GitChartSource, namespace string) string {
+	var credentials string
+	if source.SecretRef != nil {
+		credentials = namespace + "/" + source.SecretRef.Name
+	}
+	sum := sha256.Sum256([]byte(credentials + "\x00" + source.URL))
+	return hex.EncodeToString(sum[:])
+}
+
+// securePathInCheckout joins the checkout directory and the path of the chart, ensuring the result stays within the checkout.
+func securePathInCheckout(checkoutDir, path string) (string, error) {
+	chartPath := filepath.Join(checkoutDir, path)
+	rel, err := filepath.Rel(checkoutDir, chartPath)
+	if err != nil || filepath.IsAbs(path) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside of the repository", path)
+	}
+	return chartPath, nil
+}
+
+// gitAuthFromSecret returns the authentication method configured in the Secret referenced by the source in the namespace of the Plugin.
+// HTTP basic authentication is used if a username or password is present, SSH public key authentication if an identity is present.
+func gitAuthFromSecret(ctx context.Context, k8sClient client.Client, source *greenhousev1alpha1.GitChartSource, namespace string) (transport.AuthMethod, error) {
+	if source.SecretRef == nil {
+		return nil, nil
+	}
+	secret := new(corev1.Secret)
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.SecretRef.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get git credentials: %w", err)
+	}
+	if identity, ok := secret.Data[gitSecretIdentityKey]; ok {
+		publicKeys, err := gitssh.NewPublicKeys(gitssh.DefaultUsername, identity, string(secret.Data[gitSecretPasswordKey]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse git identity in secret %s/%s: %w", namespace, source.SecretRef.Name, err)
+		}
+		if knownHosts, ok := secret.Data[gitSecretKnownHostsKey]; ok {
+			if err := setKnownHostsCallback(publicKeys, knownHosts); err != nil {
+				return nil, fmt.Errorf("failed to parse known_hosts in secret %s/%s: %w", namespace, source.SecretRef.Name, err)
+			}
+		}
+		return publicKeys, nil
+	}
+	username, hasUsername := secret.Data[gitSecretUsernameKey]
+	password, hasPassword := secret.Data[gitSecretPasswordKey]
+	if !hasUsername && !hasPassword {
+		return nil, fmt.Errorf("secret %s/%s contains neither %s nor %s and %s", namespace, source.SecretRef.Name, gitSecretIdentityKey, gitSecretUsernameKey, gitSecretPasswordKey)
+	}
+	return &githttp.BasicAuth{Username: string(username), Password: string(password)}, nil
+}
+
+// setKnownHostsCallback configures the public keys to verify the host key against the given known_hosts content.
+func setKnownHostsCallback(publicKeys *gitssh.PublicKeys, knownHosts []byte) error {
+	f, err := os.CreateTemp("", "known_hosts-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(knownHosts); err != nil {
+		return errors.Join(err, f.Close())
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	publicKeys.HostKeyCallback, err = gitssh.NewKnownHostsCallback(f.Name())
+	return err
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	greenhousesapv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/helm"
+)
+
+var _ = Describe("git chart source", func() {
+	var (
+		repoDir   string
+		repo      *git.Repository
+		commitAll = func(message string) string {
+			worktree, err := repo.Worktree()
+			Expect(err).ToNot(HaveOccurred(), "there should be no error getting the worktree")
+			Expect(worktree.AddGlob(".")).To(Succeed(), "there should be no error staging the chart")
+			hash, err := worktree.Commit(message, &git.CommitOptions{
+				Author: &object.Signature{Name: "greenhouse", Email: "greenhouse@example.com", When: time.Now()},
+			})
+			Expect(err).ToNot(HaveOccurred(), "there should be no error committing the chart")
+			return hash.String()
+		}
+		copyFixture = func(name, path string) {
+			c, err := loader.Load(filepath.Join("./../test/fixtures", name))
+			Expect(err).ToNot(HaveOccurred(), "there should be no error loading the chart fixture")
+			Expect(os.RemoveAll(filepath.Join(repoDir, path))).To(Succeed(), "there should be no error removing the previous chart")
+			Expect(chartutil.SaveDir(c, repoDir)).To(Succeed(), "there should be no error writing the chart fixture")
+			Expect(os.Rename(filepath.Join(repoDir, c.Name()), filepath.Join(repoDir, path))).To(Succeed(), "there should be no error moving the chart")
+		}
+	)
+
+	BeforeEach(func() {
+		var err error
+		repoDir = GinkgoT().TempDir()
+		repo, err = git.PlainInit(repoDir, false)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error initializing the git repository")
+		copyFixture("myChart", "chart")
+	})
+
+	It("should check out the chart at the resolved commit", func() {
+		commit := commitAll("add chart")
+		cache := helm.ExportNewGitChartCache(GinkgoT().TempDir())
+		source := &greenhousesapv1alpha1.GitChartSource{URL: repoDir, Path: "chart"}
+
+		chartPath, resolved, release, err := helm.ExportGitChartCacheCheckout(cache, context.Background(), fake.NewClientBuilder().Build(), source, "default")
+		Expect(err).ToNot(HaveOccurred(), "there should be no error checking out the chart")
+		defer release()
+		Expect(resolved).To(Equal(commit), "the chart should be checked out at the latest commit")
+		c, err := loader.Load(chartPath)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error loading the checked out chart")
+		Expect(c.Metadata.Version).To(Equal("1.0.0"), "the checked out chart should have the committed version")
+	})
+
+	It("should check out a pinned commit", func() {
+		firstCommit := commitAll("add chart")
+		copyFixture("myChartV2", "chart")
+		commitAll("update chart")
+		cache := helm.ExportNewGitChartCache(GinkgoT().TempDir())
+		source := &greenhousesapv1alpha1.GitChartSource{URL: repoDir, Path: "chart", Ref: greenhousesapv1alpha1.GitReference{Commit: firstCommit}}
+
+		chartPath, resolved, release, err := helm.ExportGitChartCacheCheckout(cache, context.Background(), fake.NewClientBuilder().Build(), source, "default")
+		Expect(err).ToNot(HaveOccurred(), "there should be no error checking out the chart")
+		defer release()
+		Expect(resolved).To(Equal(firstCommit), "the chart should be checked out at the pinned commit")
+		c, err := loader.Load(chartPath)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error loading the checked out chart")
+		Expect(c.Metadata.Version).To(Equal("1.0.0"), "the checked out chart should have the version of the pinned commit")
+	})
+
+	It("should not share checkouts between the credentials of different namespaces", func() {
+		commit := commitAll("add chart")
+		cache := helm.ExportNewGitChartCache(GinkgoT().TempDir())
+		k8sClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "git-credentials", Namespace: "org-a"},
+			Data:       map[string][]byte{"username": []byte("user"), "password": []byte("password")},
+		}).Build()
+		source := &greenhousesapv1alpha1.GitChartSource{URL: repoDir, Path: "chart", Ref: greenhousesapv1alpha1.GitReference{Commit: commit},
+			SecretRef: &greenhousesapv1alpha1.GitCredentialsReference{Name: "git-credentials"}}
+
+		chartPath, _, release, err := helm.ExportGitChartCacheCheckout(cache, context.Background(), k8sClient, source, "org-a")
+		Expect(err).ToNot(HaveOccurred(), "there should be no error checking out the chart with the credentials of org-a")
+		defer release()
+
+		_, _, _, err = helm.ExportGitChartCacheCheckout(cache, context.Background(), k8sClient, source, "org-b")
+		Expect(err).To(HaveOccurred(), "the checkout of org-a should not be used without the credentials in org-b")
+
+		source.SecretRef = nil
+		otherPath, _, releaseOther, err := helm.ExportGitChartCacheCheckout(cache, context.Background(), k8sClient, source, "org-b")
+		Expect(err).ToNot(HaveOccurred(), "there should be no error checking out the chart without credentials")
+		defer releaseOther()
+		Expect(otherPath).ToNot(Equal(chartPath), "the checkout without credentials should be separate")
+	})
+
+	It("should reject a path outside of the repository", func() {
+		commitAll("add chart")
+		cache := helm.ExportNewGitChartCache(GinkgoT().TempDir())
+		source := &greenhousesapv1alpha1.GitChartSource{URL: repoDir, Path: "../chart"}
+
+		_, _, _, err := helm.ExportGitChartCacheCheckout(cache, context.Background(), fake.NewClientBuilder().Build(), source, "default")
+		Expect(err).To(HaveOccurred(), "there should be an error checking out a path outside of the repository")
+	})
+
+	It("should not remove a checkout while it is in use", func() {
+		var commits []string
+		for i := range 6 {
+			Expect(os.WriteFile(filepath.Join(repoDir, "revision"), []byte(strconv.Itoa(i)), 0o600)).To(Succeed(), "there should be no error writing the revision")
+			commits = append(commits, commitAll("revision "+strconv.Itoa(i)))
+		}
+		cache := helm.ExportNewGitChartCache(GinkgoT().TempDir())
+		checkout := func(commit string) (string, func()) {
+			source := &greenhousesapv1alpha1.GitChartSource{URL: repoDir, Path: "chart", Ref: greenhousesapv1alpha1.GitReference{Commit: commit}}
+			chartPath, _, release, err := helm.ExportGitChartCacheCheckout(cache, context.Background(), fake.NewClientBuilder().Build(), source, "default")
+			Expect(err).ToNot(HaveOccurred(), "there should be no error checking out the chart")
+			return chartPath, release
+		}
+
+		firstPath, releaseFirst := checkout(commits[0])
+		defer releaseFirst()
+		var paths []string
+		for _, commit := range commits[1:] {
+			path, release := checkout(commit)
+			release()
+			paths = append(paths, path)
+		}
+		Expect(firstPath).To(BeADirectory(), "the checkout in use should not be removed")
+		Expect(paths[0]).ToNot(BeADirectory(), "the least recently used checkout not in use should be removed instead")
+	})
+})
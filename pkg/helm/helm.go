@@ -12,7 +12,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"slices"
 	"sort"
 	"time"
@@ -59,9 +58,9 @@ const driftDetectionInterval = 60 * time.Minute
 // InstallOrUpgradeHelmChartFromPlugin installs a new or upgrades an existing Helm release for the given PluginDefinition and Plugin.
 func InstallOrUpgradeHelmChartFromPlugin(ctx context.Context, local client.Client, restClientGetter genericclioptions.RESTClientGetter, pluginDefinition *greenhousev1alpha1.PluginDefinition, plugin *greenhousev1alpha1.Plugin) error {
 	// Early return if the pluginDefinition is not helm based
-	if pluginDefinition.Spec.HelmChart == nil {
+	if pluginDefinition.Spec.ChartReference() == nil {
 		metrics.UpdateMetrics(plugin, metrics.MetricResultError, metrics.MetricReasonHelmChartIsNotDefined)
-		return fmt.Errorf("no helm chart defined in pluginDefinition.Spec.HelmChart for pluginDefinition %s", plugin.Spec.PluginDefinition)
+		return fmt.Errorf("no helm chart or manifests defined in pluginDefinition %s", plugin.Spec.PluginDefinition)
 	}
 	latestRelease, isReleaseExists, err := isReleaseExistsForPlugin(ctx, restClientGetter, plugin)
 	if err != nil {
@@ -74,7 +73,7 @@ func InstallOrUpgradeHelmChartFromPlugin(ctx context.Context, local client.Clien
 		metrics.UpdateMetrics(plugin, metrics.MetricResultError, metrics.MetricReasonInstallFailed)
 		return err
 	}
-	helmChart, err := locateChartForPlugin(restClientGetter, pluginDefinition)
+	helmChart, err := locateChartForPlugin(ctx, local, restClientGetter, pluginDefinition, plugin)
 	if err != nil {
 		metrics.UpdateMetrics(plugin, metrics.MetricResultError, metrics.MetricReasonUpgradeFailed)
 		return err
@@ -189,8 +188,8 @@ func DiffChartToDeployedResources(ctx context.Context, local client.Client, rest
 	if plugin.Status.HelmReleaseStatus != nil && plugin.Status.HelmChart != nil {
 		pluginStatusHelmChart = plugin.Status.HelmChart.String()
 	}
-	if pluginDefinition.Spec.HelmChart.String() != pluginStatusHelmChart {
-		log.FromContext(ctx).Info("observed helm chart differs from pluginDefinition helm chart", "pluginDefinition", pluginDefinition.Spec.HelmChart.String(), "plugin", pluginStatusHelmChart)
+	if pluginDefinition.Spec.ChartReference().String() != pluginStatusHelmChart {
+		log.FromContext(ctx).Info("observed helm chart differs from pluginDefinition helm chart", "pluginDefinition", pluginDefinition.Spec.ChartReference().String(), "plugin", pluginStatusHelmChart)
 		return nil, true, nil
 	}
 
@@ -214,6 +213,12 @@ func DiffChartToDeployedResources(ctx context.Context, local client.Client, rest
 		return nil, false, err
 	}
 
+	// A branch or tag of a chart sourced from Git might point to a new commit.
+	if templateCommit, releaseCommit := GitCommitFromRelease(helmTemplateRelease), GitCommitFromRelease(helmRelease); templateCommit != releaseCommit {
+		log.FromContext(ctx).Info("git commit of the helm chart differs from the deployed release", "template", templateCommit, "release", releaseCommit)
+		return nil, true, nil
+	}
+
 	diffObjects, err := diffAgainstRelease(restClientGetter, plugin.Spec.ReleaseNamespace, helmTemplateRelease, helmRelease)
 	if err != nil {
 		return nil, false, err
@@ -342,7 +347,7 @@ type ChartLoaderFunc func(name string) (*chart.Chart, error)
 
 var ChartLoader ChartLoaderFunc = loader.Load
 
-func locateChartForPlugin(restClientGetter genericclioptions.RESTClientGetter, pluginDefinition *greenhousev1alpha1.PluginDefinition) (*chart.Chart, error) {
+func locateChartForPlugin(ctx context.Context, local client.Client, restClientGetter genericclioptions.RESTClientGetter, pluginDefinition *greenhousev1alpha1.PluginDefinition, plugin *greenhousev1alpha1.Plugin) (*chart.Chart, error) {
 	cfg, err := newHelmAction(restClientGetter, corev1.NamespaceAll)
 	if err != nil {
 		return nil, err
@@ -350,13 +355,7 @@ func locateChartForPlugin(restClientGetter genericclioptions.RESTClientGetter, p
 
 	// FIXME: we need to instantiate a action to set the registry in the ChartPathOptions
 	cpo := &action.NewShowWithConfig(action.ShowChart, cfg).ChartPathOptions
-
-	chartName := configureChartPathOptions(cpo, pluginDefinition.Spec.HelmChart)
-	chartPath, err := cpo.LocateChart(chartName, settings)
-	if err != nil {
-		return nil, err
-	}
-	return ChartLoader(chartPath)
+	return loadChartForPlugin(ctx, local, cpo, pluginDefinition, plugin)
 }
 
 // configureChartPathOptions configures the ChartPathOptions and chartName considering OCI repositories.
@@ -384,7 +383,7 @@ func upgradeRelease(ctx context.Context, local client.Client, restClientGetter g
 	upgradeAction.Timeout = GetHelmTimeout() // set a timeout for the upgrade to not be stuck in pending state
 	upgradeAction.Description = pluginDefinition.Spec.Version
 
-	helmChart, err := loadHelmChart(&upgradeAction.ChartPathOptions, pluginDefinition.Spec.HelmChart, settings)
+	helmChart, err := loadChartForPlugin(ctx, local, &upgradeAction.ChartPathOptions, pluginDefinition, plugin)
 	if err != nil {
 		return err
 	}
@@ -426,7 +425,7 @@ func installRelease(ctx context.Context, local client.Client, restClientGetter g
 	installAction.ClientOnly = isDryRun
 	installAction.Description = pluginDefinition.Spec.Version
 
-	helmChart, err := loadHelmChart(&installAction.ChartPathOptions, pluginDefinition.Spec.HelmChart, settings)
+	helmChart, err := loadChartForPlugin(ctx, local, &installAction.ChartPathOptions, pluginDefinition, plugin)
 	if err != nil {
 		return nil, err
 	}
@@ -452,21 +451,50 @@ func installRelease(ctx context.Context, local client.Client, restClientGetter g
 	return installAction.RunWithContext(ctx, helmChart, helmValues)
 }
 
-func loadHelmChart(chartPathOptions *action.ChartPathOptions, reference *greenhousev1alpha1.HelmChartReference, settings *cli.EnvSettings) (*chart.Chart, error) {
-	name := filepath.Base(reference.Name)
-	chartPath := settings.RepositoryCache + "/" + name + "-" + reference.Version + ".tgz"
+// loadChartForPlugin loads the Helm chart of the PluginDefinition or renders its manifests into a Helm chart.
+func loadChartForPlugin(ctx context.Context, local client.Client, chartPathOptions *action.ChartPathOptions, pluginDefinition *greenhousev1alpha1.PluginDefinition, plugin *greenhousev1alpha1.Plugin) (*chart.Chart, error) {
+	if pluginDefinition.Spec.Manifests != nil {
+		return loadManifestChart(ctx, local, pluginDefinition, plugin)
+	}
+	return loadHelmChart(ctx, local, chartPathOptions, pluginDefinition.Spec.HelmChart, plugin.GetNamespace(), settings)
+}
 
-	if _, err := os.Stat(chartPath); errors.Is(err, os.ErrNotExist) {
-		chartName := configureChartPathOptions(chartPathOptions, reference)
-		chartPath, err = chartPathOptions.LocateChart(chartName, settings)
+// loadHelmChart loads the chart for the given reference using the shared chart cache.
+// Charts sourced from Git are checked out using the credentials in the given namespace and annotated with the commit.
+func loadHelmChart(ctx context.Context, local client.Client, chartPathOptions *action.ChartPathOptions, reference *greenhousev1alpha1.HelmChartReference, namespace string, settings *cli.EnvSettings) (*chart.Chart, error) {
+	if reference.Git != nil {
+		chartPath, commit, release, err := getGitChartCache().checkout(ctx, local, reference.Git, namespace)
 		if err != nil {
 			return nil, err
 		}
+		defer release()
+		helmChart, err := ChartLoader(chartPath)
+		if err != nil {
+			return nil, err
+		}
+		if helmChart.Metadata.Annotations == nil {
+			helmChart.Metadata.Annotations = make(map[string]string, 1)
+		}
+		helmChart.Metadata.Annotations[HelmChartGitCommitAnnotation] = commit
+		return helmChart, nil
 	}
-
+	chartPath, release, err := getChartCache().locate(chartPathOptions, reference, settings)
+	if err != nil {
+		return nil, err
+	}
+	// The archive is read completely when loading the chart and may be evicted afterwards.
+	defer release()
 	return ChartLoader(chartPath)
 }
 
+// GitCommitFromRelease returns the commit the chart of the release was checked out from, if it was sourced from Git.
+func GitCommitFromRelease(r *release.Release) string {
+	if r == nil || r.Chart == nil || r.Chart.Metadata == nil {
+		return ""
+	}
+	return r.Chart.Metadata.Annotations[HelmChartGitCommitAnnotation]
+}
+
 func newHelmAction(restClientGetter genericclioptions.RESTClientGetter, namespace string) (*action.Configuration, error) {
 	cfg := &action.Configuration{}
 	settings.SetNamespace(namespace)
@@ -11,4 +11,10 @@ var (
 	ExportGreenhouseFieldManager    = greenhouseFieldManager
 	ExportDiffAgainstRelease        = diffAgainstRelease
 	ExportInstallHelmRelease        = installRelease
+	ExportNewChartCache             = newChartCache
+	ExportChartCacheLocate          = (*chartCache).locate
+	ExportSettings                  = settings
+	ExportNewGitChartCache          = newGitChartCache
+	ExportGitChartCacheCheckout     = (*gitChartCache).checkout
+	ExportRenderManifests           = renderManifests
 )
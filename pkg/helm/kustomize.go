@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+const (
+	// manifestChartFile is the file of the rendered chart containing the manifests.
+	manifestChartFile = "manifests.yaml"
+	// manifestChartTemplate returns the manifests as they are, so Helm does not interpret them as templates.
+	manifestChartTemplate = `{{ .Files.Get "manifests.yaml" }}`
+
+	// pluginOptionValuesKind is the kind of the object providing the PluginOption values as source for replacements.
+	// The object is annotated as local configuration and not part of the rendered manifests.
+	pluginOptionValuesKind = "PluginOptionValues"
+	pluginOptionValuesName = "greenhouse-plugin-options"
+
+	kustomizeRepositoryDir = "/repository"
+	kustomizeWrapperDir    = "/greenhouse"
+)
+
+// loadManifestChart renders the manifests of the PluginDefinition and wraps them in a Helm chart.
+// Deploying the manifests as a Helm release provides the same lifecycle as for Helm charts. The release is the inventory used to prune removed objects.
+func loadManifestChart(ctx context.Context, local client.Client, pluginDefinition *greenhousev1alpha1.PluginDefinition, plugin *greenhousev1alpha1.Plugin) (*chart.Chart, error) {
+	manifests := pluginDefinition.Spec.Manifests
+	// Check out the root of the repository as kustomizations might reference bases outside the path.
+	source := manifests.Git.DeepCopy()
+	source.Path = ""
+	repositoryDir, commit, release, err := getGitChartCache().checkout(ctx, local, source, plugin.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if _, err := securePathInCheckout(repositoryDir, manifests.Git.Path); err != nil {
+		return nil, err
+	}
+	pluginValues, err := getValuesFromPlugin(ctx, local, plugin)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := renderManifests(repositoryDir, manifests.Git.Path, manifests.Replacements, pluginValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render manifests %s: %w", manifests.Git.String(), err)
+	}
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion:  chart.APIVersionV2,
+			Name:        pluginDefinition.GetName(),
+			Version:     pluginDefinition.Spec.Version,
+			Annotations: map[string]string{HelmChartGitCommitAnnotation: commit},
+		},
+		Templates: []*chart.File{{Name: path.Join("templates", manifestChartFile), Data: []byte(manifestChartTemplate)}},
+		Files:     []*chart.File{{Name: manifestChartFile, Data: rendered}},
+	}, nil
+}
+
+// renderManifests builds the manifests in the path of the repository with Kustomize.
+// A kustomization listing all manifests is generated if the path does not contain one.
+// The checkout is read from disk and generated files are kept in memory, so neither the shared checkout is modified nor files outside of it are read.
+func renderManifests(repositoryDir, manifestPath string, replacements []greenhousev1alpha1.ManifestReplacement, pluginValues []greenhousev1alpha1.PluginOptionValue) ([]byte, error) {
+	fSys, err := newCheckoutFs(repositoryDir)
+	if err != nil {
+		return nil, err
+	}
+	manifestDir := path.Join(kustomizeRepositoryDir, filepath.ToSlash(filepath.Clean("/"+manifestPath)))
+	if !fSys.IsDir(manifestDir) {
+		return nil, fmt.Errorf("path %s is not a directory", manifestPath)
+	}
+	if !hasKustomization(fSys, manifestDir) {
+		if err := writeManifestKustomization(fSys, manifestDir); err != nil {
+			return nil, err
+		}
+	}
+
+	optionValues, err := pluginOptionValuesManifest(pluginValues)
+	if err != nil {
+		return nil, err
+	}
+	if err := fSys.WriteFile(path.Join(kustomizeWrapperDir, "options.yaml"), optionValues); err != nil {
+		return nil, err
+	}
+	kustomization := &kustomizetypes.Kustomization{
+		TypeMeta: kustomizetypes.TypeMeta{
+			APIVersion: kustomizetypes.KustomizationVersion,
+			Kind:       kustomizetypes.KustomizationKind,
+		},
+		Resources:    []string{path.Join("..", manifestDir), "options.yaml"},
+		Replacements: kustomizeReplacements(replacements, pluginValues),
+	}
+	if err := writeKustomization(fSys, kustomizeWrapperDir, kustomization); err != nil {
+		return nil, err
+	}
+
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, kustomizeWrapperDir)
+	if err != nil {
+		return nil, err
+	}
+	return resMap.AsYaml()
+}
+
+// hasKustomization returns whether the directory contains a kustomization.
+func hasKustomization(fSys filesys.FileSystem, dir string) bool {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		if fSys.Exists(path.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeManifestKustomization writes a kustomization listing all manifests in the directory and its subdirectories.
+// Files containing anything but Kubernetes objects, such as Helm values or other configuration, are skipped.
+func writeManifestKustomization(fSys filesys.FileSystem, dir string) error {
+	var resources []string
+	err := fSys.Walk(dir, func(p string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || !slices.Contains([]string{".yaml", ".yml", ".json"}, strings.ToLower(path.Ext(p))) {
+			return nil
+		}
+		data, err := fSys.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if !isManifest(data) {
+			return nil
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, dir), "/")
+		resources = append(resources, rel)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		return fmt.Errorf("no manifests found in %s", strings.TrimPrefix(dir, kustomizeRepositoryDir))
+	}
+	slices.Sort(resources)
+	return writeKustomization(fSys, dir, &kustomizetypes.Kustomization{
+		TypeMeta: kustomizetypes.TypeMeta{
+			APIVersion: kustomizetypes.KustomizationVersion,
+			Kind:       kustomizetypes.KustomizationKind,
+		},
+		Resources: resources,
+	})
+}
+
+// isManifest returns whether the data consists of Kubernetes objects only, which all have an apiVersion and a kind.
+func isManifest(data []byte) bool {
+	nodes, err := kio.FromBytes(data)
+	if err != nil || len(nodes) == 0 {
+		return false
+	}
+	for _, node := range nodes {
+		if node.GetApiVersion() == "" || node.GetKind() == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func writeKustomization(fSys filesys.FileSystem, dir string, kustomization *kustomizetypes.Kustomization) error {
+	data, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(path.Join(dir, konfig.DefaultKustomizationFileName()), data)
+}
+
+// pluginOptionValuesManifest returns the object providing the PluginOption values as source for replacements.
+// The values are nested the same way as the values of a Helm chart.
+func pluginOptionValuesManifest(pluginValues []greenhousev1alpha1.PluginOptionValue) ([]byte, error) {
+	values, err := convertFlatValuesToHelmValues(pluginValues)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(map[string]any{
+		"apiVersion": greenhousev1alpha1.GroupVersion.String(),
+		"kind":       pluginOptionValuesKind,
+		"metadata": map[string]any{
+			"name":        pluginOptionValuesName,
+			"annotations": map[string]string{konfig.IgnoredByKustomizeAnnotation: "true"},
+		},
+		"values": values,
+	})
+}
+
+// kustomizeReplacements converts the replacements of the PluginDefinition to Kustomize replacements sourced from the PluginOption values.
+// Replacements for options without a value are skipped.
+func kustomizeReplacements(replacements []greenhousev1alpha1.ManifestReplacement, pluginValues []greenhousev1alpha1.PluginOptionValue) []kustomizetypes.ReplacementField {
+	var fields []kustomizetypes.ReplacementField
+	for _, r := range replacements {
+		if !slices.ContainsFunc(pluginValues, func(v greenhousev1alpha1.PluginOptionValue) bool { return v.Name == r.Option }) {
+			continue
+		}
+		replacement := kustomizetypes.Replacement{
+			Source: &kustomizetypes.SourceSelector{
+				ResId: resid.NewResId(resid.Gvk{
+					Group:   greenhousev1alpha1.GroupVersion.Group,
+					Version: greenhousev1alpha1.GroupVersion.Version,
+					Kind:    pluginOptionValuesKind,
+				}, pluginOptionValuesName),
+				FieldPath: "values." + r.Option,
+			},
+		}
+		for _, t := range r.Targets {
+			replacement.Targets = append(replacement.Targets, &kustomizetypes.TargetSelector{
+				Select: &kustomizetypes.Selector{
+					ResId: resid.NewResIdWithNamespace(resid.Gvk{
+						Group:   t.Select.Group,
+						Version: t.Select.Version,
+						Kind:    t.Select.Kind,
+					}, t.Select.Name, t.Select.Namespace),
+				},
+				FieldPaths: t.FieldPaths,
+				Options:    &kustomizetypes.FieldOptions{Create: t.Create},
+			})
+		}
+		fields = append(fields, kustomizetypes.ReplacementField{Replacement: replacement})
+	}
+	return fields
+}
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// checkoutFs is a Kustomize filesystem reading the checkout of a repository from disk under kustomizeRepositoryDir.
+// All writes are kept in memory and shadow the checkout, so the shared checkout is never modified.
+// Paths resolving outside of the checkout, also via symbolic links, do not exist.
+type checkoutFs struct {
+	root string
+	mem  filesys.FileSystem
+}
+
+var _ filesys.FileSystem = (*checkoutFs)(nil)
+
+func newCheckoutFs(checkoutDir string) (*checkoutFs, error) {
+	root, err := filepath.EvalSymlinks(checkoutDir)
+	if err != nil {
+		return nil, err
+	}
+	return &checkoutFs{root: root, mem: filesys.MakeFsInMemory()}, nil
+}
+
+// cleanPath returns the absolute, cleaned path in the filesystem. Relative paths are relative to the root of the filesystem.
+func cleanPath(p string) string {
+	return path.Clean("/" + filepath.ToSlash(p))
+}
+
+// diskPath returns the path on disk for a path in the repository directory if it exists within the checkout.
+func (f *checkoutFs) diskPath(p string) (string, bool) {
+	p = cleanPath(p)
+	if p != kustomizeRepositoryDir && !strings.HasPrefix(p, kustomizeRepositoryDir+"/") {
+		return "", false
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(f.root, filepath.FromSlash(strings.TrimPrefix(p, kustomizeRepositoryDir))))
+	if err != nil {
+		return "", false
+	}
+	if resolved != f.root && !strings.HasPrefix(resolved, f.root+string(filepath.Separator)) {
+		return "", false
+	}
+	return resolved, true
+}
+
+func (f *checkoutFs) Create(p string) (filesys.File, error) { return f.mem.Create(p) }
+
+func (f *checkoutFs) Mkdir(p string) error { return f.mem.Mkdir(p) }
+
+func (f *checkoutFs) MkdirAll(p string) error { return f.mem.MkdirAll(p) }
+
+func (f *checkoutFs) RemoveAll(p string) error { return f.mem.RemoveAll(p) }
+
+func (f *checkoutFs) WriteFile(p string, data []byte) error { return f.mem.WriteFile(p, data) }
+
+func (f *checkoutFs) Open(p string) (filesys.File, error) {
+	if diskPath, ok := f.diskPath(p); ok && !f.mem.Exists(p) {
+		return os.Open(diskPath)
+	}
+	return f.mem.Open(p)
+}
+
+func (f *checkoutFs) ReadFile(p string) ([]byte, error) {
+	if diskPath, ok := f.diskPath(p); ok && !f.mem.Exists(p) {
+		return os.ReadFile(diskPath)
+	}
+	return f.mem.ReadFile(p)
+}
+
+func (f *checkoutFs) Exists(p string) bool {
+	_, ok := f.diskPath(p)
+	return ok || f.mem.Exists(p)
+}
+
+func (f *checkoutFs) IsDir(p string) bool {
+	if f.mem.IsDir(p) {
+		return true
+	}
+	diskPath, ok := f.diskPath(p)
+	if !ok {
+		return false
+	}
+	fi, err := os.Stat(diskPath)
+	return err == nil && fi.IsDir()
+}
+
+func (f *checkoutFs) ReadDir(p string) ([]string, error) {
+	if !f.IsDir(p) {
+		return nil, fmt.Errorf("%s is not a directory", p)
+	}
+	var names []string
+	if f.mem.IsDir(p) {
+		memNames, err := f.mem.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, memNames...)
+	}
+	if diskPath, ok := f.diskPath(p); ok {
+		entries, err := os.ReadDir(diskPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
+
+func (f *checkoutFs) CleanedAbs(p string) (filesys.ConfirmedDir, string, error) {
+	p = cleanPath(p)
+	switch {
+	case f.IsDir(p):
+		return filesys.ConfirmedDir(p), "", nil
+	case f.Exists(p):
+		return filesys.ConfirmedDir(path.Dir(p)), path.Base(p), nil
+	default:
+		return "", "", fmt.Errorf("%s does not exist", p)
+	}
+}
+
+func (f *checkoutFs) Glob(pattern string) ([]string, error) {
+	matches, err := f.mem.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	pattern = cleanPath(pattern)
+	if strings.HasPrefix(pattern, kustomizeRepositoryDir+"/") {
+		diskMatches, err := filepath.Glob(filepath.Join(f.root, filepath.FromSlash(strings.TrimPrefix(pattern, kustomizeRepositoryDir))))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range filesys.RemoveHiddenFiles(diskMatches) {
+			rel, err := filepath.Rel(f.root, m)
+			if err != nil {
+				return nil, err
+			}
+			p := path.Join(kustomizeRepositoryDir, filepath.ToSlash(rel))
+			if _, ok := f.diskPath(p); ok {
+				matches = append(matches, p)
+			}
+		}
+	}
+	slices.Sort(matches)
+	return slices.Compact(matches), nil
+}
+
+// Walk walks the files in the checkout followed by the files only present in memory. Symbolic links in the checkout are not followed.
+func (f *checkoutFs) Walk(p string, walkFn filepath.WalkFunc) error {
+	p = cleanPath(p)
+	if diskPath, ok := f.diskPath(p); ok {
+		err := filepath.Walk(diskPath, func(dp string, info fs.FileInfo, err error) error {
+			rel, relErr := filepath.Rel(diskPath, dp)
+			if relErr != nil {
+				return relErr
+			}
+			return walkFn(path.Join(p, filepath.ToSlash(rel)), info, err)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if !f.mem.Exists(p) {
+		if _, ok := f.diskPath(p); ok {
+			return nil
+		}
+	}
+	return f.mem.Walk(p, func(mp string, info fs.FileInfo, err error) error {
+		if _, ok := f.diskPath(mp); ok {
+			return nil
+		}
+		return walkFn(mp, info, err)
+	})
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	greenhousesapv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/helm"
+	"github.com/cloudoperators/greenhouse/pkg/test"
+)
+
+const (
+	priorityClassManifest = `apiVersion: scheduling.k8s.io/v1
+kind: PriorityClass
+metadata:
+  name: important
+value: 1000
+`
+	deploymentManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0.0
+`
+)
+
+var _ = Describe("manifest rendering", func() {
+	var (
+		repositoryDir string
+		writeFile     = func(path, content string) {
+			path = filepath.Join(repositoryDir, path)
+			Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed(), "there should be no error creating the directory")
+			Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed(), "there should be no error writing the file")
+		}
+	)
+
+	BeforeEach(func() {
+		repositoryDir = GinkgoT().TempDir()
+	})
+
+	It("should render all manifests in the path", func() {
+		writeFile("manifests/priorityclass.yaml", priorityClassManifest)
+		writeFile("manifests/apps/deployment.yml", deploymentManifest)
+		writeFile("manifests/README.md", "not a manifest")
+
+		rendered, err := helm.ExportRenderManifests(repositoryDir, "manifests", nil, nil)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error rendering the manifests")
+		Expect(string(rendered)).To(ContainSubstring("kind: PriorityClass"), "the rendered manifests should contain the PriorityClass")
+		Expect(string(rendered)).To(ContainSubstring("kind: Deployment"), "the rendered manifests should contain the Deployment")
+		Expect(string(rendered)).ToNot(ContainSubstring("PluginOptionValues"), "the rendered manifests should not contain the PluginOption values")
+	})
+
+	It("should skip files that do not contain Kubernetes objects", func() {
+		writeFile("manifests/priorityclass.yaml", priorityClassManifest)
+		writeFile("manifests/values.yaml", "replicas: 3\n")
+
+		rendered, err := helm.ExportRenderManifests(repositoryDir, "manifests", nil, nil)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error rendering the manifests")
+		Expect(string(rendered)).To(ContainSubstring("kind: PriorityClass"), "the rendered manifests should contain the PriorityClass")
+		Expect(string(rendered)).ToNot(ContainSubstring("replicas: 3"), "the values file should not be rendered")
+		Expect(filepath.Join(repositoryDir, "manifests", "kustomization.yaml")).ToNot(BeAnExistingFile(), "the generated kustomization should not be written to the checkout")
+	})
+
+	It("should not read files outside of the checkout via symbolic links", func() {
+		outsideDir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(outsideDir, "deployment.yaml"), []byte(deploymentManifest), 0o600)).To(Succeed(), "there should be no error writing the file outside of the checkout")
+		writeFile("manifests/priorityclass.yaml", priorityClassManifest)
+		Expect(os.Symlink(filepath.Join(outsideDir, "deployment.yaml"), filepath.Join(repositoryDir, "manifests", "deployment.yaml"))).To(Succeed(), "there should be no error creating the symbolic link")
+
+		rendered, err := helm.ExportRenderManifests(repositoryDir, "manifests", nil, nil)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error rendering the manifests")
+		Expect(string(rendered)).ToNot(ContainSubstring("kind: Deployment"), "the file outside of the checkout should not be rendered")
+	})
+
+	It("should build a kustomization referencing a base outside of the path", func() {
+		writeFile("base/deployment.yaml", deploymentManifest)
+		writeFile("base/kustomization.yaml", "resources:\n- deployment.yaml\n")
+		writeFile("overlays/prod/kustomization.yaml", "resources:\n- ../../base\nnamePrefix: prod-\n")
+
+		rendered, err := helm.ExportRenderManifests(repositoryDir, "overlays/prod", nil, nil)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error rendering the kustomization")
+		Expect(string(rendered)).To(ContainSubstring("name: prod-app"), "the overlay should be applied")
+	})
+
+	It("should write PluginOption values into the manifests", func() {
+		writeFile("deployment.yaml", deploymentManifest)
+		replacements := []greenhousesapv1alpha1.ManifestReplacement{
+			{
+				Option: "replicas",
+				Targets: []greenhousesapv1alpha1.ManifestReplacementTarget{{
+					Select:     greenhousesapv1alpha1.ManifestObjectSelector{Kind: "Deployment", Name: "app"},
+					FieldPaths: []string{"spec.replicas"},
+				}},
+			},
+			{
+				Option: "image.name",
+				Targets: []greenhousesapv1alpha1.ManifestReplacementTarget{{
+					Select:     greenhousesapv1alpha1.ManifestObjectSelector{Kind: "Deployment"},
+					FieldPaths: []string{"spec.template.spec.containers.[name=app].image"},
+				}},
+			},
+			{
+				Option: "unset",
+				Targets: []greenhousesapv1alpha1.ManifestReplacementTarget{{
+					Select:     greenhousesapv1alpha1.ManifestObjectSelector{Kind: "Deployment"},
+					FieldPaths: []string{"metadata.labels.unset"},
+					Create:     true,
+				}},
+			},
+		}
+		pluginValues := []greenhousesapv1alpha1.PluginOptionValue{
+			{Name: "replicas", Value: test.MustReturnJSONFor(3)},
+			{Name: "image.name", Value: test.MustReturnJSONFor("app:2.0.0")},
+		}
+
+		rendered, err := helm.ExportRenderManifests(repositoryDir, "", replacements, pluginValues)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error rendering the manifests")
+		Expect(string(rendered)).To(ContainSubstring("replicas: 3"), "the replicas should be replaced")
+		Expect(string(rendered)).To(ContainSubstring("image: app:2.0.0"), "the image should be replaced")
+		Expect(string(rendered)).ToNot(ContainSubstring("unset"), "replacements of options without a value should be skipped")
+	})
+
+	It("should fail if the path does not contain manifests", func() {
+		writeFile("manifests/README.md", "not a manifest")
+
+		_, err := helm.ExportRenderManifests(repositoryDir, "manifests", nil, nil)
+		Expect(err).To(HaveOccurred(), "there should be an error rendering a path without manifests")
+	})
+})
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	chartCacheResultHit    = "hit"
+	chartCacheResultMiss   = "miss"
+	chartCacheResultBypass = "bypass"
+)
+
+var (
+	chartMetricCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "greenhouse_helm_chart_cache_requests_total",
+			Help: "Number of chart lookups in the chart cache by result.",
+		},
+		[]string{"result"})
+
+	chartMetricCacheEvictions = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "greenhouse_helm_chart_cache_evictions_total",
+			Help: "Number of chart archives evicted from the chart cache.",
+		})
+
+	chartMetricCacheSize = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "greenhouse_helm_chart_cache_size_bytes",
+			Help: "Size of all chart archives in the chart cache.",
+		})
+)
+
+func init() {
+	metrics.Registry.MustRegister(chartMetricCacheRequests)
+	metrics.Registry.MustRegister(chartMetricCacheEvictions)
+	metrics.Registry.MustRegister(chartMetricCacheSize)
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+const (
+	// maxReleaseNotesBytes is the maximum size of the release notes reported in the Plugin status.
+	maxReleaseNotesBytes = 4 * 1024
+	// maxReleaseObjectKinds is the maximum number of object kinds reported in the Plugin status.
+	maxReleaseObjectKinds = 50
+
+	releaseNotesTruncatedSuffix = "\n[truncated]"
+)
+
+// AppVersionFromRelease returns the appVersion of the chart of the release.
+func AppVersionFromRelease(r *release.Release) string {
+	if r == nil || r.Chart == nil || r.Chart.Metadata == nil {
+		return ""
+	}
+	return r.Chart.Metadata.AppVersion
+}
+
+// NotesFromRelease returns the rendered notes of the release truncated to maxReleaseNotesBytes.
+func NotesFromRelease(r *release.Release) string {
+	if r == nil || r.Info == nil {
+		return ""
+	}
+	notes := r.Info.Notes
+	if len(notes) <= maxReleaseNotesBytes {
+		return notes
+	}
+	// Do not cut a multi-byte character in half.
+	cut := maxReleaseNotesBytes - len(releaseNotesTruncatedSuffix)
+	for cut > 0 && !utf8.RuneStart(notes[cut]) {
+		cut--
+	}
+	return strings.ToValidUTF8(notes[:cut], "") + releaseNotesTruncatedSuffix
+}
+
+// ObjectCountsFromRelease returns the number of objects per apiVersion and kind in the release manifest.
+// The counts are sorted by apiVersion and kind and limited to maxReleaseObjectKinds entries.
+func ObjectCountsFromRelease(r *release.Release) []greenhousev1alpha1.ReleaseObjectCount {
+	if r == nil || r.Manifest == "" {
+		return nil
+	}
+	type typeMeta struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	counts := make(map[typeMeta]int32)
+	for _, manifest := range releaseutil.SplitManifests(r.Manifest) {
+		var tm typeMeta
+		if err := yaml.Unmarshal([]byte(manifest), &tm); err != nil || tm.Kind == "" {
+			continue
+		}
+		counts[tm]++
+	}
+	objectCounts := make([]greenhousev1alpha1.ReleaseObjectCount, 0, len(counts))
+	for tm, count := range counts {
+		objectCounts = append(objectCounts, greenhousev1alpha1.ReleaseObjectCount{APIVersion: tm.APIVersion, Kind: tm.Kind, Count: count})
+	}
+	slices.SortFunc(objectCounts, func(a, b greenhousev1alpha1.ReleaseObjectCount) int {
+		return cmp.Or(cmp.Compare(a.APIVersion, b.APIVersion), cmp.Compare(a.Kind, b.Kind))
+	})
+	if len(objectCounts) > maxReleaseObjectKinds {
+		objectCounts = objectCounts[:maxReleaseObjectKinds]
+	}
+	return objectCounts
+}
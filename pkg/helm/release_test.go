@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm_test
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+
+	greenhousesapv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/helm"
+)
+
+var _ = Describe("release metadata", func() {
+	It("should return the appVersion of the chart", func() {
+		r := &release.Release{Chart: &chart.Chart{Metadata: &chart.Metadata{AppVersion: "1.2.3"}}}
+		Expect(helm.AppVersionFromRelease(r)).To(Equal("1.2.3"), "the appVersion of the chart should be returned")
+		Expect(helm.AppVersionFromRelease(&release.Release{})).To(BeEmpty(), "an empty appVersion should be returned for a release without chart")
+	})
+
+	It("should truncate long notes", func() {
+		Expect(helm.NotesFromRelease(&release.Release{Info: &release.Info{Notes: "visit https://example.com"}})).
+			To(Equal("visit https://example.com"), "short notes should be returned as they are")
+
+		notes := helm.NotesFromRelease(&release.Release{Info: &release.Info{Notes: strings.Repeat("ä", 4096)}})
+		Expect(len(notes)).To(BeNumerically("<=", 4096), "the notes should be truncated")
+		Expect(notes).To(HaveSuffix("[truncated]"), "truncated notes should be marked")
+		Expect(utf8.ValidString(notes)).To(BeTrue(), "truncated notes should be valid UTF-8")
+
+		notes = helm.NotesFromRelease(&release.Release{Info: &release.Info{Notes: "\xff" + strings.Repeat("a", 4096)}})
+		Expect(notes).To(HavePrefix(strings.Repeat("a", 4000)), "text after an invalid byte should be kept")
+	})
+
+	It("should count the objects per kind", func() {
+		r := &release.Release{Manifest: `---
+# Source: chart/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: one
+---
+# Source: chart/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: two
+---
+# Source: chart/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`}
+		Expect(helm.ObjectCountsFromRelease(r)).To(Equal([]greenhousesapv1alpha1.ReleaseObjectCount{
+			{APIVersion: "apps/v1", Kind: "Deployment", Count: 1},
+			{APIVersion: "v1", Kind: "ConfigMap", Count: 2},
+		}), "the objects should be counted per apiVersion and kind")
+	})
+})
@@ -17,7 +17,7 @@ func OrganizationAdminPolicyRules() []rbacv1.PolicyRule {
 		{
 			Verbs:     []string{"get", "list", "watch", "update", "patch", "delete", "create"},
 			APIGroups: []string{greenhouseapisv1alpha1.GroupVersion.Group},
-			Resources: []string{"teams", "teammemberships"},
+			Resources: []string{"teams", "teammemberships", "resourcepropagations"},
 		},
 		// Grant permissions for secrets referenced by other resources, e.g. Plugins for storing sensitive values.
 		// Retrieving these secrets is not permitted to the user.
@@ -97,7 +97,7 @@ func OrganizationMemberPolicyRules() []rbacv1.PolicyRule {
 		{
 			Verbs:     []string{"get", "list", "watch"},
 			APIGroups: []string{greenhouseapisv1alpha1.GroupVersion.Group},
-			Resources: []string{"clusters", "clusterkubeconfigs", "plugins", "pluginpresets", "teams", "teammemberships", "teamroles", "teamrolebindings"},
+			Resources: []string{"clusters", "clusterkubeconfigs", "plugins", "pluginpresets", "teams", "teammemberships", "teamroles", "teamrolebindings", "resourcepropagations"},
 		},
 	}
 }
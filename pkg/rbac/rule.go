@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rbac
+
+import (
+	"slices"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// RulesAllow returns true if any of the PolicyRules allows the request to the resource.
+func RulesAllow(rules []rbacv1.PolicyRule, attrs *authorizationv1.ResourceAttributes) bool {
+	return slices.ContainsFunc(rules, func(rule rbacv1.PolicyRule) bool {
+		return RuleAllows(rule, attrs)
+	})
+}
+
+// RuleAllows returns true if the PolicyRule allows the request to the resource, following the matching of the Kubernetes RBAC authorizer.
+func RuleAllows(rule rbacv1.PolicyRule, attrs *authorizationv1.ResourceAttributes) bool {
+	return matchesValue(rule.Verbs, attrs.Verb) &&
+		matchesValue(rule.APIGroups, attrs.Group) &&
+		matchesResource(rule.Resources, attrs.Resource, attrs.Subresource) &&
+		(len(rule.ResourceNames) == 0 || slices.Contains(rule.ResourceNames, attrs.Name))
+}
+
+// matchesValue returns true if the values contain the value or the wildcard.
+func matchesValue(values []string, value string) bool {
+	return slices.Contains(values, "*") || slices.Contains(values, value)
+}
+
+// matchesResource returns true if the resources contain the resource and subresource, or a wildcard matching them.
+func matchesResource(resources []string, resource, subresource string) bool {
+	combined := resource
+	if subresource != "" {
+		combined = resource + "/" + subresource
+	}
+	for _, r := range resources {
+		switch {
+		case r == rbacv1.ResourceAll, r == combined:
+			return true
+		case subresource != "" && r == "*/"+subresource:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rbac
+
+import (
+	"slices"
+
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+// AncestorTeams returns the names of the parent Teams of the Team up to the root, starting with the direct parent.
+// A cycle in the parent references ends the list before the first repeated Team.
+func AncestorTeams(teamName string, teams []greenhouseapisv1alpha1.Team) []string {
+	var ancestors []string
+	visited := []string{teamName}
+	for {
+		idx := slices.IndexFunc(teams, func(team greenhouseapisv1alpha1.Team) bool { return team.GetName() == teamName })
+		if idx < 0 {
+			return ancestors
+		}
+		parent := teams[idx].Spec.ParentTeamRef
+		if parent == "" || slices.Contains(visited, parent) {
+			return ancestors
+		}
+		ancestors = append(ancestors, parent)
+		visited = append(visited, parent)
+		teamName = parent
+	}
+}
+
+// DescendantTeams returns the names of the child Teams of the Team and their descendants.
+func DescendantTeams(teamName string, teams []greenhouseapisv1alpha1.Team) []string {
+	var descendants []string
+	parents := []string{teamName}
+	for len(parents) > 0 {
+		var children []string
+		for _, team := range teams {
+			if slices.Contains(parents, team.Spec.ParentTeamRef) && team.GetName() != teamName && !slices.Contains(descendants, team.GetName()) {
+				children = append(children, team.GetName())
+				descendants = append(descendants, team.GetName())
+			}
+		}
+		parents = children
+	}
+	return descendants
+}
+
+// HasTeamCycle returns true if following the parent references from the Team leads back to the Team.
+func HasTeamCycle(team *greenhouseapisv1alpha1.Team, teams []greenhouseapisv1alpha1.Team) bool {
+	visited := []string{team.GetName()}
+	for parent := team.Spec.ParentTeamRef; parent != ""; {
+		if slices.Contains(visited, parent) {
+			return parent == team.GetName()
+		}
+		visited = append(visited, parent)
+		idx := slices.IndexFunc(teams, func(t greenhouseapisv1alpha1.Team) bool { return t.GetName() == parent })
+		if idx < 0 {
+			return false
+		}
+		parent = teams[idx].Spec.ParentTeamRef
+	}
+	return false
+}
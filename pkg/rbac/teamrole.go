@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package rbac
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhouseapisv1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+var (
+	// DefaultSensitiveResources are the resources allowing privilege escalation if granted with any verb.
+	DefaultSensitiveResources = []string{"pods/exec", "pods/attach", "secrets", "serviceaccounts/token", "nodes/proxy"}
+	// DefaultSensitiveVerbs are the verbs allowing privilege escalation if granted on any resource.
+	DefaultSensitiveVerbs = []string{"*", "escalate", "bind", "impersonate"}
+	// DefaultExemptTeamRoles are the TeamRoles not checked, if the TeamRolePolicy does not name any.
+	DefaultExemptTeamRoles = []string{"cluster-admin"}
+)
+
+// TeamRoleLabels returns the labels of the ClusterRole rendered from the TeamRole.
+func TeamRoleLabels(teamRole *greenhouseapisv1alpha1.TeamRole) map[string]string {
+	roleLabels := maps.Clone(teamRole.Spec.Labels)
+	if roleLabels == nil {
+		roleLabels = make(map[string]string, 1)
+	}
+	roleLabels[greenhouseapis.LabelKeyRole] = teamRole.GetName()
+	return roleLabels
+}
+
+// AggregationRuleSelects returns true if any of the ClusterRoleSelectors of the AggregationRule selects the labels.
+func AggregationRuleSelects(aggregationRule *rbacv1.AggregationRule, roleLabels map[string]string) (bool, error) {
+	if aggregationRule == nil {
+		return false, nil
+	}
+	for _, clusterRoleSelector := range aggregationRule.ClusterRoleSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(&clusterRoleSelector)
+		if err != nil {
+			return false, err
+		}
+		if !selector.Empty() && selector.Matches(labels.Set(roleLabels)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EffectiveTeamRoleRules returns the rules of the TeamRole. The rules of a TeamRole with an AggregationRule are the rules of the TeamRoles selected by it.
+func EffectiveTeamRoleRules(teamRole *greenhouseapisv1alpha1.TeamRole, teamRoles []greenhouseapisv1alpha1.TeamRole) ([]rbacv1.PolicyRule, error) {
+	return effectiveTeamRoleRules(teamRole, teamRoles, nil)
+}
+
+func effectiveTeamRoleRules(teamRole *greenhouseapisv1alpha1.TeamRole, teamRoles []greenhouseapisv1alpha1.TeamRole, visited []string) ([]rbacv1.PolicyRule, error) {
+	if teamRole.Spec.AggregationRule == nil {
+		return teamRole.Spec.Rules, nil
+	}
+	visited = append(slices.Clone(visited), teamRole.GetName())
+	var rules []rbacv1.PolicyRule
+	for _, other := range teamRoles {
+		if slices.Contains(visited, other.GetName()) {
+			continue
+		}
+		selected, err := AggregationRuleSelects(teamRole.Spec.AggregationRule, TeamRoleLabels(&other))
+		if err != nil {
+			return nil, err
+		}
+		if !selected {
+			continue
+		}
+		otherRules, err := effectiveTeamRoleRules(&other, teamRoles, visited)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, otherRules...)
+	}
+	return rules, nil
+}
+
+// LintTeamRole returns the findings for the effective rules of the TeamRole violating the TeamRolePolicy.
+// The TeamRoles aggregating the TeamRole are named in an additional finding, as their effective rules are affected as well.
+func LintTeamRole(teamRole *greenhouseapisv1alpha1.TeamRole, teamRoles []greenhouseapisv1alpha1.TeamRole, policy *greenhouseapisv1alpha1.TeamRolePolicy) ([]string, error) {
+	sensitiveResources, sensitiveVerbs, exemptTeamRoles := DefaultSensitiveResources, DefaultSensitiveVerbs, DefaultExemptTeamRoles
+	if policy != nil {
+		// An empty list set explicitly exempts no TeamRole.
+		if policy.ExemptTeamRoles != nil {
+			exemptTeamRoles = policy.ExemptTeamRoles
+		}
+		if len(policy.SensitiveResources) > 0 {
+			sensitiveResources = policy.SensitiveResources
+		}
+		if len(policy.SensitiveVerbs) > 0 {
+			sensitiveVerbs = policy.SensitiveVerbs
+		}
+	}
+	if slices.Contains(exemptTeamRoles, teamRole.GetName()) {
+		return nil, nil
+	}
+	rules, err := EffectiveTeamRoleRules(teamRole, teamRoles)
+	if err != nil {
+		return nil, err
+	}
+	findings := LintRules(rules, sensitiveResources, sensitiveVerbs)
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	var aggregatedBy []string
+	for _, other := range teamRoles {
+		if other.GetName() == teamRole.GetName() {
+			continue
+		}
+		selected, err := AggregationRuleSelects(other.Spec.AggregationRule, TeamRoleLabels(teamRole))
+		if err != nil {
+			return nil, err
+		}
+		if selected {
+			aggregatedBy = append(aggregatedBy, other.GetName())
+		}
+	}
+	if len(aggregatedBy) > 0 {
+		findings = append(findings, "is aggregated into the TeamRoles "+strings.Join(aggregatedBy, ", "))
+	}
+	return findings, nil
+}
+
+// LintRules returns a finding for every sensitive verb granted on any resource and every sensitive resource granted with any verb.
+// Sensitive resources are given in the format resource[.group][/subresource], the verb * refers to rules granting all verbs.
+func LintRules(rules []rbacv1.PolicyRule, sensitiveResources, sensitiveVerbs []string) []string {
+	var findings []string
+	addFinding := func(finding string) {
+		if !slices.Contains(findings, finding) {
+			findings = append(findings, finding)
+		}
+	}
+	for _, rule := range rules {
+		var grantedVerbs []string
+		for _, verb := range sensitiveVerbs {
+			if matchesValue(rule.Verbs, verb) {
+				grantedVerbs = append(grantedVerbs, verb)
+			}
+		}
+		// A rule granting all verbs is reported once if that is sensitive.
+		if slices.Contains(rule.Verbs, "*") && slices.Contains(grantedVerbs, "*") {
+			grantedVerbs = []string{"*"}
+		}
+		if len(grantedVerbs) > 0 {
+			addFinding(fmt.Sprintf("grants the sensitive verbs %s on %s", strings.Join(grantedVerbs, ","), strings.Join(rule.Resources, ",")))
+		}
+		for _, sensitiveResource := range sensitiveResources {
+			resource, group, subresource := ParseResource(sensitiveResource)
+			if len(rule.Verbs) > 0 && matchesValue(rule.APIGroups, group) && matchesResource(rule.Resources, resource, subresource) {
+				addFinding(fmt.Sprintf("grants %s on the sensitive resource %s", strings.Join(rule.Verbs, ","), sensitiveResource))
+			}
+		}
+	}
+	return findings
+}
+
+// ParseResource splits the resource in the format resource[.group][/subresource] used by kubectl.
+func ParseResource(value string) (resource, group, subresource string) {
+	resource, subresource, _ = strings.Cut(value, "/")
+	resource, group, _ = strings.Cut(resource, ".")
+	return resource, group, subresource
+}
@@ -5,6 +5,7 @@ package test
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 
@@ -305,6 +306,12 @@ func WithNamespaces(namespaces ...string) func(*greenhousev1alpha1.TeamRoleBindi
 	}
 }
 
+func WithNamespaceSelector(selector metav1.LabelSelector) func(*greenhousev1alpha1.TeamRoleBinding) {
+	return func(trb *greenhousev1alpha1.TeamRoleBinding) {
+		trb.Spec.NamespaceSelector = &selector
+	}
+}
+
 func WithCreateNamespace(createNamespaces bool) func(*greenhousev1alpha1.TeamRoleBinding) {
 	return func(trb *greenhousev1alpha1.TeamRoleBinding) {
 		trb.Spec.CreateNamespaces = createNamespaces
@@ -317,6 +324,24 @@ func WithUsernames(usernames []string) func(*greenhousev1alpha1.TeamRoleBinding)
 	}
 }
 
+func WithExpiresAt(expiresAt time.Time) func(*greenhousev1alpha1.TeamRoleBinding) {
+	return func(trb *greenhousev1alpha1.TeamRoleBinding) {
+		trb.Spec.ExpiresAt = &metav1.Time{Time: expiresAt}
+	}
+}
+
+func WithIncludeChildTeams(includeChildTeams bool) func(*greenhousev1alpha1.TeamRoleBinding) {
+	return func(trb *greenhousev1alpha1.TeamRoleBinding) {
+		trb.Spec.IncludeChildTeams = includeChildTeams
+	}
+}
+
+func WithIncludeTeamMembers(includeTeamMembers bool) func(*greenhousev1alpha1.TeamRoleBinding) {
+	return func(trb *greenhousev1alpha1.TeamRoleBinding) {
+		trb.Spec.IncludeTeamMembers = includeTeamMembers
+	}
+}
+
 // NewTeamRoleBinding returns a greenhousev1alpha1.TeamRoleBinding object. Opts can be used to set the desired state of the TeamRoleBinding.
 func NewTeamRoleBinding(ctx context.Context, name, namespace string, opts ...func(*greenhousev1alpha1.TeamRoleBinding)) *greenhousev1alpha1.TeamRoleBinding {
 	trb := &greenhousev1alpha1.TeamRoleBinding{
@@ -341,6 +366,18 @@ func WithMappedIDPGroup(group string) func(*greenhousev1alpha1.Team) {
 	}
 }
 
+func WithParentTeamRef(parentTeamRef string) func(*greenhousev1alpha1.Team) {
+	return func(t *greenhousev1alpha1.Team) {
+		t.Spec.ParentTeamRef = parentTeamRef
+	}
+}
+
+func WithMembers(members ...greenhousev1alpha1.TeamMember) func(*greenhousev1alpha1.Team) {
+	return func(t *greenhousev1alpha1.Team) {
+		t.Spec.Members = members
+	}
+}
+
 // NewTeam returns a greenhousev1alpha1.Team object. Opts can be used to set the desired state of the Team.
 func NewTeam(ctx context.Context, name, namespace string, opts ...func(*greenhousev1alpha1.Team)) *greenhousev1alpha1.Team {
 	team := &greenhousev1alpha1.Team{
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http2"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Agent runs in a remote cluster and connects it to Greenhouse.
+// It dials out to the tunnel server and serves connections to the cluster's API server multiplexed over the tunnel.
+// TLS is terminated by the API server, so the agent never sees the credentials used by Greenhouse.
+type Agent struct {
+	// URL of the Greenhouse tunnel server.
+	URL string
+	// Cluster is the namespace and name of the Cluster in Greenhouse.
+	Cluster types.NamespacedName
+	// Token authenticates the agent against Greenhouse.
+	Token string
+	// APIServerAddress is the address of the cluster's API server. All streams are connected to this address.
+	APIServerAddress string
+	// TLSConfig is used to connect to the tunnel server. Defaults to the system trust store.
+	TLSConfig *tls.Config
+	// Backoff between reconnects. Defaults to exponential backoff up to one minute.
+	Backoff *wait.Backoff
+}
+
+// Run keeps the tunnel open until the context is cancelled.
+func (a *Agent) Run(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("agent")
+	backoff := wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 10, Cap: time.Minute}
+	if a.Backoff != nil {
+		backoff = *a.Backoff
+	}
+	current := backoff
+	for {
+		conn, err := a.connect(ctx)
+		if err != nil {
+			logger.Error(err, "failed to connect to Greenhouse")
+		} else {
+			logger.Info("connected to Greenhouse", "url", a.URL)
+			current = backoff
+			a.serve(ctx, conn)
+			logger.Info("disconnected from Greenhouse")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(current.Step()):
+		}
+	}
+}
+
+// connect dials the tunnel server and upgrades the connection.
+func (a *Agent) connect(ctx context.Context) (net.Conn, error) {
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = agentPath(a.Cluster)
+
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), map[string]string{"http": "80", "https": "443"}[u.Scheme])
+	}
+	var conn net.Conn
+	switch u.Scheme {
+	case "https":
+		tlsConfig := a.TLSConfig.Clone()
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = u.Hostname()
+		}
+		// Only HTTP/1.1 supports the upgrade of the connection.
+		tlsConfig.NextProtos = []string{"http/1.1"}
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	case "http":
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", UpgradeProtocol)
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		_ = resp.Body.Close()
+		_ = conn.Close()
+		return nil, fmt.Errorf("tunnel server refused connection: %s", resp.Status)
+	}
+	return newBufferedConn(conn, reader), nil
+}
+
+// serve handles the streams opened by Greenhouse until the connection is closed.
+func (a *Agent) serve(ctx context.Context, conn net.Conn) {
+	server := &http2.Server{
+		ReadIdleTimeout: healthCheckInterval,
+		PingTimeout:     healthCheckTimeout,
+	}
+	server.ServeConn(conn, &http2.ServeConnOpts{
+		Context: ctx,
+		Handler: http.HandlerFunc(a.handleConnect),
+	})
+	_ = conn.Close()
+}
+
+// handleConnect connects a stream with the API server. The requested address is ignored, so the agent cannot be used to reach other endpoints.
+func (a *Agent) handleConnect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	upstream, err := (&net.Dialer{Timeout: 10 * time.Second}).DialContext(r.Context(), "tcp", a.APIServerAddress)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer upstream.Close()
+
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	pipe(upstream, &streamReadWriter{Reader: r.Body, Writer: flushWriter{w: w}})
+}
+
+// streamReadWriter combines the request and response of a CONNECT stream.
+type streamReadWriter struct {
+	io.Reader
+	io.Writer
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// streamConn is a net.Conn on top of a CONNECT stream of the tunnel.
+// Deadlines are not supported, the stream is closed by closing the connection or cancelling the dial context.
+type streamConn struct {
+	reader    io.ReadCloser
+	writer    *io.PipeWriter
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+
+	local, remote net.Addr
+}
+
+var _ net.Conn = &streamConn{}
+
+func (c *streamConn) Read(b []byte) (int, error)  { return c.reader.Read(b) }
+func (c *streamConn) Write(b []byte) (int, error) { return c.writer.Write(b) }
+
+func (c *streamConn) Close() error {
+	c.closeOnce.Do(func() {
+		_ = c.writer.Close()
+		_ = c.reader.Close()
+		c.cancel()
+	})
+	return nil
+}
+
+func (c *streamConn) LocalAddr() net.Addr                { return c.local }
+func (c *streamConn) RemoteAddr() net.Addr               { return c.remote }
+func (c *streamConn) SetDeadline(_ time.Time) error      { return nil }
+func (c *streamConn) SetReadDeadline(_ time.Time) error  { return nil }
+func (c *streamConn) SetWriteDeadline(_ time.Time) error { return nil }
+
+// tunnelAddr is the address of a stream endpoint.
+type tunnelAddr string
+
+func (a tunnelAddr) Network() string { return "tunnel" }
+func (a tunnelAddr) String() string  { return string(a) }
+
+// bufferedConn is a net.Conn reading data already buffered while reading the HTTP upgrade response first.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) { return c.reader.Read(b) }
+
+// newBufferedConn returns the connection itself if nothing was buffered.
+func newBufferedConn(conn net.Conn, reader *bufio.Reader) net.Conn {
+	if reader == nil || reader.Buffered() == 0 {
+		return conn
+	}
+	return &bufferedConn{Conn: conn, reader: reader}
+}
+
+// notifyConn closes the done channel once the connection is closed.
+type notifyConn struct {
+	net.Conn
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newNotifyConn(conn net.Conn) *notifyConn {
+	return &notifyConn{Conn: conn, done: make(chan struct{})}
+}
+
+func (c *notifyConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(func() { close(c.done) })
+	return err
+}
+
+// flushWriter flushes every write, so data of a CONNECT stream is not delayed.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f flushWriter) Write(b []byte) (int, error) {
+	n, err := f.w.Write(b)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+// pipe copies data in both directions until one side is closed.
+func pipe(a io.ReadWriter, b io.ReadWriter) {
+	errs := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(a, b)
+		errs <- err
+	}()
+	go func() {
+		_, err := io.Copy(b, a)
+		errs <- err
+	}()
+	// The first finished direction ends the connection, the caller closes both sides.
+	<-errs
+}
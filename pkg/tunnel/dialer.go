@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// DefaultProxyAddress is the address of the proxy endpoint exposed by the tunnel Service of the Greenhouse operator.
+	DefaultProxyAddress = "greenhouse-tunnel.greenhouse.svc:8091"
+	// DefaultTokenFile is the ServiceAccount token presented to the proxy endpoint.
+	DefaultTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// DefaultDialer is used to access clusters with access mode agent.
+// The Greenhouse operator sets the Registry of its tunnel server, other components set the ProxyAddress of the operator.
+var DefaultDialer = &Dialer{}
+
+// Dialer dials addresses in a cluster through the tunnel of its agent.
+type Dialer struct {
+	// Registry holds the tunnels if the tunnel server runs in the same process.
+	Registry *Registry
+	// ProxyAddress is the address of the tunnel server's proxy endpoint. It is used if no Registry is set.
+	ProxyAddress string
+	// TokenFile contains the ServiceAccount token presented to the proxy endpoint.
+	// It is read on every dial, as projected tokens are rotated.
+	TokenFile string
+}
+
+// DialFunc returns a dial function for the cluster, e.g. to be used in a rest.Config.
+func (d *Dialer) DialFunc(cluster types.NamespacedName) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, _, address string) (net.Conn, error) {
+		return d.DialContext(ctx, cluster, address)
+	}
+}
+
+// DialContext connects to the address in the cluster through the tunnel of the cluster agent.
+func (d *Dialer) DialContext(ctx context.Context, cluster types.NamespacedName, address string) (net.Conn, error) {
+	switch {
+	case d.Registry != nil:
+		return d.Registry.DialContext(ctx, cluster, address)
+	case d.ProxyAddress != "":
+		return d.dialProxy(ctx, cluster, address)
+	default:
+		return nil, errors.New("no tunnel configured to access clusters with access mode agent")
+	}
+}
+
+// dialProxy issues a CONNECT request to the proxy endpoint of the tunnel server.
+func (d *Dialer) dialProxy(ctx context.Context, cluster types.NamespacedName, address string) (net.Conn, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", d.ProxyAddress)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodConnect, "http://"+address, http.NoBody)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	req.Host = address
+	req.Header.Set(ClusterHeader, cluster.String())
+	if d.TokenFile != "" {
+		token, err := os.ReadFile(d.TokenFile)
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to read token for the tunnel proxy: %w", err)
+		}
+		req.Header.Set("Proxy-Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("tunnel proxy refused connection to cluster %s: %s", cluster.String(), resp.Status)
+	}
+	return newBufferedConn(conn, reader), nil
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http2"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ErrNotConnected is returned if the agent of a cluster is not connected.
+var ErrNotConnected = errors.New("cluster agent is not connected")
+
+// Registry holds the tunnel connections of the cluster agents.
+// Greenhouse is the HTTP/2 client on the connection dialed by the agent. Every connection to the API server of a cluster is a CONNECT stream multiplexed over the tunnel.
+type Registry struct {
+	mu          sync.RWMutex
+	connections map[types.NamespacedName]*agentConnection
+}
+
+type agentConnection struct {
+	clientConn     *http2.ClientConn
+	conn           net.Conn
+	connectedSince time.Time
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{connections: make(map[types.NamespacedName]*agentConnection)}
+}
+
+// register adds the connection of the cluster agent. A previous connection of the same cluster is closed.
+func (r *Registry) register(cluster types.NamespacedName, ac *agentConnection) {
+	r.mu.Lock()
+	previous := r.connections[cluster]
+	r.connections[cluster] = ac
+	r.mu.Unlock()
+	if previous != nil {
+		_ = previous.conn.Close()
+	}
+}
+
+// unregister removes the connection of the cluster agent unless it was already replaced by a newer connection.
+func (r *Registry) unregister(cluster types.NamespacedName, ac *agentConnection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.connections[cluster] == ac {
+		delete(r.connections, cluster)
+	}
+}
+
+func (r *Registry) get(cluster types.NamespacedName) (*agentConnection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ac, ok := r.connections[cluster]
+	if !ok || ac.clientConn.State().Closed {
+		return nil, fmt.Errorf("%w: %s", ErrNotConnected, cluster.String())
+	}
+	return ac, nil
+}
+
+// ConnectedSince returns when the agent of the cluster connected and whether it is currently connected.
+func (r *Registry) ConnectedSince(cluster types.NamespacedName) (time.Time, bool) {
+	ac, err := r.get(cluster)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ac.connectedSince, true
+}
+
+// Ping checks the health of the tunnel to the agent of the cluster.
+func (r *Registry) Ping(ctx context.Context, cluster types.NamespacedName) error {
+	ac, err := r.get(cluster)
+	if err != nil {
+		return err
+	}
+	return ac.clientConn.Ping(ctx)
+}
+
+// DialContext opens a connection to the address through the tunnel of the cluster agent.
+// The agent only accepts connections to the API server of its cluster, the address is used for the TLS handshake only.
+func (r *Registry) DialContext(ctx context.Context, cluster types.NamespacedName, address string) (net.Conn, error) {
+	ac, err := r.get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	// The stream outlives the dial, it is closed with the returned connection.
+	streamCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	body, bodyWriter := io.Pipe()
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodConnect, "https://"+address, body)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	req.Host = address
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		resp, err := ac.clientConn.RoundTrip(req)
+		results <- result{resp: resp, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		cancel()
+		_ = bodyWriter.Close()
+		return nil, ctx.Err()
+	case res := <-results:
+		if res.err != nil {
+			cancel()
+			_ = bodyWriter.Close()
+			return nil, fmt.Errorf("failed to open stream to cluster %s: %w", cluster.String(), res.err)
+		}
+		if res.resp.StatusCode != http.StatusOK {
+			cancel()
+			_ = bodyWriter.Close()
+			_ = res.resp.Body.Close()
+			return nil, fmt.Errorf("cluster agent of %s refused connection to %s: %s", cluster.String(), address, res.resp.Status)
+		}
+		return &streamConn{
+			reader: res.resp.Body,
+			writer: bodyWriter,
+			cancel: cancel,
+			local:  tunnelAddr(cluster.String()),
+			remote: tunnelAddr(address),
+		}, nil
+	}
+}
+
+// Close closes all agent connections.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for cluster, ac := range r.connections {
+		_ = ac.conn.Close()
+		delete(r.connections, cluster)
+	}
+}
@@ -0,0 +1,316 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tunnel
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+)
+
+const (
+	// UpgradeProtocol is the protocol the agent connection is upgraded to.
+	UpgradeProtocol = "greenhouse-tunnel"
+	// ClusterHeader identifies the cluster as <namespace>/<name> in CONNECT requests to the proxy endpoint.
+	ClusterHeader = "Greenhouse-Cluster"
+	// ServiceName is the name of the tunnel Service and of its EndpointSlice. The Service has no selector.
+	// The leader publishes its pod IP in the EndpointSlice while it runs the tunnel server, so agents and proxy clients only reach the leader.
+	ServiceName = "greenhouse-tunnel"
+
+	// healthCheckInterval is the interval after which the tunnel is pinged if no frames were received.
+	healthCheckInterval = 30 * time.Second
+	// healthCheckTimeout is the timeout after which the tunnel is closed if the ping is not answered.
+	healthCheckTimeout = 15 * time.Second
+)
+
+// agentPath returns the path agents connect to.
+func agentPath(cluster types.NamespacedName) string {
+	return fmt.Sprintf("/agent/%s/%s", cluster.Namespace, cluster.Name)
+}
+
+// Server accepts the tunnel connections of cluster agents and proxies connections of other Greenhouse components through them.
+// The server must only run in the leader, so all agents connect to the replica running the controllers.
+// The IP of the leader is published in the EndpointSlice of the tunnel Service while the server runs, so the Service does not route to other replicas.
+type Server struct {
+	// Client is used to authenticate the agents against the token stored in the cluster secret,
+	// to authorize the clients of the proxy endpoint and to publish the endpoint of the server.
+	Client client.Client
+	// Registry holds the established tunnels.
+	Registry *Registry
+	// AgentBindAddress is the address the agents connect to. It is expected to be exposed via an ingress terminating TLS.
+	AgentBindAddress string
+	// ProxyBindAddress is the address other Greenhouse components, e.g. the service-proxy, dial clusters through. It must not be exposed.
+	ProxyBindAddress string
+	// EndpointSlice is the EndpointSlice of the tunnel Service. PodIP is published in it while the server runs. Publishing is skipped if the name is empty.
+	EndpointSlice types.NamespacedName
+	// PodIP is the IP of the pod running the server.
+	PodIP string
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface.
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the agent and proxy endpoints until the context is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("tunnel")
+	if err := s.publishEndpoint(ctx); err != nil {
+		return fmt.Errorf("failed to publish tunnel endpoint in %s: %w", s.EndpointSlice.String(), err)
+	}
+	defer func() {
+		if err := s.RemoveEndpoint(context.WithoutCancel(ctx)); err != nil {
+			logger.Error(err, "failed to remove tunnel endpoint", "endpointSlice", s.EndpointSlice.String())
+		}
+	}()
+	var servers []*http.Server
+	if s.AgentBindAddress != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /agent/{namespace}/{name}", s.ServeAgent)
+		servers = append(servers, &http.Server{Addr: s.AgentBindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second})
+	}
+	if s.ProxyBindAddress != "" {
+		servers = append(servers, &http.Server{Addr: s.ProxyBindAddress, Handler: http.HandlerFunc(s.ServeConnect), ReadHeaderTimeout: 10 * time.Second})
+	}
+
+	errs := make(chan error, len(servers))
+	for _, srv := range servers {
+		srv.BaseContext = func(net.Listener) context.Context { return ctx }
+		go func() {
+			logger.Info("starting tunnel server", "address", srv.Addr)
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+	for _, srv := range servers {
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		_ = srv.Shutdown(shutdownCtx)
+		cancel()
+	}
+	s.Registry.Close()
+	return err
+}
+
+// ServeAgent authenticates the agent, upgrades the connection and registers it as tunnel of the cluster.
+func (s *Server) ServeAgent(w http.ResponseWriter, r *http.Request) {
+	cluster := types.NamespacedName{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")}
+	logger := log.FromContext(r.Context()).WithName("tunnel").WithValues("cluster", cluster.String())
+
+	if !strings.EqualFold(r.Header.Get("Upgrade"), UpgradeProtocol) {
+		http.Error(w, "expected upgrade to "+UpgradeProtocol, http.StatusUpgradeRequired)
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || !s.authenticate(r.Context(), cluster, token) {
+		logger.Info("rejected agent connection", "remoteAddr", r.RemoteAddr)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection does not support upgrades", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		logger.Error(err, "failed to hijack agent connection")
+		return
+	}
+	if _, err := fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: %s\r\n\r\n", UpgradeProtocol); err != nil {
+		_ = conn.Close()
+		return
+	}
+	if err := rw.Flush(); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	nc := newNotifyConn(newBufferedConn(conn, rw.Reader))
+	transport := &http2.Transport{
+		ReadIdleTimeout: healthCheckInterval,
+		PingTimeout:     healthCheckTimeout,
+	}
+	clientConn, err := transport.NewClientConn(nc)
+	if err != nil {
+		logger.Error(err, "failed to establish tunnel")
+		_ = nc.Close()
+		return
+	}
+	ac := &agentConnection{clientConn: clientConn, conn: nc, connectedSince: time.Now()}
+	s.Registry.register(cluster, ac)
+	logger.Info("cluster agent connected", "remoteAddr", r.RemoteAddr)
+	go func() {
+		<-nc.done
+		s.Registry.unregister(cluster, ac)
+		logger.Info("cluster agent disconnected")
+	}()
+}
+
+// authenticate compares the token with the agent token of the cluster secret.
+// Only secrets of clusters with access mode agent are considered.
+func (s *Server) authenticate(ctx context.Context, cluster types.NamespacedName, token string) bool {
+	if token == "" {
+		return false
+	}
+	var secret = new(corev1.Secret)
+	if err := s.Client.Get(ctx, cluster, secret); err != nil {
+		return false
+	}
+	if secret.GetAnnotations()[greenhouseapis.SecretClusterAccessModeAnnotation] != string(greenhousev1alpha1.ClusterAccessModeAgent) {
+		return false
+	}
+	expected := secret.Data[greenhouseapis.AgentTokenKey]
+	return len(expected) > 0 && subtle.ConstantTimeCompare(expected, []byte(token)) == 1
+}
+
+// RemoveEndpoint removes PodIP from the EndpointSlice of the tunnel Service if it is the published endpoint.
+// It must be called on startup, as the endpoint published by a previous leader outlives the restart of its container.
+func (s *Server) RemoveEndpoint(ctx context.Context) error {
+	if s.EndpointSlice.Name == "" {
+		return nil
+	}
+	// The test operation ensures the endpoint of a newer leader is not removed. A failing test is reported as invalid patch.
+	err := s.patchEndpointSlice(ctx, []map[string]any{
+		{"op": "test", "path": "/endpoints/0/addresses/0", "value": s.PodIP},
+		{"op": "replace", "path": "/endpoints", "value": []discoveryv1.Endpoint{}},
+	})
+	if apierrors.IsInvalid(err) {
+		return nil
+	}
+	return err
+}
+
+// publishEndpoint replaces the endpoints in the EndpointSlice of the tunnel Service with PodIP.
+func (s *Server) publishEndpoint(ctx context.Context) error {
+	if s.EndpointSlice.Name == "" {
+		return nil
+	}
+	return s.patchEndpointSlice(ctx, []map[string]any{
+		{"op": "replace", "path": "/endpoints", "value": []discoveryv1.Endpoint{{
+			Addresses:  []string{s.PodIP},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+		}}},
+	})
+}
+
+// patchEndpointSlice applies the JSON patch to the EndpointSlice of the tunnel Service.
+// The EndpointSlice is patched without reading it, so the server is only allowed to patch this EndpointSlice.
+func (s *Server) patchEndpointSlice(ctx context.Context, operations []map[string]any) error {
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return err
+	}
+	endpointSlice := &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: s.EndpointSlice.Namespace, Name: s.EndpointSlice.Name}}
+	return s.Client.Patch(ctx, endpointSlice, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// ServeConnect dials the cluster given by the ClusterHeader through its tunnel and connects the client with it.
+// Clients authenticate with a ServiceAccount token in the Proxy-Authorization header and must be allowed to read the secret of the cluster.
+func (s *Server) ServeConnect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	namespace, name, ok := strings.Cut(r.Header.Get(ClusterHeader), "/")
+	if !ok || namespace == "" || name == "" {
+		http.Error(w, "missing or invalid "+ClusterHeader+" header", http.StatusBadRequest)
+		return
+	}
+	cluster := types.NamespacedName{Namespace: namespace, Name: name}
+	token, ok := strings.CutPrefix(r.Header.Get("Proxy-Authorization"), "Bearer ")
+	if !ok || !s.authorizeProxy(r.Context(), cluster, token) {
+		log.FromContext(r.Context()).WithName("tunnel").Info("rejected proxy connection", "cluster", cluster.String(), "remoteAddr", r.RemoteAddr)
+		w.Header().Set("Proxy-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusProxyAuthRequired)
+		return
+	}
+	upstream, err := s.Registry.DialContext(r.Context(), cluster, r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer upstream.Close()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection does not support hijacking", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if _, err := rw.WriteString("HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		return
+	}
+	if err := rw.Flush(); err != nil {
+		return
+	}
+	pipe(newBufferedConn(conn, rw.Reader), upstream)
+}
+
+// authorizeProxy authenticates the ServiceAccount token of a proxy client and checks whether it may read the secret of the cluster.
+// Only components with access to the credentials of the cluster may dial it through the tunnel.
+func (s *Server) authorizeProxy(ctx context.Context, cluster types.NamespacedName, token string) bool {
+	if token == "" {
+		return false
+	}
+	tokenReview := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := s.Client.Create(ctx, tokenReview); err != nil || !tokenReview.Status.Authenticated {
+		return false
+	}
+	user := tokenReview.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	accessReview := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: cluster.Namespace,
+				Verb:      "get",
+				Resource:  "secrets",
+				Name:      cluster.Name,
+			},
+		},
+	}
+	if err := s.Client.Create(ctx, accessReview); err != nil {
+		return false
+	}
+	return accessReview.Status.Allowed
+}
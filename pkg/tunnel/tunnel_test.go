@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Greenhouse contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tunnel_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	greenhouseapis "github.com/cloudoperators/greenhouse/pkg/apis"
+	greenhousev1alpha1 "github.com/cloudoperators/greenhouse/pkg/apis/greenhouse/v1alpha1"
+	"github.com/cloudoperators/greenhouse/pkg/test"
+	"github.com/cloudoperators/greenhouse/pkg/tunnel"
+)
+
+var _ = Describe("Cluster tunnel", func() {
+	const (
+		agentToken = "agent-token"
+		proxyToken = "proxy-token"
+		// proxyUser is the only user allowed to read the secret of the cluster.
+		proxyUser = "system:serviceaccount:test-org:service-proxy"
+	)
+
+	var (
+		cluster = types.NamespacedName{Namespace: "test-org", Name: "test-cluster"}
+
+		apiServer    *httptest.Server
+		tunnelServer *tunnel.Server
+		agentServer  *httptest.Server
+		cancelAgent  context.CancelFunc
+		agentDone    chan struct{}
+
+		startAgent = func(token string) {
+			var ctx context.Context
+			ctx, cancelAgent = context.WithCancel(context.Background())
+			agent := &tunnel.Agent{
+				URL:              agentServer.URL,
+				Cluster:          cluster,
+				Token:            token,
+				APIServerAddress: apiServer.Listener.Addr().String(),
+				Backoff:          &wait.Backoff{Duration: 100 * time.Millisecond, Steps: 1},
+			}
+			agentDone = make(chan struct{})
+			go func() {
+				defer close(agentDone)
+				_ = agent.Run(ctx)
+			}()
+		}
+		// apiServerClient returns an HTTP client dialing the API server with the given dial function.
+		apiServerClient = func(dial func(ctx context.Context, network, address string) (net.Conn, error)) *http.Client {
+			transport := apiServer.Client().Transport.(*http.Transport).Clone() //nolint:errcheck
+			transport.DialContext = dial
+			return &http.Client{Transport: transport, Timeout: 5 * time.Second}
+		}
+		// apiServerURL uses a host that is not resolvable to ensure requests are routed through the tunnel.
+		apiServerURL = func() string {
+			u, err := url.Parse(apiServer.URL)
+			Expect(err).ToNot(HaveOccurred())
+			u.Host = "kubernetes.default.svc:443"
+			return u.String() + "/version"
+		}
+	)
+
+	BeforeEach(func() {
+		apiServer = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "remote api server")
+		}))
+		// The certificate of the test server is only valid for example.com and the loopback address.
+		apiServer.Client().Transport.(*http.Transport).TLSClientConfig.ServerName = "example.com" //nolint:errcheck
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        cluster.Name,
+				Namespace:   cluster.Namespace,
+				Annotations: map[string]string{greenhouseapis.SecretClusterAccessModeAnnotation: string(greenhousev1alpha1.ClusterAccessModeAgent)},
+			},
+			Type: greenhouseapis.SecretTypeKubeConfig,
+			Data: map[string][]byte{greenhouseapis.AgentTokenKey: []byte(agentToken)},
+		}
+		endpointSlice := &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Name: tunnel.ServiceName, Namespace: "greenhouse"}, AddressType: discoveryv1.AddressTypeIPv4}
+		// The fake client does not review tokens and access. Only the proxy token of the proxy user is accepted.
+		reviews := interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				switch review := obj.(type) {
+				case *authenticationv1.TokenReview:
+					if review.Spec.Token == proxyToken {
+						review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: proxyUser}}
+					}
+					return nil
+				case *authorizationv1.SubjectAccessReview:
+					attrs := review.Spec.ResourceAttributes
+					review.Status.Allowed = review.Spec.User == proxyUser && attrs.Resource == "secrets" && attrs.Namespace == cluster.Namespace && attrs.Name == cluster.Name
+					return nil
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+			// The API server reports failing JSON patch test operations as invalid, the fake client does not.
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				err := c.Patch(ctx, obj, patch, opts...)
+				if err != nil && patch.Type() == types.JSONPatchType && strings.Contains(err.Error(), "test failed") {
+					return apierrors.NewInvalid(obj.GetObjectKind().GroupVersionKind().GroupKind(), obj.GetName(), nil)
+				}
+				return err
+			},
+		}
+		tunnelServer = &tunnel.Server{
+			Client:        fake.NewClientBuilder().WithScheme(test.GreenhouseV1Alpha1Scheme()).WithObjects(secret, endpointSlice).WithInterceptorFuncs(reviews).Build(),
+			Registry:      tunnel.NewRegistry(),
+			EndpointSlice: client.ObjectKeyFromObject(endpointSlice),
+			PodIP:         "10.0.0.1",
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /agent/{namespace}/{name}", tunnelServer.ServeAgent)
+		agentServer = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		if cancelAgent != nil {
+			cancelAgent()
+			tunnelServer.Registry.Close()
+			Eventually(agentDone).Should(BeClosed(), "the agent should stop")
+		}
+		agentServer.Close()
+		apiServer.Close()
+	})
+
+	It("should route connections to the API server through the agent", func() {
+		startAgent(agentToken)
+		Eventually(func() bool {
+			_, connected := tunnelServer.Registry.ConnectedSince(cluster)
+			return connected
+		}).Should(BeTrue(), "the agent should connect")
+		Expect(tunnelServer.Registry.Ping(context.Background(), cluster)).To(Succeed(), "the tunnel should answer pings")
+
+		dialer := &tunnel.Dialer{Registry: tunnelServer.Registry}
+		resp, err := apiServerClient(dialer.DialFunc(cluster)).Get(apiServerURL())
+		Expect(err).ToNot(HaveOccurred(), "there should be no error requesting the API server through the tunnel")
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error reading the response")
+		Expect(string(body)).To(Equal("remote api server"), "the response should be returned by the API server")
+	})
+
+	It("should route connections through the proxy endpoint", func() {
+		startAgent(agentToken)
+		Eventually(func() bool {
+			_, connected := tunnelServer.Registry.ConnectedSince(cluster)
+			return connected
+		}).Should(BeTrue(), "the agent should connect")
+
+		proxy := httptest.NewServer(http.HandlerFunc(tunnelServer.ServeConnect))
+		defer proxy.Close()
+
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte(proxyToken), 0o600)).To(Succeed(), "there should be no error writing the token file")
+		dialer := &tunnel.Dialer{ProxyAddress: proxy.Listener.Addr().String(), TokenFile: tokenFile}
+		resp, err := apiServerClient(dialer.DialFunc(cluster)).Get(apiServerURL())
+		Expect(err).ToNot(HaveOccurred(), "there should be no error requesting the API server through the proxy")
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred(), "there should be no error reading the response")
+		Expect(string(body)).To(Equal("remote api server"), "the response should be returned by the API server")
+	})
+
+	It("should reject proxy clients that are not allowed to access the cluster", func() {
+		startAgent(agentToken)
+		Eventually(func() bool {
+			_, connected := tunnelServer.Registry.ConnectedSince(cluster)
+			return connected
+		}).Should(BeTrue(), "the agent should connect")
+
+		proxy := httptest.NewServer(http.HandlerFunc(tunnelServer.ServeConnect))
+		defer proxy.Close()
+
+		dialer := &tunnel.Dialer{ProxyAddress: proxy.Listener.Addr().String()}
+		_, err := dialer.DialContext(context.Background(), cluster, "kubernetes.default.svc:443")
+		Expect(err).To(MatchError(ContainSubstring("407")), "a client without token should be rejected")
+
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte("invalid"), 0o600)).To(Succeed(), "there should be no error writing the token file")
+		dialer.TokenFile = tokenFile
+		_, err = dialer.DialContext(context.Background(), cluster, "kubernetes.default.svc:443")
+		Expect(err).To(MatchError(ContainSubstring("407")), "a client with an invalid token should be rejected")
+	})
+
+	It("should publish the endpoint of the leader while the server runs", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		tunnelServer.AgentBindAddress = "127.0.0.1:0"
+		done := make(chan error)
+		go func() {
+			done <- tunnelServer.Start(ctx)
+		}()
+		endpointSlice := &discoveryv1.EndpointSlice{}
+		Eventually(func(g Gomega) {
+			g.Expect(tunnelServer.Client.Get(context.Background(), tunnelServer.EndpointSlice, endpointSlice)).To(Succeed(), "there should be no error getting the EndpointSlice")
+			g.Expect(endpointSlice.Endpoints).To(ConsistOf(HaveField("Addresses", ConsistOf(tunnelServer.PodIP))), "the IP of the leader should be the only endpoint")
+		}).Should(Succeed(), "the endpoint of the leader should be published")
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()), "the server should stop without error")
+		Expect(tunnelServer.Client.Get(context.Background(), tunnelServer.EndpointSlice, endpointSlice)).To(Succeed(), "there should be no error getting the EndpointSlice")
+		Expect(endpointSlice.Endpoints).To(BeEmpty(), "the endpoint should be removed once the server stopped")
+	})
+
+	It("should not remove the endpoint of another leader", func() {
+		endpointSlice := &discoveryv1.EndpointSlice{}
+		Expect(tunnelServer.Client.Get(context.Background(), tunnelServer.EndpointSlice, endpointSlice)).To(Succeed(), "there should be no error getting the EndpointSlice")
+		endpointSlice.Endpoints = []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.2"}}}
+		Expect(tunnelServer.Client.Update(context.Background(), endpointSlice)).To(Succeed(), "there should be no error publishing the endpoint of another leader")
+
+		Expect(tunnelServer.RemoveEndpoint(context.Background())).To(Succeed(), "there should be no error removing the endpoint")
+		Expect(tunnelServer.Client.Get(context.Background(), tunnelServer.EndpointSlice, endpointSlice)).To(Succeed(), "there should be no error getting the EndpointSlice")
+		Expect(endpointSlice.Endpoints).To(ConsistOf(HaveField("Addresses", ConsistOf("10.0.0.2"))), "the endpoint of the other leader should be kept")
+	})
+
+	It("should reject agents with an invalid token", func() {
+		startAgent("invalid")
+		Consistently(func() bool {
+			_, connected := tunnelServer.Registry.ConnectedSince(cluster)
+			return connected
+		}).WithTimeout(500*time.Millisecond).Should(BeFalse(), "the agent should not be connected")
+
+		_, err := tunnelServer.Registry.DialContext(context.Background(), cluster, "kubernetes.default.svc:443")
+		Expect(err).To(MatchError(tunnel.ErrNotConnected), "dialing a cluster without agent should fail")
+	})
+})